@@ -0,0 +1,384 @@
+// Package anthropictest provides a local fake of the Anthropic Messages API.
+//
+// A Server replays scripted responses to POST /v1/messages, as JSON or as an
+// SSE stream when the request sets "stream": true, and records every request
+// it receives. Point server.Config.BaseURL (or option.WithBaseURL) at
+// Server.URL to run the agent end to end without network access:
+//
+//	fake := anthropictest.NewServer(
+//		anthropictest.ToolUse("get_balance", map[string]interface{}{}),
+//		anthropictest.Text("You have $100."),
+//	)
+//	defer fake.Close()
+//
+//	srv, _ := server.New(server.Config{AnthropicKey: "test", BaseURL: fake.URL})
+//
+// The Anthropic client retries 429, 529 and 5xx responses by default; scripted
+// errors set "x-should-retry: false" unless Response.Retry is true.
+package anthropictest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"unicode/utf8"
+)
+
+// DefaultModel is reported in responses whose request named no model.
+const DefaultModel = "claude-test"
+
+// streamChunkSize is the number of runes per text_delta when streaming.
+const streamChunkSize = 8
+
+// Response is one scripted reply.
+type Response struct {
+	// Content blocks of a successful reply.
+	Content []Block
+
+	// StopReason defaults to "tool_use" if Content contains a tool_use block,
+	// otherwise "end_turn".
+	StopReason string
+
+	// Usage reported for the reply.
+	Usage Usage
+
+	// Status, when 400 or above, makes this an error reply with ErrorType and
+	// ErrorMessage in the Anthropic error format.
+	Status       int
+	ErrorType    string
+	ErrorMessage string
+
+	// Retry lets the client's retry policy apply to an error reply.
+	// By default error replies tell the client not to retry.
+	Retry bool
+}
+
+// Block is a content block in a scripted reply.
+type Block struct {
+	Type  string      `json:"type"` // "text" or "tool_use"
+	Text  string      `json:"text,omitempty"`
+	ID    string      `json:"id,omitempty"`
+	Name  string      `json:"name,omitempty"`
+	Input interface{} `json:"input,omitempty"`
+}
+
+// MarshalJSON encodes the block in the Messages API shape for its type.
+func (b Block) MarshalJSON() ([]byte, error) {
+	if b.Type == "tool_use" {
+		return json.Marshal(map[string]interface{}{
+			"type": b.Type, "id": b.ID, "name": b.Name, "input": b.Input,
+		})
+	}
+	return json.Marshal(map[string]interface{}{"type": b.Type, "text": b.Text})
+}
+
+// Usage mirrors the Messages API usage object.
+type Usage struct {
+	InputTokens              int64 `json:"input_tokens"`
+	OutputTokens             int64 `json:"output_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+}
+
+// Text returns a reply with a single text block.
+func Text(text string) Response {
+	return Response{Content: []Block{TextBlock(text)}}
+}
+
+// ToolUse returns a reply that calls one tool.
+func ToolUse(name string, input interface{}) Response {
+	return Response{Content: []Block{ToolUseBlock("", name, input)}}
+}
+
+// Error returns an error reply, e.g. Error(429, "rate_limit_error", "slow down").
+func Error(status int, errorType, message string) Response {
+	return Response{Status: status, ErrorType: errorType, ErrorMessage: message}
+}
+
+// RateLimited returns a 429 rate_limit_error reply.
+func RateLimited() Response {
+	return Error(http.StatusTooManyRequests, "rate_limit_error", "Number of requests has exceeded your rate limit")
+}
+
+// Overloaded returns a 529 overloaded_error reply.
+func Overloaded() Response {
+	return Error(529, "overloaded_error", "Overloaded")
+}
+
+// ServerError returns a 500 api_error reply.
+func ServerError() Response {
+	return Error(http.StatusInternalServerError, "api_error", "Internal server error")
+}
+
+// TextBlock returns a text content block.
+func TextBlock(text string) Block {
+	return Block{Type: "text", Text: text}
+}
+
+// ToolUseBlock returns a tool_use content block. If id is empty, one is
+// assigned when the reply is served.
+func ToolUseBlock(id, name string, input interface{}) Block {
+	if input == nil {
+		input = map[string]interface{}{}
+	}
+	return Block{Type: "tool_use", ID: id, Name: name, Input: input}
+}
+
+// WithUsage returns r with usage set.
+func (r Response) WithUsage(u Usage) Response {
+	r.Usage = u
+	return r
+}
+
+// Request is a recorded request to the fake.
+type Request struct {
+	Header http.Header     `json:"-"`
+	Body   json.RawMessage `json:"-"`
+
+	// Decoded fields of the body.
+	Model     string            `json:"model"`
+	MaxTokens int64             `json:"max_tokens"`
+	Stream    bool              `json:"stream"`
+	System    json.RawMessage   `json:"system"`
+	Messages  []json.RawMessage `json:"messages"`
+	Tools     []json.RawMessage `json:"tools"`
+}
+
+// Server is a fake Messages API. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	responses []Response
+	requests  []Request
+	nextID    int
+}
+
+// NewServer starts a fake serving responses in order.
+// The caller should Close it when done.
+func NewServer(responses ...Response) *Server {
+	s := &Server{responses: responses}
+	s.Server = httptest.NewServer(s.Handler())
+	return s
+}
+
+// Enqueue appends scripted responses.
+func (s *Server) Enqueue(responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses = append(s.responses, responses...)
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Pending returns the number of scripted responses not yet served.
+func (s *Server) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.responses)
+}
+
+// Handler returns the fake's http.Handler, for mounting without NewServer.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/messages", s.handleMessages)
+	return mux
+}
+
+func (s *Server) handleMessages(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, Error(http.StatusBadRequest, "invalid_request_error", "failed to read body"))
+		return
+	}
+	req := Request{Header: r.Header.Clone(), Body: body}
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeError(w, Error(http.StatusBadRequest, "invalid_request_error", "invalid JSON body"))
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	if len(s.responses) == 0 {
+		s.mu.Unlock()
+		writeError(w, Error(http.StatusInternalServerError, "api_error", "anthropictest: no scripted response"))
+		return
+	}
+	resp := s.responses[0]
+	s.responses = s.responses[1:]
+	s.nextID++
+	id := s.nextID
+	s.mu.Unlock()
+
+	if resp.Status >= 400 {
+		writeError(w, resp)
+		return
+	}
+
+	msg := s.message(id, req.Model, resp)
+	if req.Stream {
+		writeStream(w, msg)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(msg)
+}
+
+// message is the Messages API response object.
+type message struct {
+	ID           string  `json:"id"`
+	Type         string  `json:"type"`
+	Role         string  `json:"role"`
+	Model        string  `json:"model"`
+	Content      []Block `json:"content"`
+	StopReason   string  `json:"stop_reason"`
+	StopSequence *string `json:"stop_sequence"`
+	Usage        Usage   `json:"usage"`
+}
+
+func (s *Server) message(id int, model string, resp Response) message {
+	if model == "" {
+		model = DefaultModel
+	}
+
+	content := make([]Block, len(resp.Content))
+	stopReason := "end_turn"
+	for i, block := range resp.Content {
+		if block.Type == "tool_use" {
+			stopReason = "tool_use"
+			if block.ID == "" {
+				block.ID = fmt.Sprintf("toolu_%03d_%d", id, i)
+			}
+		}
+		content[i] = block
+	}
+	if resp.StopReason != "" {
+		stopReason = resp.StopReason
+	}
+
+	return message{
+		ID:         fmt.Sprintf("msg_%03d", id),
+		Type:       "message",
+		Role:       "assistant",
+		Model:      model,
+		Content:    content,
+		StopReason: stopReason,
+		Usage:      resp.Usage,
+	}
+}
+
+// writeStream writes msg as the Messages API SSE event sequence.
+func writeStream(w http.ResponseWriter, msg message) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+
+	event := func(name string, data interface{}) {
+		b, _ := json.Marshal(data)
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, b)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	// Output tokens are reported by message_delta
+	startUsage := msg.Usage
+	startUsage.OutputTokens = 0
+	event("message_start", map[string]interface{}{
+		"type": "message_start",
+		"message": map[string]interface{}{
+			"id":            msg.ID,
+			"type":          msg.Type,
+			"role":          msg.Role,
+			"model":         msg.Model,
+			"content":       []Block{},
+			"stop_reason":   nil,
+			"stop_sequence": nil,
+			"usage":         startUsage,
+		},
+	})
+	event("ping", map[string]string{"type": "ping"})
+
+	for i, block := range msg.Content {
+		switch block.Type {
+		case "tool_use":
+			event("content_block_start", map[string]interface{}{
+				"type":  "content_block_start",
+				"index": i,
+				"content_block": map[string]interface{}{
+					"type": "tool_use", "id": block.ID, "name": block.Name, "input": map[string]interface{}{},
+				},
+			})
+			input, _ := json.Marshal(block.Input)
+			event("content_block_delta", map[string]interface{}{
+				"type":  "content_block_delta",
+				"index": i,
+				"delta": map[string]string{"type": "input_json_delta", "partial_json": string(input)},
+			})
+		default:
+			event("content_block_start", map[string]interface{}{
+				"type":          "content_block_start",
+				"index":         i,
+				"content_block": map[string]string{"type": "text", "text": ""},
+			})
+			for _, chunk := range chunkText(block.Text, streamChunkSize) {
+				event("content_block_delta", map[string]interface{}{
+					"type":  "content_block_delta",
+					"index": i,
+					"delta": map[string]string{"type": "text_delta", "text": chunk},
+				})
+			}
+		}
+		event("content_block_stop", map[string]interface{}{"type": "content_block_stop", "index": i})
+	}
+
+	event("message_delta", map[string]interface{}{
+		"type":  "message_delta",
+		"delta": map[string]interface{}{"stop_reason": msg.StopReason, "stop_sequence": nil},
+		"usage": map[string]int64{"output_tokens": msg.Usage.OutputTokens},
+	})
+	event("message_stop", map[string]string{"type": "message_stop"})
+}
+
+// chunkText splits text into pieces of at most n runes.
+func chunkText(text string, n int) []string {
+	var chunks []string
+	for len(text) > 0 {
+		end, count := 0, 0
+		for end < len(text) && count < n {
+			_, size := utf8.DecodeRuneInString(text[end:])
+			end += size
+			count++
+		}
+		chunks = append(chunks, text[:end])
+		text = text[end:]
+	}
+	return chunks
+}
+
+// writeError writes resp as an Anthropic error response.
+func writeError(w http.ResponseWriter, resp Response) {
+	w.Header().Set("Content-Type", "application/json")
+	if resp.Retry {
+		w.Header().Set("Retry-After-Ms", "1")
+	} else {
+		w.Header().Set("x-should-retry", "false")
+	}
+	w.WriteHeader(resp.Status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"type": "error",
+		"error": map[string]string{
+			"type":    resp.ErrorType,
+			"message": resp.ErrorMessage,
+		},
+	})
+}
@@ -0,0 +1,136 @@
+package anthropictest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+)
+
+func newClient(fake *Server) anthropic.Client {
+	return anthropic.NewClient(option.WithAPIKey("test-key"), option.WithBaseURL(fake.URL))
+}
+
+func testParams() anthropic.MessageNewParams {
+	return anthropic.MessageNewParams{
+		Model:     anthropic.ModelClaudeSonnet4_20250514,
+		MaxTokens: 100,
+		Messages: []anthropic.MessageParam{
+			anthropic.NewUserMessage(anthropic.NewTextBlock("What's my balance?")),
+		},
+	}
+}
+
+func TestJSONResponse(t *testing.T) {
+	fake := NewServer(ToolUse("get_balance", map[string]interface{}{"currency": "USD"}).WithUsage(Usage{
+		InputTokens: 10, OutputTokens: 5, CacheReadInputTokens: 7,
+	}))
+	defer fake.Close()
+
+	client := newClient(fake)
+	msg, err := client.Messages.New(context.Background(), testParams())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if msg.StopReason != anthropic.StopReasonToolUse {
+		t.Errorf("expected tool_use stop reason, got %s", msg.StopReason)
+	}
+	if len(msg.Content) != 1 || msg.Content[0].Name != "get_balance" || string(msg.Content[0].Input) != `{"currency":"USD"}` {
+		t.Errorf("unexpected content: %+v", msg.Content)
+	}
+	if msg.Usage.CacheReadInputTokens != 7 || msg.Usage.OutputTokens != 5 {
+		t.Errorf("unexpected usage: %+v", msg.Usage)
+	}
+
+	reqs := fake.Requests()
+	if len(reqs) != 1 || reqs[0].Stream || len(reqs[0].Messages) != 1 || reqs[0].Header.Get("X-Api-Key") != "test-key" {
+		t.Errorf("unexpected recorded request: %+v", reqs)
+	}
+}
+
+func TestStreamingResponse(t *testing.T) {
+	fake := NewServer(Response{
+		Content: []Block{
+			TextBlock("Let me check your balance for you."),
+			ToolUseBlock("toolu_1", "get_balance", nil),
+		},
+		Usage: Usage{InputTokens: 20, OutputTokens: 12},
+	})
+	defer fake.Close()
+
+	client := newClient(fake)
+	stream := client.Messages.NewStreaming(context.Background(), testParams())
+	defer stream.Close()
+
+	var msg anthropic.Message
+	var chunks int
+	for stream.Next() {
+		event := stream.Current()
+		if err := msg.Accumulate(event); err != nil {
+			t.Fatalf("Accumulate failed: %v", err)
+		}
+		if delta, ok := event.AsAny().(anthropic.ContentBlockDeltaEvent); ok {
+			if _, ok := delta.Delta.AsAny().(anthropic.TextDelta); ok {
+				chunks++
+			}
+		}
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+
+	if chunks < 2 {
+		t.Errorf("expected text in several chunks, got %d", chunks)
+	}
+	if len(msg.Content) != 2 || msg.Content[0].Text != "Let me check your balance for you." {
+		t.Fatalf("unexpected content: %+v", msg.Content)
+	}
+	if msg.Content[1].ID != "toolu_1" || string(msg.Content[1].Input) != "{}" {
+		t.Errorf("unexpected tool_use block: %+v", msg.Content[1])
+	}
+	if msg.StopReason != anthropic.StopReasonToolUse || msg.Usage.OutputTokens != 12 || msg.Usage.InputTokens != 20 {
+		t.Errorf("unexpected stop reason or usage: %s %+v", msg.StopReason, msg.Usage)
+	}
+	if !fake.Requests()[0].Stream {
+		t.Error("expected recorded request to be streaming")
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	fake := NewServer(RateLimited(), Overloaded(), ServerError())
+	defer fake.Close()
+
+	client := newClient(fake)
+	for _, want := range []int{http.StatusTooManyRequests, 529, http.StatusInternalServerError} {
+		_, err := client.Messages.New(context.Background(), testParams())
+		var apiErr *anthropic.Error
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != want {
+			t.Errorf("expected %d error, got %v", want, err)
+		}
+	}
+
+	// Errors are not retried, so each consumed exactly one response
+	if len(fake.Requests()) != 3 || fake.Pending() != 0 {
+		t.Errorf("expected 3 requests and no pending responses, got %d and %d", len(fake.Requests()), fake.Pending())
+	}
+}
+
+func TestRetriedError(t *testing.T) {
+	overloaded := Overloaded()
+	overloaded.Retry = true
+	fake := NewServer(overloaded, Text("Hello"))
+	defer fake.Close()
+
+	client := newClient(fake)
+	msg, err := client.Messages.New(context.Background(), testParams())
+	if err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if msg.Content[0].Text != "Hello" || len(fake.Requests()) != 2 {
+		t.Errorf("unexpected result after retry: %+v, %d requests", msg.Content, len(fake.Requests()))
+	}
+}
@@ -24,6 +24,14 @@ type ToolExecutor interface {
 	Cancel(ctx context.Context, userID, confirmationID string) error
 }
 
+// HealthChecker is an optional interface for dependencies that can report
+// whether they are usable. Executors and stores implement it so the server's
+// /readyz endpoint can probe them; those that don't are assumed healthy.
+type HealthChecker interface {
+	// CheckHealth returns an error if the dependency is unreachable or unusable.
+	CheckHealth(ctx context.Context) error
+}
+
 // ExecuteRequest contains the parameters for tool execution.
 type ExecuteRequest struct {
 	// UserID is the authenticated user making the request.
@@ -37,6 +45,10 @@ type ExecuteRequest struct {
 
 	// RequestID for tracing/logging.
 	RequestID string `json:"request_id,omitempty"`
+
+	// IdempotencyKey lets executors safely retry a write. Writes without one
+	// are never retried.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
 }
 
 // ExecuteResponse contains the result of tool execution.
@@ -107,10 +119,11 @@ func (t *ExecutorTool) RequiresConfirmation() bool {
 // Execute runs the tool via the ToolExecutor.
 func (t *ExecutorTool) Execute(ctx context.Context, params *ToolParams) (*ToolResult, error) {
 	req := &ExecuteRequest{
-		UserID:    params.UserID,
-		Tool:      t.definition.ToolName,
-		Input:     params.Input,
-		RequestID: params.RequestID,
+		UserID:         params.UserID,
+		Tool:           t.definition.ToolName,
+		Input:          params.Input,
+		RequestID:      params.RequestID,
+		IdempotencyKey: params.IdempotencyKey,
 	}
 
 	var resp *ExecuteResponse
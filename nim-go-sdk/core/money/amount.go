@@ -0,0 +1,269 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// ErrCurrencyMismatch is returned when combining amounts in different
+// currencies.
+var ErrCurrencyMismatch = errors.New("money: currency mismatch")
+
+// DefaultPrecision is the number of minor-unit digits of currencies that
+// aren't registered, such as USD cents.
+const DefaultPrecision = 2
+
+var (
+	precisionMu sync.RWMutex
+	precisions  = map[string]int32{
+		// ISO 4217 currencies without minor units
+		"CLP": 0, "ISK": 0, "JPY": 0, "KRW": 0, "UGX": 0, "VND": 0, "XAF": 0, "XOF": 0,
+		// ISO 4217 currencies with three-digit minor units
+		"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	}
+)
+
+// RegisterCurrency sets the number of minor-unit digits of a currency,
+// e.g. for a token with 6 decimals.
+func RegisterCurrency(code string, precision int32) {
+	precisionMu.Lock()
+	defer precisionMu.Unlock()
+	precisions[strings.ToUpper(code)] = precision
+}
+
+// Precision returns the number of minor-unit digits of a currency.
+// Defaults to DefaultPrecision.
+func Precision(currency string) int32 {
+	precisionMu.RLock()
+	defer precisionMu.RUnlock()
+	if p, ok := precisions[strings.ToUpper(currency)]; ok {
+		return p
+	}
+	return DefaultPrecision
+}
+
+// Amount is a Decimal in a currency. Currency codes are upper case.
+type Amount struct {
+	Value    Decimal
+	Currency string
+}
+
+// New returns value in currency. The value is kept exactly; use Round to
+// bring it to the currency's precision.
+func New(value Decimal, currency string) Amount {
+	return Amount{Value: value, Currency: strings.ToUpper(currency)}
+}
+
+// Zero returns a zero amount in currency.
+func Zero(currency string) Amount {
+	return New(Decimal{}, currency)
+}
+
+// Parse parses an amount such as "12.50" in currency. It fails if the
+// value has more decimal places than the currency allows.
+func Parse(value, currency string) (Amount, error) {
+	if strings.TrimSpace(currency) == "" {
+		return Amount{}, errors.New("money: currency is required")
+	}
+	d, err := ParseDecimal(value)
+	if err != nil {
+		return Amount{}, err
+	}
+	a := New(d, currency)
+	if err := a.checkPrecision(); err != nil {
+		return Amount{}, err
+	}
+	return a, nil
+}
+
+// MustParse is like Parse but panics on error. Use it for constants.
+func MustParse(value, currency string) Amount {
+	a, err := Parse(value, currency)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// checkPrecision fails if a has more places than its currency allows.
+func (a Amount) checkPrecision() error {
+	if p := Precision(a.Currency); !a.Value.Round(p, RoundDown).Equal(a.Value) {
+		return fmt.Errorf("money: %s amounts have at most %d decimal places, got %s", a.Currency, p, a.Value)
+	}
+	return nil
+}
+
+// same checks that b is in a's currency.
+func (a Amount) same(b Amount) error {
+	if a.Currency != b.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, a.Currency, b.Currency)
+	}
+	return nil
+}
+
+// Add returns a + b.
+func (a Amount) Add(b Amount) (Amount, error) {
+	if err := a.same(b); err != nil {
+		return Amount{}, err
+	}
+	return Amount{Value: a.Value.Add(b.Value), Currency: a.Currency}, nil
+}
+
+// Sub returns a - b.
+func (a Amount) Sub(b Amount) (Amount, error) {
+	if err := a.same(b); err != nil {
+		return Amount{}, err
+	}
+	return Amount{Value: a.Value.Sub(b.Value), Currency: a.Currency}, nil
+}
+
+// Mul returns a × factor rounded to the currency's precision, e.g. to
+// apply a rate or a fee percentage.
+func (a Amount) Mul(factor Decimal, mode RoundingMode) Amount {
+	return Amount{Value: a.Value.Mul(factor).Round(Precision(a.Currency), mode), Currency: a.Currency}
+}
+
+// Neg returns -a.
+func (a Amount) Neg() Amount {
+	return Amount{Value: a.Value.Neg(), Currency: a.Currency}
+}
+
+// Abs returns |a|.
+func (a Amount) Abs() Amount {
+	return Amount{Value: a.Value.Abs(), Currency: a.Currency}
+}
+
+// Round returns a rounded to its currency's precision.
+func (a Amount) Round(mode RoundingMode) Amount {
+	return Amount{Value: a.Value.Round(Precision(a.Currency), mode), Currency: a.Currency}
+}
+
+// Cmp compares a and b, returning -1, 0 or +1.
+func (a Amount) Cmp(b Amount) (int, error) {
+	if err := a.same(b); err != nil {
+		return 0, err
+	}
+	return a.Value.Cmp(b.Value), nil
+}
+
+// Equal reports whether a and b have the same currency and value.
+func (a Amount) Equal(b Amount) bool {
+	return a.Currency == b.Currency && a.Value.Equal(b.Value)
+}
+
+// Sign returns -1, 0 or +1.
+func (a Amount) Sign() int {
+	return a.Value.Sign()
+}
+
+// IsZero reports whether a is zero.
+func (a Amount) IsZero() bool {
+	return a.Value.IsZero()
+}
+
+// Allocate splits a by ratios without losing minor units: each share is
+// rounded down to the currency's precision, and the remainder is handed
+// out one minor unit at a time from the first share. The shares always
+// add up to a.
+//
+//	money.MustParse("100.00", "USD").Allocate(1, 1, 1) // 33.34, 33.33, 33.33
+func (a Amount) Allocate(ratios ...int64) ([]Amount, error) {
+	if len(ratios) == 0 {
+		return nil, errors.New("money: at least one ratio is required")
+	}
+	total := new(big.Int)
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, errors.New("money: ratios must not be negative")
+		}
+		total.Add(total, big.NewInt(r))
+	}
+	if total.Sign() == 0 {
+		return nil, errors.New("money: ratios must not all be zero")
+	}
+
+	// Work in minor units of the currency
+	p := Precision(a.Currency)
+	if err := a.checkPrecision(); err != nil {
+		return nil, err
+	}
+	units := a.Value.rescale(p)
+	sign := units.Sign()
+	units.Abs(units)
+
+	shares := make([]*big.Int, len(ratios))
+	remainder := new(big.Int).Set(units)
+	for i, r := range ratios {
+		shares[i] = new(big.Int).Mul(units, big.NewInt(r))
+		shares[i].Quo(shares[i], total)
+		remainder.Sub(remainder, shares[i])
+	}
+	for i := 0; remainder.Sign() > 0; i = (i + 1) % len(shares) {
+		if ratios[i] == 0 {
+			continue
+		}
+		shares[i].Add(shares[i], big.NewInt(1))
+		remainder.Sub(remainder, big.NewInt(1))
+	}
+
+	out := make([]Amount, len(shares))
+	for i, s := range shares {
+		if sign < 0 {
+			s.Neg(s)
+		}
+		out[i] = Amount{Value: Decimal{coef: s, scale: p}, Currency: a.Currency}
+	}
+	return out, nil
+}
+
+// Split divides a into n shares that differ by at most one minor unit.
+func (a Amount) Split(n int) ([]Amount, error) {
+	if n <= 0 {
+		return nil, errors.New("money: n must be positive")
+	}
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return a.Allocate(ratios...)
+}
+
+// String returns the value at the currency's precision and the currency,
+// e.g. "12.50 USD". Use Format for locale-aware display.
+func (a Amount) String() string {
+	value := a.Value
+	if p := Precision(a.Currency); value.Scale() < p {
+		value = value.Round(p, RoundHalfEven)
+	}
+	return value.String() + " " + a.Currency
+}
+
+// amountJSON is the JSON form of an Amount.
+type amountJSON struct {
+	Amount   Decimal `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// MarshalJSON encodes a as {"amount": "12.50", "currency": "USD"}.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	value := a.Value
+	if p := Precision(a.Currency); value.Scale() < p {
+		value = value.Round(p, RoundHalfEven)
+	}
+	return json.Marshal(amountJSON{Amount: value, Currency: a.Currency})
+}
+
+// UnmarshalJSON decodes {"amount": "12.50", "currency": "USD"}. The amount
+// may also be a JSON number.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	var v amountJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*a = New(v.Amount, v.Currency)
+	return nil
+}
@@ -0,0 +1,414 @@
+// Package money provides exact decimal amounts for financial values.
+//
+// Decimal is an arbitrary-precision decimal number. It never goes through
+// float64, and marshals to JSON as a string so values keep their exact
+// digits on the wire:
+//
+//	price := money.MustParseDecimal("19.99")
+//	total := price.Mul(money.NewDecimal(3, 0)) // 59.97
+//
+// Amount pairs a Decimal with a currency, whose precision decides how
+// results are rounded and formatted:
+//
+//	rent, err := money.Parse("1500.00", "USD")
+//	shares, err := rent.Split(3) // 500.00, 500.00, 500.00
+//	money.Format(rent, "de-DE")  // "1.500,00 $"
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ErrDivisionByZero is returned when dividing by zero.
+var ErrDivisionByZero = errors.New("money: division by zero")
+
+// maxExponent bounds the exponent of parsed numbers such as "1e30".
+const maxExponent = 1000
+
+// RoundingMode selects how values are rounded to a number of places.
+type RoundingMode string
+
+// Rounding modes.
+const (
+	// RoundHalfEven rounds to the nearest value, and ties to the even
+	// neighbour ("banker's rounding").
+	RoundHalfEven RoundingMode = "half_even"
+
+	// RoundHalfUp rounds to the nearest value, and ties away from zero.
+	RoundHalfUp RoundingMode = "half_up"
+
+	// RoundHalfDown rounds to the nearest value, and ties towards zero.
+	RoundHalfDown RoundingMode = "half_down"
+
+	// RoundUp rounds away from zero.
+	RoundUp RoundingMode = "up"
+
+	// RoundDown rounds towards zero (truncation).
+	RoundDown RoundingMode = "down"
+
+	// RoundCeiling rounds towards positive infinity.
+	RoundCeiling RoundingMode = "ceiling"
+
+	// RoundFloor rounds towards negative infinity.
+	RoundFloor RoundingMode = "floor"
+)
+
+// RoundingModes lists every rounding mode.
+var RoundingModes = []RoundingMode{
+	RoundHalfEven, RoundHalfUp, RoundHalfDown, RoundUp, RoundDown, RoundCeiling, RoundFloor,
+}
+
+// Valid reports whether m is a known rounding mode.
+func (m RoundingMode) Valid() bool {
+	for _, mode := range RoundingModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// Decimal is an exact decimal number: coef × 10^-scale. The zero value is
+// 0. Decimals are immutable; operations return new values.
+type Decimal struct {
+	coef  *big.Int // nil means zero
+	scale int32    // digits after the decimal point, never negative
+}
+
+// NewDecimal returns coef × 10^-scale, e.g. NewDecimal(1999, 2) is 19.99.
+func NewDecimal(coef int64, scale int32) Decimal {
+	if scale < 0 {
+		c := new(big.Int).Mul(big.NewInt(coef), pow10(-scale))
+		return Decimal{coef: c}
+	}
+	return Decimal{coef: big.NewInt(coef), scale: scale}
+}
+
+// ParseDecimal parses a decimal such as "-1234.50", "+0.5" or "1.5e3".
+// Digits are kept exactly, including trailing zeros.
+func ParseDecimal(s string) (Decimal, error) {
+	text := strings.TrimSpace(s)
+	mantissa, exponent, hasExponent := text, "", false
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		mantissa, exponent, hasExponent = text[:i], text[i+1:], true
+	}
+
+	sign := ""
+	if mantissa != "" && (mantissa[0] == '-' || mantissa[0] == '+') {
+		if mantissa[0] == '-' {
+			sign = "-"
+		}
+		mantissa = mantissa[1:]
+	}
+	whole, frac, _ := strings.Cut(mantissa, ".")
+	if whole+frac == "" || !isDigits(whole) || !isDigits(frac) {
+		return Decimal{}, fmt.Errorf("money: invalid decimal %q", s)
+	}
+
+	coef, ok := new(big.Int).SetString(sign+whole+frac, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("money: invalid decimal %q", s)
+	}
+	scale := int64(len(frac))
+	if hasExponent {
+		exp, err := strconv.ParseInt(exponent, 10, 32)
+		if err != nil || exp > maxExponent || exp < -maxExponent {
+			return Decimal{}, fmt.Errorf("money: invalid decimal %q", s)
+		}
+		scale -= exp
+	}
+	if scale < 0 {
+		return Decimal{coef: coef.Mul(coef, pow10(int32(-scale)))}, nil
+	}
+	return Decimal{coef: coef, scale: int32(scale)}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics on error. Use it for
+// constants.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// DecimalFromRat rounds a rational number to places decimal places.
+// Negative places count as zero.
+func DecimalFromRat(r *big.Rat, places int32, mode RoundingMode) Decimal {
+	places = max(places, 0)
+	num := new(big.Int).Mul(r.Num(), pow10(places))
+	return Decimal{coef: roundQuo(num, r.Denom(), mode), scale: places}
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+var tenPowers = func() []*big.Int {
+	powers := make([]*big.Int, 40)
+	for i := range powers {
+		powers[i] = new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(i)), nil)
+	}
+	return powers
+}()
+
+// pow10 returns 10^n. The result must not be modified.
+func pow10(n int32) *big.Int {
+	if int(n) < len(tenPowers) {
+		return tenPowers[n]
+	}
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundQuo returns num / den rounded to an integer. den must be positive.
+func roundQuo(num, den *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	// Compare the discarded fraction with one half
+	half := new(big.Int).Abs(r)
+	cmp := half.Lsh(half, 1).Cmp(den)
+
+	var away bool
+	switch mode {
+	case RoundHalfUp:
+		away = cmp >= 0
+	case RoundHalfDown:
+		away = cmp > 0
+	case RoundUp:
+		away = true
+	case RoundDown:
+		away = false
+	case RoundCeiling:
+		away = num.Sign() > 0
+	case RoundFloor:
+		away = num.Sign() < 0
+	default:
+		away = cmp > 0 || cmp == 0 && q.Bit(0) == 1
+	}
+	if away {
+		q.Add(q, big.NewInt(int64(num.Sign())))
+	}
+	return q
+}
+
+func (d Decimal) int() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// rescale returns d's coefficient at a larger scale.
+func (d Decimal) rescale(scale int32) *big.Int {
+	c := new(big.Int).Set(d.int())
+	if scale > d.scale {
+		c.Mul(c, pow10(scale-d.scale))
+	}
+	return c
+}
+
+// align returns both coefficients at the larger of the two scales.
+func align(x, y Decimal) (a, b *big.Int, scale int32) {
+	scale = x.scale
+	if y.scale > scale {
+		scale = y.scale
+	}
+	return x.rescale(scale), y.rescale(scale), scale
+}
+
+// Add returns d + y.
+func (d Decimal) Add(y Decimal) Decimal {
+	a, b, scale := align(d, y)
+	return Decimal{coef: a.Add(a, b), scale: scale}
+}
+
+// Sub returns d - y.
+func (d Decimal) Sub(y Decimal) Decimal {
+	a, b, scale := align(d, y)
+	return Decimal{coef: a.Sub(a, b), scale: scale}
+}
+
+// Mul returns d × y exactly; the scale is the sum of both scales.
+func (d Decimal) Mul(y Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.int(), y.int()), scale: d.scale + y.scale}
+}
+
+// Quo returns d / y rounded to places decimal places.
+func (d Decimal) Quo(y Decimal, places int32, mode RoundingMode) (Decimal, error) {
+	if y.Sign() == 0 {
+		return Decimal{}, ErrDivisionByZero
+	}
+	return DecimalFromRat(new(big.Rat).Quo(d.Rat(), y.Rat()), places, mode), nil
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Abs returns |d|.
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.int()), scale: d.scale}
+}
+
+// Round returns d rounded to places decimal places. Values with fewer
+// places are padded, so Round(2) of 1.5 is 1.50. Negative places count as
+// zero.
+func (d Decimal) Round(places int32, mode RoundingMode) Decimal {
+	places = max(places, 0)
+	if places >= d.scale {
+		return Decimal{coef: d.rescale(places), scale: places}
+	}
+	return Decimal{coef: roundQuo(d.int(), pow10(d.scale-places), mode), scale: places}
+}
+
+// Cmp compares d and y, returning -1, 0 or +1.
+func (d Decimal) Cmp(y Decimal) int {
+	a, b, _ := align(d, y)
+	return a.Cmp(b)
+}
+
+// Equal reports whether d and y are the same number, ignoring scale.
+func (d Decimal) Equal(y Decimal) bool {
+	return d.Cmp(y) == 0
+}
+
+// Sign returns -1, 0 or +1.
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Rat returns d as a rational number.
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(d.int(), pow10(d.scale))
+}
+
+// Float64 returns the nearest float64, for display or statistics only.
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+// String returns d with all its digits, e.g. "-1234.50".
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+	sign := ""
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale == 0 {
+		return sign + digits
+	}
+	if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(d.scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// StringFixed returns d rounded half-even to exactly places decimal places.
+func (d Decimal) StringFixed(places int32) string {
+	return d.Round(places, RoundHalfEven).String()
+}
+
+// MarshalJSON encodes d as a JSON string, e.g. "12.50".
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes a JSON string or number. An empty string or null
+// decodes as zero.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		*d = Decimal{}
+		return nil
+	}
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		if text == "" {
+			*d = Decimal{}
+			return nil
+		}
+	}
+	parsed, err := ParseDecimal(text)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalText encodes d as text, for YAML and map keys.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes d from text.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value stores d in a database as a string, so no precision is lost.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan reads d from a database string, integer or float column.
+func (d *Decimal) Scan(src interface{}) error {
+	var text string
+	switch v := src.(type) {
+	case nil:
+		*d = Decimal{}
+		return nil
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	case int64:
+		*d = NewDecimal(v, 0)
+		return nil
+	case float64:
+		// The shortest representation that round-trips, e.g. 0.1 not 0.1000000000000000055
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("money: cannot scan %T into Decimal", src)
+	}
+	parsed, err := ParseDecimal(text)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
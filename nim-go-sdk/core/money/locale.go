@@ -0,0 +1,143 @@
+package money
+
+import (
+	"fmt"
+	"strings"
+)
+
+// localeFormat describes how a locale writes amounts.
+type localeFormat struct {
+	group   string // thousands separator
+	decimal string // decimal separator
+	suffix  bool   // symbol after the number, e.g. "12,50 €"
+	space   bool   // space between number and symbol
+}
+
+// locales maps lower-case BCP 47 tags to their formats. Lookups fall back
+// from "de-ch" to "de", and unknown locales use "en".
+var locales = map[string]localeFormat{
+	"en":    {group: ",", decimal: "."},
+	"ja":    {group: ",", decimal: "."},
+	"zh":    {group: ",", decimal: "."},
+	"ko":    {group: ",", decimal: "."},
+	"de":    {group: ".", decimal: ",", suffix: true, space: true},
+	"de-ch": {group: "'", decimal: ".", space: true},
+	"es":    {group: ".", decimal: ",", suffix: true, space: true},
+	"fr":    {group: " ", decimal: ",", suffix: true, space: true},
+	"it":    {group: ".", decimal: ",", suffix: true, space: true},
+	"nl":    {group: ".", decimal: ",", space: true},
+	"pt":    {group: ".", decimal: ",", space: true},
+	"pl":    {group: " ", decimal: ",", suffix: true, space: true},
+	"sv":    {group: " ", decimal: ",", suffix: true, space: true},
+}
+
+// symbols are the display symbols of common currencies. Other currencies
+// are shown by code.
+var symbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"CNY": "¥",
+	"INR": "₹",
+	"KRW": "₩",
+	"BRL": "R$",
+	"NGN": "₦",
+}
+
+// lookupLocale returns the format of a locale such as "en-US" or "de_DE".
+func lookupLocale(locale string) localeFormat {
+	tag := strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	if f, ok := locales[tag]; ok {
+		return f
+	}
+	if lang, _, ok := strings.Cut(tag, "-"); ok {
+		if f, ok := locales[lang]; ok {
+			return f
+		}
+	}
+	return locales["en"]
+}
+
+// Symbol returns the display symbol of a currency, or its code if it has
+// none.
+func Symbol(currency string) string {
+	currency = strings.ToUpper(currency)
+	if s, ok := symbols[currency]; ok {
+		return s
+	}
+	return currency
+}
+
+// Format returns a rounded to its currency's precision and written for a
+// locale, e.g. "$1,234.56" for en-US or "1.234,56 €" for de-DE.
+func Format(a Amount, locale string) string {
+	f := lookupLocale(locale)
+	value := a.Round(RoundHalfEven).Value
+	digits := value.Abs().String()
+	whole, frac, _ := strings.Cut(digits, ".")
+
+	var b strings.Builder
+	for i, c := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(f.group)
+		}
+		b.WriteRune(c)
+	}
+	if frac != "" {
+		b.WriteString(f.decimal)
+		b.WriteString(frac)
+	}
+	number := b.String()
+
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+	}
+	symbol := Symbol(a.Currency)
+	if symbol == "" {
+		return sign + number
+	}
+	sep := ""
+	if f.space || symbol == a.Currency {
+		sep = " "
+	}
+	if f.suffix {
+		return sign + number + sep + symbol
+	}
+	return sign + symbol + sep + number
+}
+
+// ParseLocale parses an amount written for a locale, such as "$1,234.56"
+// or "1.234,56 €". The currency symbol and code are optional.
+func ParseLocale(s, currency, locale string) (Amount, error) {
+	f := lookupLocale(locale)
+	text := strings.TrimSpace(s)
+	currency = strings.ToUpper(currency)
+	if symbol := Symbol(currency); symbol != currency {
+		text = strings.ReplaceAll(text, symbol, "")
+	}
+	text = strings.ReplaceAll(text, currency, "")
+	text = strings.ReplaceAll(text, strings.ToLower(currency), "")
+	text = strings.ReplaceAll(text, "−", "-")
+
+	// Drop spaces (including the no-break spaces some locales group with)
+	// and group separators, then normalize the decimal separator
+	text = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\u00a0', '\u202f':
+			return -1
+		}
+		return r
+	}, text)
+	if strings.TrimSpace(f.group) != "" {
+		text = strings.ReplaceAll(text, f.group, "")
+	}
+	if f.decimal != "." {
+		if strings.Contains(text, ".") {
+			return Amount{}, fmt.Errorf("money: invalid amount %q for locale %s", s, locale)
+		}
+		text = strings.ReplaceAll(text, f.decimal, ".")
+	}
+	return Parse(text, currency)
+}
@@ -0,0 +1,227 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDecimal(t *testing.T) {
+	for _, tt := range []struct {
+		in, want string
+	}{
+		{"12.50", "12.50"},
+		{"-0.5", "-0.5"},
+		{"+7", "7"},
+		{".25", "0.25"},
+		{"1.5e3", "1500"},
+		{"15e-4", "0.0015"},
+		{"  42 ", "42"},
+	} {
+		d, err := ParseDecimal(tt.in)
+		if err != nil || d.String() != tt.want {
+			t.Errorf("ParseDecimal(%q) = %s, %v; want %s", tt.in, d, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "-", ".", "1.2.3", "1,5", "abc", "1e", "1e5000", "NaN"} {
+		if _, err := ParseDecimal(in); err == nil {
+			t.Errorf("ParseDecimal(%q): expected an error", in)
+		}
+	}
+
+	a, b := MustParseDecimal("0.1"), MustParseDecimal("0.2")
+	if got := a.Add(b); got.String() != "0.3" || !got.Equal(MustParseDecimal("0.30")) {
+		t.Errorf("0.1 + 0.2 = %s", got)
+	}
+	if got := a.Sub(b); got.String() != "-0.1" {
+		t.Errorf("0.1 - 0.2 = %s", got)
+	}
+	if got := MustParseDecimal("19.99").Mul(NewDecimal(3, 0)); got.String() != "59.97" {
+		t.Errorf("19.99 × 3 = %s", got)
+	}
+	if got, err := NewDecimal(10, 0).Quo(NewDecimal(3, 0), 4, RoundHalfEven); err != nil || got.String() != "3.3333" {
+		t.Errorf("10 / 3 = %s, %v", got, err)
+	}
+	if _, err := a.Quo(Decimal{}, 2, RoundHalfEven); !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("Quo by zero: %v", err)
+	}
+	var zero Decimal
+	if !zero.IsZero() || zero.String() != "0" || zero.Add(a).String() != "0.1" || zero.Cmp(a) != -1 {
+		t.Errorf("zero value = %s", zero)
+	}
+	if got := MustParseDecimal("1.5").Round(3, RoundHalfEven); got.String() != "1.500" {
+		t.Errorf("Round pads: %s", got)
+	}
+}
+
+func TestRounding(t *testing.T) {
+	values := []string{"2.5", "3.5", "-2.5", "2.51", "-2.49"}
+	want := map[RoundingMode][]string{
+		RoundHalfEven: {"2", "4", "-2", "3", "-2"},
+		RoundHalfUp:   {"3", "4", "-3", "3", "-2"},
+		RoundHalfDown: {"2", "3", "-2", "3", "-2"},
+		RoundUp:       {"3", "4", "-3", "3", "-3"},
+		RoundDown:     {"2", "3", "-2", "2", "-2"},
+		RoundCeiling:  {"3", "4", "-2", "3", "-2"},
+		RoundFloor:    {"2", "3", "-3", "2", "-3"},
+	}
+	for _, mode := range RoundingModes {
+		for i, v := range values {
+			if got := MustParseDecimal(v).Round(0, mode).String(); got != want[mode][i] {
+				t.Errorf("Round(%s, %s) = %s, want %s", v, mode, got, want[mode][i])
+			}
+		}
+	}
+	if RoundingMode("nearest").Valid() || !RoundHalfUp.Valid() {
+		t.Error("Valid")
+	}
+}
+
+func TestDecimalEncoding(t *testing.T) {
+	var v struct {
+		A Decimal `json:"a"`
+		B Decimal `json:"b"`
+		C Decimal `json:"c"`
+	}
+	if err := json.Unmarshal([]byte(`{"a":"1234.50","b":0.1,"c":null}`), &v); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	data, _ := json.Marshal(v)
+	if string(data) != `{"a":"1234.50","b":"0.1","c":"0"}` {
+		t.Errorf("Marshal = %s", data)
+	}
+	if err := json.Unmarshal([]byte(`{"a":"ten"}`), &v); err == nil {
+		t.Error("expected an error for a non-numeric string")
+	}
+
+	for src, want := range map[interface{}]string{"2.50": "2.50", int64(7): "7", 0.1: "0.1", nil: "0"} {
+		var d Decimal
+		if err := d.Scan(src); err != nil || d.String() != want {
+			t.Errorf("Scan(%v) = %s, %v; want %s", src, d, err, want)
+		}
+	}
+	if value, _ := MustParseDecimal("3.10").Value(); value != "3.10" {
+		t.Errorf("Value = %v", value)
+	}
+}
+
+func TestAmount(t *testing.T) {
+	usd := MustParse("10.25", "usd")
+	if usd.Currency != "USD" || usd.String() != "10.25 USD" {
+		t.Errorf("amount = %s", usd)
+	}
+	if _, err := Parse("10.255", "USD"); err == nil {
+		t.Error("expected an error for sub-cent USD")
+	}
+	if _, err := Parse("100.5", "JPY"); err == nil {
+		t.Error("expected an error for fractional JPY")
+	}
+	if _, err := Parse("1.234", "KWD"); err != nil {
+		t.Errorf("KWD: %v", err)
+	}
+	if _, err := Parse("1", ""); err == nil {
+		t.Error("expected an error without a currency")
+	}
+
+	sum, err := usd.Add(MustParse("0.75", "USD"))
+	if err != nil || sum.String() != "11.00 USD" {
+		t.Errorf("Add = %s, %v", sum, err)
+	}
+	if _, err := usd.Add(MustParse("1", "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add EUR: %v", err)
+	}
+	if fee := usd.Mul(MustParseDecimal("0.015"), RoundHalfUp); fee.String() != "0.15 USD" {
+		t.Errorf("fee = %s", fee)
+	}
+	if c, err := usd.Cmp(MustParse("10.3", "USD")); err != nil || c != -1 {
+		t.Errorf("Cmp = %d, %v", c, err)
+	}
+	if New(NewDecimal(5, 0), "USD").String() != "5.00 USD" {
+		t.Error("String pads to the currency's precision")
+	}
+
+	RegisterCurrency("usdc", 6)
+	if _, err := Parse("1.000001", "USDC"); err != nil {
+		t.Errorf("registered currency: %v", err)
+	}
+
+	data, _ := json.Marshal(New(NewDecimal(5, 0), "USD"))
+	if string(data) != `{"amount":"5.00","currency":"USD"}` {
+		t.Errorf("Marshal = %s", data)
+	}
+	var decoded Amount
+	if err := json.Unmarshal([]byte(`{"amount":12.5,"currency":"eur"}`), &decoded); err != nil || !decoded.Equal(MustParse("12.50", "EUR")) {
+		t.Errorf("Unmarshal = %s, %v", decoded, err)
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	for _, tt := range []struct {
+		amount, currency string
+		ratios           []int64
+		want             string
+	}{
+		{"100.00", "USD", []int64{1, 1, 1}, "[33.34 USD 33.33 USD 33.33 USD]"},
+		{"0.05", "USD", []int64{3, 7}, "[0.02 USD 0.03 USD]"},
+		{"-10.00", "USD", []int64{1, 2}, "[-3.34 USD -6.66 USD]"},
+		{"1000", "JPY", []int64{1, 0, 2}, "[334 JPY 0 JPY 666 JPY]"},
+	} {
+		shares, err := MustParse(tt.amount, tt.currency).Allocate(tt.ratios...)
+		if err != nil || fmt.Sprint(shares) != tt.want {
+			t.Errorf("Allocate(%s, %v) = %v, %v; want %s", tt.amount, tt.ratios, shares, err, tt.want)
+		}
+		total := Zero(tt.currency)
+		for _, s := range shares {
+			total, _ = total.Add(s)
+		}
+		if !total.Equal(MustParse(tt.amount, tt.currency)) {
+			t.Errorf("Allocate(%s, %v) adds up to %s", tt.amount, tt.ratios, total)
+		}
+	}
+
+	shares, err := MustParse("10.00", "USD").Split(3)
+	if err != nil || fmt.Sprint(shares) != "[3.34 USD 3.33 USD 3.33 USD]" {
+		t.Errorf("Split = %v, %v", shares, err)
+	}
+	if _, err := MustParse("1", "USD").Allocate(0, 0); err == nil {
+		t.Error("expected an error for zero ratios")
+	}
+	if _, err := MustParse("1", "USD").Split(0); err == nil {
+		t.Error("expected an error for zero shares")
+	}
+}
+
+func TestLocale(t *testing.T) {
+	amount := MustParse("-1234567.891", "KWD")
+	usd := MustParse("1234.5", "USD")
+	for _, tt := range []struct {
+		amount Amount
+		locale string
+		want   string
+	}{
+		{usd, "en-US", "$1,234.50"},
+		{usd, "de-DE", "1.234,50 $"},
+		{MustParse("1234.5", "EUR"), "fr_FR", "1 234,50 €"},
+		{MustParse("1234.5", "EUR"), "nl", "€ 1.234,50"},
+		{MustParse("1234.5", "CHF"), "de-CH", "CHF 1'234.50"},
+		{MustParse("1234", "JPY"), "ja-JP", "¥1,234"},
+		{amount, "en", "-KWD 1,234,567.891"},
+		{MustParse("5", "USD"), "xx", "$5.00"},
+	} {
+		if got := Format(tt.amount, tt.locale); got != tt.want {
+			t.Errorf("Format(%s, %s) = %q, want %q", tt.amount, tt.locale, got, tt.want)
+		}
+		parsed, err := ParseLocale(tt.want, tt.amount.Currency, tt.locale)
+		if err != nil || !parsed.Equal(tt.amount) {
+			t.Errorf("ParseLocale(%q, %s) = %s, %v", tt.want, tt.locale, parsed, err)
+		}
+	}
+
+	if a, err := ParseLocale("1.234,5 EUR", "EUR", "de"); err != nil || a.String() != "1234.50 EUR" {
+		t.Errorf("ParseLocale with code = %s, %v", a, err)
+	}
+	if _, err := ParseLocale("1,234.50", "EUR", "de"); err == nil {
+		t.Error("expected an error for an en-style amount")
+	}
+}
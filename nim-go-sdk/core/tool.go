@@ -37,6 +37,11 @@ type ToolParams struct {
 	// ConfirmationID is set for confirmed write operations.
 	ConfirmationID string
 
+	// IdempotencyKey is set for confirmed write operations to the pending
+	// action's key, so tools can retry the write safely. ExecutorTool
+	// confirms through ToolExecutor.Confirm, which doesn't take it.
+	IdempotencyKey string
+
 	// RequestID for tracing/logging.
 	RequestID string
 }
@@ -54,6 +59,10 @@ type ToolResult struct {
 
 	// Metadata contains additional info (e.g., transaction hash).
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+
+	// TokensUsed is set by tools that call Claude themselves, such as
+	// sub-agent delegation. The engine adds it to the run's token usage.
+	TokensUsed *TokenUsage `json:"tokens_used,omitempty"`
 }
 
 // ToolDefinition contains static tool metadata.
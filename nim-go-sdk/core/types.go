@@ -2,8 +2,13 @@
 package core
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core/money"
 )
 
 // Role represents the role of a message sender.
@@ -206,6 +211,9 @@ type UserPreferences struct {
 	// DefaultVault is the user's preferred savings vault.
 	DefaultVault string `json:"default_vault"`
 
+	// Currency is the currency amounts are shown in (e.g., "USD").
+	Currency string `json:"currency"`
+
 	// Locale is the user's language preference (e.g., "en-US").
 	Locale string `json:"locale"`
 
@@ -223,30 +231,131 @@ func DefaultPreferences() *UserPreferences {
 		DefaultChain: "arbitrum",
 		DefaultToken: "usdc",
 		DefaultVault: "morpho",
+		Currency:     "USD",
 		Locale:       "en-US",
 		Timezone:     "UTC",
 	}
 }
 
-// UserLimits contains user-specific financial limits.
+// ErrTransferLimit is returned when a transfer exceeds the user's limits.
+var ErrTransferLimit = errors.New("transfer limit exceeded")
+
+// UserLimits contains user-specific financial limits. The limits share one
+// currency, and transfers in other currencies are refused rather than
+// compared; convert them first, e.g. with the fx package. A zero limit
+// means no limit.
 type UserLimits struct {
 	// DailyTransferLimit is the maximum amount the user can transfer per day.
-	DailyTransferLimit string `json:"daily_transfer_limit"`
+	DailyTransferLimit money.Amount `json:"daily_transfer_limit"`
 
 	// DailyTransferUsed is the amount already transferred today.
-	DailyTransferUsed string `json:"daily_transfer_used"`
+	DailyTransferUsed money.Amount `json:"daily_transfer_used"`
 
 	// SingleTransferMax is the maximum amount for a single transfer.
-	SingleTransferMax string `json:"single_transfer_max"`
+	SingleTransferMax money.Amount `json:"single_transfer_max"`
 }
 
-// DefaultUserLimits returns sensible default user limits.
+// DefaultUserLimits returns sensible default user limits, in USD.
 func DefaultUserLimits() *UserLimits {
 	return &UserLimits{
-		DailyTransferLimit: "10000.00",
-		DailyTransferUsed:  "0.00",
-		SingleTransferMax:  "5000.00",
+		DailyTransferLimit: money.MustParse("10000.00", "USD"),
+		DailyTransferUsed:  money.MustParse("0.00", "USD"),
+		SingleTransferMax:  money.MustParse("5000.00", "USD"),
+	}
+}
+
+// UnmarshalJSON decodes limits whose fields are amounts, or plain decimal
+// strings or numbers as they were before limits carried a currency. Plain
+// values are taken to be in USD.
+func (l *UserLimits) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		DailyTransferLimit json.RawMessage `json:"daily_transfer_limit"`
+		DailyTransferUsed  json.RawMessage `json:"daily_transfer_used"`
+		SingleTransferMax  json.RawMessage `json:"single_transfer_max"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var limits UserLimits
+	for _, field := range []struct {
+		name string
+		raw  json.RawMessage
+		dst  *money.Amount
+	}{
+		{"daily_transfer_limit", raw.DailyTransferLimit, &limits.DailyTransferLimit},
+		{"daily_transfer_used", raw.DailyTransferUsed, &limits.DailyTransferUsed},
+		{"single_transfer_max", raw.SingleTransferMax, &limits.SingleTransferMax},
+	} {
+		if err := decodeLimit(field.raw, field.dst); err != nil {
+			return fmt.Errorf("%s: %w", field.name, err)
+		}
+	}
+	*l = limits
+	return nil
+}
+
+// decodeLimit decodes a limit written as an amount or a legacy USD value.
+func decodeLimit(raw json.RawMessage, dst *money.Amount) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if raw[0] == '{' {
+		return json.Unmarshal(raw, dst)
+	}
+	var value money.Decimal
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return err
+	}
+	*dst = money.New(value, "USD")
+	return nil
+}
+
+// CheckTransfer returns an error wrapping ErrTransferLimit if a transfer
+// of amount would exceed the single transfer maximum or the rest of the
+// daily limit, or wrapping money.ErrCurrencyMismatch if amount isn't in
+// the limits' currency. Amounts that aren't positive are refused.
+func (l *UserLimits) CheckTransfer(amount money.Amount) error {
+	if l == nil {
+		return nil
+	}
+	if amount.Sign() <= 0 {
+		return fmt.Errorf("transfer amount must be positive, got %s", amount)
+	}
+	if l.SingleTransferMax.Sign() > 0 {
+		if err := checkCurrency(amount, l.SingleTransferMax); err != nil {
+			return err
+		}
+		if amount.Value.Cmp(l.SingleTransferMax.Value) > 0 {
+			return fmt.Errorf("%w: %s is over the single transfer maximum of %s", ErrTransferLimit, amount, l.SingleTransferMax)
+		}
+	}
+	if l.DailyTransferLimit.Sign() > 0 {
+		if err := checkCurrency(amount, l.DailyTransferLimit); err != nil {
+			return err
+		}
+		remaining := l.DailyTransferLimit
+		if !l.DailyTransferUsed.IsZero() {
+			var err error
+			if remaining, err = remaining.Sub(l.DailyTransferUsed); err != nil {
+				return err
+			}
+		}
+		if remaining.Sign() < 0 {
+			remaining = money.Zero(remaining.Currency)
+		}
+		if amount.Value.Cmp(remaining.Value) > 0 {
+			return fmt.Errorf("%w: %s is over the %s left of today's %s limit", ErrTransferLimit, amount, remaining, l.DailyTransferLimit)
+		}
 	}
+	return nil
+}
+
+// checkCurrency fails if amount isn't in the currency of limit.
+func checkCurrency(amount, limit money.Amount) error {
+	if amount.Currency != limit.Currency {
+		return fmt.Errorf("%w: the transfer is in %s but the limits are in %s", money.ErrCurrencyMismatch, amount.Currency, limit.Currency)
+	}
+	return nil
 }
 
 // ExecutionLimits constrains agent execution.
@@ -292,9 +401,12 @@ func SubAgentLimits() *ExecutionLimits {
 
 // ForSubAgent creates a new context for sub-agent execution.
 // The new context inherits user identity but has restricted limits
-// and sets up audit parent chain.
+// and sets up audit parent chain. Its timeout never outlasts the time
+// the parent has left.
 func (c *Context) ForSubAgent(requestID string) *Context {
 	parentID := c.RequestID
+	limits := SubAgentLimits()
+	limits.Timeout = c.CapTimeout(limits.Timeout)
 	return &Context{
 		UserID:         c.UserID,
 		SessionID:      c.SessionID,
@@ -303,16 +415,43 @@ func (c *Context) ForSubAgent(requestID string) *Context {
 		AuditParentID:  &parentID,
 		Preferences:    c.Preferences,
 		UserLimits:     c.UserLimits,
-		Limits:         SubAgentLimits(),
+		Limits:         limits,
 		StartTime:      time.Now(),
 	}
 }
 
+type contextKey struct{}
+
+// WithContext returns a copy of ctx that carries c. The engine attaches
+// the run's Context this way, so tools can reach it with FromContext.
+func WithContext(ctx context.Context, c *Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the Context attached to ctx by WithContext.
+func FromContext(ctx context.Context) (*Context, bool) {
+	c, ok := ctx.Value(contextKey{}).(*Context)
+	return c, ok && c != nil
+}
+
 // Elapsed returns the time elapsed since StartTime.
 func (c *Context) Elapsed() time.Duration {
 	return time.Since(c.StartTime)
 }
 
+// CapTimeout returns d, or the time left before the context times out if
+// that is shorter. The result is never zero, which would mean no timeout;
+// a context that has timed out gives one nanosecond.
+func (c *Context) CapTimeout(d time.Duration) time.Duration {
+	if c.Limits == nil || c.Limits.Timeout == 0 || c.StartTime.IsZero() {
+		return d
+	}
+	if remaining := c.Limits.Timeout - c.Elapsed(); remaining < d {
+		return max(remaining, time.Nanosecond)
+	}
+	return d
+}
+
 // IsTimedOut returns true if the context has exceeded its timeout.
 func (c *Context) IsTimedOut() bool {
 	if c.Limits == nil || c.Limits.Timeout == 0 {
@@ -341,6 +480,14 @@ func (t TokenUsage) TotalTokens() int {
 	return t.InputTokens + t.OutputTokens
 }
 
+// Add adds u to t.
+func (t *TokenUsage) Add(u TokenUsage) {
+	t.InputTokens += u.InputTokens
+	t.OutputTokens += u.OutputTokens
+	t.CacheCreationInputTokens += u.CacheCreationInputTokens
+	t.CacheReadInputTokens += u.CacheReadInputTokens
+}
+
 // PendingAction represents an action awaiting user confirmation.
 type PendingAction struct {
 	// ID is the unique identifier for this pending action.
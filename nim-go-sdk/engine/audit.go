@@ -3,6 +3,7 @@ package engine
 import (
 	"context"
 	"encoding/json"
+	"sync"
 )
 
 // AuditLogger logs tool executions for compliance and debugging.
@@ -68,6 +69,7 @@ func (n *NoOpAuditLogger) Log(ctx context.Context, entry *AuditEntry) error {
 // MemoryAuditLogger stores audit entries in memory.
 // Useful for testing and debugging.
 type MemoryAuditLogger struct {
+	mu      sync.Mutex
 	entries []*AuditEntry
 }
 
@@ -80,16 +82,22 @@ func NewMemoryAuditLogger() *MemoryAuditLogger {
 
 // Log stores the audit entry in memory.
 func (m *MemoryAuditLogger) Log(ctx context.Context, entry *AuditEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.entries = append(m.entries, entry)
 	return nil
 }
 
 // Entries returns all stored audit entries.
 func (m *MemoryAuditLogger) Entries() []*AuditEntry {
-	return m.entries
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*AuditEntry(nil), m.entries...)
 }
 
 // Clear removes all stored entries.
 func (m *MemoryAuditLogger) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.entries = make([]*AuditEntry, 0)
 }
@@ -17,6 +17,9 @@ type Engine struct {
 	registry   *ToolRegistry
 	guardrails Guardrails  // Optional: rate limiting and circuit breaker
 	audit      AuditLogger // Optional: audit logging
+	titleModel string      // Optional: overrides the title generation model
+
+	transferTools []string // tools whose amount is checked against UserLimits
 }
 
 // Option configures the engine.
@@ -36,11 +39,36 @@ func WithAudit(a AuditLogger) Option {
 	}
 }
 
+// WithTitleModel sets the model used by GenerateTitle.
+func WithTitleModel(model string) Option {
+	return func(e *Engine) {
+		e.titleModel = model
+	}
+}
+
+// WithTransferTools sets the tools whose "amount" input is checked against
+// the user's transfer limits before confirmation. Defaults to
+// DefaultTransferTools.
+func WithTransferTools(names ...string) Option {
+	return func(e *Engine) {
+		e.transferTools = names
+	}
+}
+
+// WithToolMiddleware attaches middleware to every tool in the engine's
+// registry. Use ToolRegistry.UseFor for per-tool middleware.
+func WithToolMiddleware(middleware ...ToolMiddleware) Option {
+	return func(e *Engine) {
+		e.registry.Use(middleware...)
+	}
+}
+
 // NewEngine creates a new engine with the given Anthropic client and registry.
 func NewEngine(client *anthropic.Client, registry *ToolRegistry, opts ...Option) *Engine {
 	e := &Engine{
-		client:   client,
-		registry: registry,
+		client:        client,
+		registry:      registry,
+		transferTools: DefaultTransferTools,
 	}
 	for _, opt := range opts {
 		opt(e)
@@ -168,6 +196,10 @@ func (e *Engine) Run(ctx context.Context, input *Input) (*Output, error) {
 			defer cancel()
 		}
 	}
+	if input.Context != nil {
+		// Let tools, such as sub-agent delegation, see the caller's context
+		ctx = core.WithContext(ctx, input.Context)
+	}
 
 	// Create session
 	userID := ""
@@ -203,8 +235,12 @@ func (e *Engine) Run(ctx context.Context, input *Input) (*Output, error) {
 		agentName = "default"
 	}
 
-	// Get parent ID for audit chain
+	// Get request and parent IDs for audit chain
+	auditRequestID := session.ID
 	var auditParentID *string
+	if input.Context != nil && input.Context.RequestID != "" {
+		auditRequestID = input.Context.RequestID
+	}
 	if input.Context != nil && input.Context.AuditParentID != nil {
 		auditParentID = input.Context.AuditParentID
 	}
@@ -265,6 +301,8 @@ func (e *Engine) Run(ctx context.Context, input *Input) (*Output, error) {
 		// Accumulate token usage
 		totalTokens.InputTokens += int(resp.Usage.InputTokens)
 		totalTokens.OutputTokens += int(resp.Usage.OutputTokens)
+		totalTokens.CacheCreationInputTokens += int(resp.Usage.CacheCreationInputTokens)
+		totalTokens.CacheReadInputTokens += int(resp.Usage.CacheReadInputTokens)
 
 		// Process response blocks
 		var toolResults []anthropic.ContentBlockParamUnion
@@ -303,6 +341,14 @@ func (e *Engine) Run(ctx context.Context, input *Input) (*Output, error) {
 					}
 
 					inputBytes, _ := json.Marshal(toolInput)
+					if err := e.checkTransferLimits(input.Context, toolName, inputBytes); err != nil {
+						toolResults = append(toolResults, anthropic.NewToolResultBlock(
+							block.ID,
+							"error: "+err.Error(),
+							true,
+						))
+						continue
+					}
 					confirmationNeeded = &core.PendingAction{
 						ID:             uuid.New().String(),
 						IdempotencyKey: GenerateIdempotencyKey(session.UserID, toolName, inputBytes),
@@ -329,6 +375,10 @@ func (e *Engine) Run(ctx context.Context, input *Input) (*Output, error) {
 				})
 
 				durationMs := time.Since(startTime).Milliseconds()
+				if result != nil && result.TokensUsed != nil {
+					// Count tokens spent by sub-agents the tool ran
+					totalTokens.Add(*result.TokensUsed)
+				}
 				execution := core.ToolExecution{
 					Tool:       toolName,
 					Input:      toolInput,
@@ -353,7 +403,7 @@ func (e *Engine) Run(ctx context.Context, input *Input) (*Output, error) {
 						ID:         uuid.New().String(),
 						UserID:     session.UserID,
 						SessionID:  session.ID,
-						RequestID:  session.ID,
+						RequestID:  auditRequestID,
 						ParentID:   auditParentID,
 						AgentName:  agentName,
 						ToolName:   toolName,
@@ -446,6 +496,16 @@ func (e *Engine) Run(ctx context.Context, input *Input) (*Output, error) {
 
 // ExecuteTool executes a confirmed write operation.
 func (e *Engine) ExecuteTool(ctx context.Context, userID, toolName string, input json.RawMessage, confirmationID string) (*core.ToolResult, error) {
+	return e.executeConfirmed(ctx, userID, toolName, input, confirmationID, "")
+}
+
+// ExecuteAction executes a confirmed pending action, passing its
+// idempotency key to the tool.
+func (e *Engine) ExecuteAction(ctx context.Context, action *core.PendingAction) (*core.ToolResult, error) {
+	return e.executeConfirmed(ctx, action.UserID, action.Tool, action.Input, action.ID, action.IdempotencyKey)
+}
+
+func (e *Engine) executeConfirmed(ctx context.Context, userID, toolName string, input json.RawMessage, confirmationID, idempotencyKey string) (*core.ToolResult, error) {
 	tool, ok := e.registry.Get(toolName)
 	if !ok {
 		return nil, fmt.Errorf("unknown tool: %s", toolName)
@@ -455,6 +515,7 @@ func (e *Engine) ExecuteTool(ctx context.Context, userID, toolName string, input
 		UserID:         userID,
 		Input:          input,
 		ConfirmationID: confirmationID,
+		IdempotencyKey: idempotencyKey,
 		RequestID:      confirmationID,
 	})
 }
@@ -511,6 +572,8 @@ func responseToBlocks(resp *anthropic.Message) []core.ContentBlock {
 
 // RunAgent executes an Agent using the engine.
 // This method uses the agent's Capabilities to configure the execution.
+// If the run fails after calling Claude, the output is returned with the
+// error so the tokens used are still accounted for.
 func (e *Engine) RunAgent(ctx context.Context, agent core.Agent, input *core.Input) (*core.Output, error) {
 	caps := agent.Capabilities()
 
@@ -542,11 +605,11 @@ func (e *Engine) RunAgent(ctx context.Context, agent core.Agent, input *core.Inp
 
 	// Run the engine
 	output, err := e.Run(ctx, engineInput)
-	if err != nil {
+	if output == nil {
 		return nil, err
 	}
 
-	// Convert to core output
+	// Convert to core output, keeping the token usage of failed runs
 	return &core.Output{
 		Type:           core.OutputType(output.Type),
 		Text:           output.Text,
@@ -555,7 +618,7 @@ func (e *Engine) RunAgent(ctx context.Context, agent core.Agent, input *core.Inp
 		ResponseBlocks: output.ResponseBlocks,
 		TokensUsed:     output.TokensUsed,
 		Error:          output.Error,
-	}, nil
+	}, err
 }
 
 // DefaultSystemPrompt is the default system prompt for the agent.
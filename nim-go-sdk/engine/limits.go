@@ -0,0 +1,33 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/core/money"
+)
+
+// DefaultTransferTools are the tools that move money out of the user's
+// account and count against core.UserLimits.
+var DefaultTransferTools = []string{"send_money"}
+
+// checkTransferLimits rejects a transfer tool call whose amount exceeds
+// the user's limits, or whose currency differs from theirs, so the user is
+// never asked to confirm a transfer that would be refused.
+func (e *Engine) checkTransferLimits(ctx *core.Context, toolName string, input json.RawMessage) error {
+	if ctx == nil || ctx.UserLimits == nil || !slices.Contains(e.transferTools, toolName) {
+		return nil
+	}
+	// The input's amount and currency fields decode as one Amount
+	var amount money.Amount
+	if err := json.Unmarshal(input, &amount); err != nil {
+		return fmt.Errorf("invalid amount: %w", err)
+	}
+	if amount.Currency == "" {
+		return errors.New("invalid amount: currency is required")
+	}
+	return ctx.UserLimits.CheckTransfer(amount)
+}
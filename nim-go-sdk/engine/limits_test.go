@@ -0,0 +1,101 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/becomeliminal/nim-go-sdk/anthropictest"
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/core/money"
+)
+
+func TestTransferLimits(t *testing.T) {
+	registry := NewToolRegistry()
+	registry.Register(core.NewBaseTool(core.ToolDefinition{
+		ToolName:                 "send_money",
+		RequiresUserConfirmation: true,
+	}, func(ctx context.Context, p *core.ToolParams) (*core.ToolResult, error) {
+		return &core.ToolResult{Success: true}, nil
+	}))
+
+	limits := core.DefaultUserLimits()
+	limits.DailyTransferUsed = money.MustParse("9000.00", "USD")
+
+	for _, tt := range []struct {
+		amount   interface{}
+		currency string
+		want     string // tool error sent back to the model; empty if confirmation is needed
+	}{
+		{"250.00", "USD", ""},
+		{1000, "usd", ""},
+		{"5000.01", "USD", "error: transfer limit exceeded: 5000.01 USD is over the single transfer maximum of 5000.00 USD"},
+		{"1000.01", "USD", "error: transfer limit exceeded: 1000.01 USD is over the 1000.00 USD left of today's 10000.00 USD limit"},
+		{"250.00", "EUR", "error: money: currency mismatch: the transfer is in EUR but the limits are in USD"},
+		{"250.00", "", "error: invalid amount: currency is required"},
+		{"0", "USD", "error: transfer amount must be positive, got 0.00 USD"},
+		{"-25.00", "USD", "error: transfer amount must be positive, got -25.00 USD"},
+		{"lots", "USD", "error: invalid amount"},
+	} {
+		fake := anthropictest.NewServer(
+			anthropictest.ToolUse("send_money", map[string]interface{}{"recipient": "@bob", "amount": tt.amount, "currency": tt.currency}),
+			anthropictest.Text("Done."),
+		)
+		client := anthropic.NewClient(option.WithAPIKey("test-key"), option.WithBaseURL(fake.URL))
+		e := NewEngine(&client, registry)
+
+		ctx := core.NewContext("user_1", "session_1", "conv_1", "req_1")
+		ctx.UserLimits = limits
+		out, err := e.Run(context.Background(), &Input{UserMessage: "pay bob", Context: ctx, Model: "claude-test", MaxTokens: 100})
+		fake.Close()
+		if err != nil {
+			t.Fatalf("%v: Run failed: %v", tt.amount, err)
+		}
+
+		if tt.want == "" {
+			if out.Type != OutputConfirmationNeeded {
+				t.Errorf("%v: expected confirmation, got %+v", tt.amount, out)
+			}
+			continue
+		}
+		if out.Type != OutputComplete {
+			t.Errorf("%v: expected the transfer to be refused, got %+v", tt.amount, out)
+		}
+		requests := fake.Requests()
+		if len(requests) != 2 || !strings.Contains(string(requests[1].Body), tt.want) {
+			t.Errorf("%v: expected tool error %q in %s", tt.amount, tt.want, requests[len(requests)-1].Body)
+		}
+	}
+}
+
+func TestUserLimitsJSON(t *testing.T) {
+	// Limits saved before they carried a currency decode as USD
+	var legacy core.UserLimits
+	if err := json.Unmarshal([]byte(`{"daily_transfer_limit":"10000.00","daily_transfer_used":250,"single_transfer_max":"5000.00"}`), &legacy); err != nil {
+		t.Fatal(err)
+	}
+	want := core.DefaultUserLimits()
+	want.DailyTransferUsed = money.MustParse("250", "USD")
+	if !legacy.DailyTransferLimit.Equal(want.DailyTransferLimit) || !legacy.DailyTransferUsed.Equal(want.DailyTransferUsed) || !legacy.SingleTransferMax.Equal(want.SingleTransferMax) {
+		t.Errorf("legacy limits = %+v, want %+v", legacy, *want)
+	}
+
+	data, err := json.Marshal(core.UserLimits{SingleTransferMax: money.MustParse("900", "EUR")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded core.UserLimits
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.SingleTransferMax.Equal(money.MustParse("900", "EUR")) {
+		t.Errorf("round trip of %s = %+v", data, decoded)
+	}
+
+	if err := json.Unmarshal([]byte(`{"single_transfer_max":"lots"}`), &decoded); err == nil {
+		t.Error("expected an error for an invalid limit")
+	}
+}
@@ -0,0 +1,272 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+// ToolMiddleware wraps a tool, typically to add behaviour around Execute.
+// Attach middleware with ToolRegistry.Use (all tools) or ToolRegistry.UseFor
+// (tools by name).
+type ToolMiddleware func(next core.Tool) core.Tool
+
+// ExecuteMiddleware builds a ToolMiddleware from a function that runs in
+// place of the tool's Execute. The wrapped tool keeps next's name, schema
+// and confirmation settings.
+func ExecuteMiddleware(fn func(ctx context.Context, params *core.ToolParams, next core.Tool) (*core.ToolResult, error)) ToolMiddleware {
+	return func(next core.Tool) core.Tool {
+		return &middlewareTool{Tool: next, execute: fn}
+	}
+}
+
+// middlewareTool delegates everything but Execute to the wrapped tool.
+type middlewareTool struct {
+	core.Tool
+	execute func(ctx context.Context, params *core.ToolParams, next core.Tool) (*core.ToolResult, error)
+}
+
+func (t *middlewareTool) Execute(ctx context.Context, params *core.ToolParams) (*core.ToolResult, error) {
+	return t.execute(ctx, params, t.Tool)
+}
+
+// Chain combines middleware into one; the first is outermost.
+func Chain(middleware ...ToolMiddleware) ToolMiddleware {
+	return func(next core.Tool) core.Tool {
+		for i := len(middleware) - 1; i >= 0; i-- {
+			next = middleware[i](next)
+		}
+		return next
+	}
+}
+
+// Timeout cancels a tool's context after d and reports a timeout error,
+// even if the tool ignores its context.
+func Timeout(d time.Duration) ToolMiddleware {
+	return ExecuteMiddleware(func(ctx context.Context, params *core.ToolParams, next core.Tool) (*core.ToolResult, error) {
+		ctx, cancel := context.WithTimeout(ctx, d)
+		defer cancel()
+
+		type outcome struct {
+			result *core.ToolResult
+			err    error
+			panic  interface{}
+		}
+		done := make(chan outcome, 1)
+		go func() {
+			var out outcome
+			defer func() {
+				out.panic = recover()
+				done <- out
+			}()
+			out.result, out.err = next.Execute(ctx, params)
+		}()
+
+		select {
+		case out := <-done:
+			if out.panic != nil {
+				// Re-raise on the caller's goroutine so Recover can handle it
+				panic(out.panic)
+			}
+			return out.result, out.err
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return &core.ToolResult{
+					Success: false,
+					Error:   fmt.Sprintf("%s timed out after %s", next.Name(), d),
+				}, nil
+			}
+			return nil, ctx.Err()
+		}
+	})
+}
+
+// Recover turns a panicking tool into a failed result, so Claude sees an
+// error instead of the server crashing. The stack is logged.
+func Recover() ToolMiddleware {
+	return ExecuteMiddleware(func(ctx context.Context, params *core.ToolParams, next core.Tool) (result *core.ToolResult, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Tool %s panicked: %v\n%s", next.Name(), r, debug.Stack())
+				result = &core.ToolResult{Success: false, Error: fmt.Sprintf("%s failed unexpectedly", next.Name())}
+				err = nil
+			}
+		}()
+		return next.Execute(ctx, params)
+	})
+}
+
+// Logging logs each tool call's outcome and duration. If logger is nil,
+// the standard logger is used.
+func Logging(logger *log.Logger) ToolMiddleware {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return ExecuteMiddleware(func(ctx context.Context, params *core.ToolParams, next core.Tool) (*core.ToolResult, error) {
+		start := time.Now()
+		result, err := next.Execute(ctx, params)
+		duration := time.Since(start).Round(time.Millisecond)
+
+		switch {
+		case err != nil:
+			logger.Printf("Tool %s user=%s duration=%s error=%q", next.Name(), params.UserID, duration, err)
+		case result != nil && !result.Success:
+			logger.Printf("Tool %s user=%s duration=%s failed=%q", next.Name(), params.UserID, duration, result.Error)
+		default:
+			logger.Printf("Tool %s user=%s duration=%s ok", next.Name(), params.UserID, duration)
+		}
+		return result, err
+	})
+}
+
+// TruncateResults caps the JSON size of successful results at maxBytes.
+// Oversized data is replaced by its truncated JSON text with a marker, and
+// Metadata["truncated"] is set.
+func TruncateResults(maxBytes int) ToolMiddleware {
+	return ExecuteMiddleware(func(ctx context.Context, params *core.ToolParams, next core.Tool) (*core.ToolResult, error) {
+		result, err := next.Execute(ctx, params)
+		if err != nil || result == nil || !result.Success {
+			return result, err
+		}
+
+		data, merr := json.Marshal(result.Data)
+		if merr != nil || len(data) <= maxBytes {
+			return result, err
+		}
+
+		truncated := *result
+		cut := maxBytes
+		for cut > 0 && !utf8Start(data[cut]) {
+			cut--
+		}
+		truncated.Data = fmt.Sprintf("%s... [truncated %d of %d bytes]", data[:cut], len(data)-cut, len(data))
+		truncated.Metadata = make(map[string]interface{}, len(result.Metadata)+1)
+		for k, v := range result.Metadata {
+			truncated.Metadata[k] = v
+		}
+		truncated.Metadata["truncated"] = true
+		return &truncated, nil
+	})
+}
+
+// utf8Start reports whether b can begin a UTF-8 sequence.
+func utf8Start(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// RateLimit allows each user at most limit calls to a tool per window.
+// Calls over the limit fail with a retry hint. Limits are tracked per tool
+// name, so one RateLimit can be attached globally.
+func RateLimit(limit int, window time.Duration) ToolMiddleware {
+	var mu sync.Mutex
+	calls := make(map[string][]time.Time) // tool + user -> call times in window
+
+	return ExecuteMiddleware(func(ctx context.Context, params *core.ToolParams, next core.Tool) (*core.ToolResult, error) {
+		key := next.Name() + "\x00" + params.UserID
+		now := time.Now()
+
+		mu.Lock()
+		recent := calls[key][:0]
+		for _, t := range calls[key] {
+			if now.Sub(t) < window {
+				recent = append(recent, t)
+			}
+		}
+		if len(recent) >= limit {
+			calls[key] = recent
+			retryIn := window - now.Sub(recent[0])
+			mu.Unlock()
+			return &core.ToolResult{
+				Success: false,
+				Error:   fmt.Sprintf("rate limit exceeded for %s; try again in %s", next.Name(), retryIn.Round(time.Second)),
+			}, nil
+		}
+		calls[key] = append(recent, now)
+		mu.Unlock()
+
+		return next.Execute(ctx, params)
+	})
+}
+
+// Redact masks sensitive values in results before they go back to Claude.
+// Values of object keys matching fields (case-insensitive) and substrings
+// matching patterns are replaced with "[REDACTED]".
+func Redact(fields []string, patterns ...*regexp.Regexp) ToolMiddleware {
+	r := &redactor{fields: fields, patterns: patterns}
+	return ExecuteMiddleware(func(ctx context.Context, params *core.ToolParams, next core.Tool) (*core.ToolResult, error) {
+		result, err := next.Execute(ctx, params)
+		if err != nil || result == nil {
+			return result, err
+		}
+
+		masked := *result
+		masked.Error = r.redactString(result.Error)
+		if result.Data != nil {
+			data, merr := json.Marshal(result.Data)
+			if merr != nil {
+				return result, err
+			}
+			var v interface{}
+			if err := json.Unmarshal(data, &v); err != nil {
+				return result, nil
+			}
+			masked.Data = r.redactValue(v)
+		}
+		return &masked, nil
+	})
+}
+
+const redacted = "[REDACTED]"
+
+type redactor struct {
+	fields   []string
+	patterns []*regexp.Regexp
+}
+
+func (r *redactor) redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			if r.sensitive(k) && child != nil {
+				val[k] = redacted
+				continue
+			}
+			val[k] = r.redactValue(child)
+		}
+		return val
+	case []interface{}:
+		for i, child := range val {
+			val[i] = r.redactValue(child)
+		}
+		return val
+	case string:
+		return r.redactString(val)
+	default:
+		return val
+	}
+}
+
+func (r *redactor) sensitive(key string) bool {
+	for _, field := range r.fields {
+		if strings.EqualFold(field, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *redactor) redactString(s string) string {
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllLiteralString(s, redacted)
+	}
+	return s
+}
@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+func newTestTool(name string, handler core.ToolHandler) core.Tool {
+	return core.NewBaseTool(core.ToolDefinition{ToolName: name}, handler)
+}
+
+func execute(t *testing.T, r *ToolRegistry, name string) *core.ToolResult {
+	t.Helper()
+	tool, ok := r.Get(name)
+	if !ok {
+		t.Fatalf("tool %s not registered", name)
+	}
+	result, err := tool.Execute(context.Background(), &core.ToolParams{UserID: "user_1"})
+	if err != nil {
+		t.Fatalf("%s returned error: %v", name, err)
+	}
+	return result
+}
+
+func TestToolMiddleware(t *testing.T) {
+	r := NewToolRegistry()
+	r.RegisterAll(
+		newTestTool("panics", func(ctx context.Context, p *core.ToolParams) (*core.ToolResult, error) {
+			panic("boom")
+		}),
+		newTestTool("slow", func(ctx context.Context, p *core.ToolParams) (*core.ToolResult, error) {
+			time.Sleep(time.Second)
+			return &core.ToolResult{Success: true}, nil
+		}),
+		newTestTool("profile", func(ctx context.Context, p *core.ToolParams) (*core.ToolResult, error) {
+			return &core.ToolResult{Success: true, Data: map[string]interface{}{
+				"name":  "Alice",
+				"email": "alice@example.com",
+				"notes": []interface{}{"call 555-0100"},
+			}}, nil
+		}),
+		newTestTool("big", func(ctx context.Context, p *core.ToolParams) (*core.ToolResult, error) {
+			return &core.ToolResult{Success: true, Data: strings.Repeat("é", 100)}, nil
+		}),
+	)
+
+	var logs bytes.Buffer
+	r.Use(Recover(), Logging(log.New(&logs, "", 0)))
+	r.UseFor("slow", Timeout(20*time.Millisecond))
+	r.UseFor("profile", Redact([]string{"email"}, regexp.MustCompile(`\d{3}-\d{4}`)), RateLimit(2, time.Minute))
+	r.UseFor("big", TruncateResults(32))
+
+	if result := execute(t, r, "panics"); result.Success || !strings.Contains(result.Error, "failed unexpectedly") {
+		t.Errorf("expected panic to become an error result, got %+v", result)
+	}
+
+	if result := execute(t, r, "slow"); result.Success || !strings.Contains(result.Error, "timed out after 20ms") {
+		t.Errorf("expected timeout, got %+v", result)
+	}
+
+	result := execute(t, r, "profile")
+	data := result.Data.(map[string]interface{})
+	if data["email"] != "[REDACTED]" || data["name"] != "Alice" || data["notes"].([]interface{})[0] != "call [REDACTED]" {
+		t.Errorf("unexpected redaction: %+v", data)
+	}
+	execute(t, r, "profile")
+	if result := execute(t, r, "profile"); result.Success || !strings.Contains(result.Error, "rate limit exceeded for profile") {
+		t.Errorf("expected third call to be rate limited, got %+v", result)
+	}
+
+	result = execute(t, r, "big")
+	if text, _ := result.Data.(string); !strings.Contains(text, "[truncated") || result.Metadata["truncated"] != true {
+		t.Errorf("expected truncated result, got %+v", result)
+	}
+
+	// Registering after Use still applies global middleware
+	r.Register(newTestTool("late", func(ctx context.Context, p *core.ToolParams) (*core.ToolResult, error) {
+		panic("late boom")
+	}))
+	if result := execute(t, r, "late"); result.Success {
+		t.Errorf("expected global Recover on late tool, got %+v", result)
+	}
+
+	if !strings.Contains(logs.String(), "Tool slow user=user_1") {
+		t.Errorf("expected slow call to be logged, got:\n%s", logs.String())
+	}
+}
@@ -10,14 +10,19 @@ import (
 
 // ToolRegistry manages available tools for an agent.
 type ToolRegistry struct {
-	mu    sync.RWMutex
-	tools map[string]core.Tool
+	mu      sync.RWMutex
+	tools   map[string]core.Tool
+	global  []ToolMiddleware
+	perTool map[string][]ToolMiddleware
+	wrapped map[string]core.Tool // tools with middleware applied, built lazily
 }
 
 // NewToolRegistry creates a new tool registry.
 func NewToolRegistry() *ToolRegistry {
 	return &ToolRegistry{
-		tools: make(map[string]core.Tool),
+		tools:   make(map[string]core.Tool),
+		perTool: make(map[string][]ToolMiddleware),
+		wrapped: make(map[string]core.Tool),
 	}
 }
 
@@ -26,6 +31,35 @@ func (r *ToolRegistry) Register(tool core.Tool) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.tools[tool.Name()] = tool
+	delete(r.wrapped, tool.Name())
+}
+
+// Unregister removes a tool from the registry. Middleware attached to the
+// name with UseFor is kept.
+func (r *ToolRegistry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tools, name)
+	delete(r.wrapped, name)
+}
+
+// Use attaches middleware to every tool, including tools registered later.
+// Middleware added first runs outermost, and global middleware runs outside
+// middleware attached with UseFor.
+func (r *ToolRegistry) Use(middleware ...ToolMiddleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.global = append(r.global, middleware...)
+	r.wrapped = make(map[string]core.Tool)
+}
+
+// UseFor attaches middleware to one tool by name, whether or not it is
+// registered yet.
+func (r *ToolRegistry) UseFor(name string, middleware ...ToolMiddleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.perTool[name] = append(r.perTool[name], middleware...)
+	delete(r.wrapped, name)
 }
 
 // RegisterAll adds multiple tools to the registry.
@@ -35,12 +69,29 @@ func (r *ToolRegistry) RegisterAll(tools ...core.Tool) {
 	}
 }
 
-// Get retrieves a tool by name.
+// Get retrieves a tool by name, wrapped in its middleware.
 func (r *ToolRegistry) Get(name string) (core.Tool, bool) {
 	r.mu.RLock()
-	defer r.mu.RUnlock()
-	tool, ok := r.tools[name]
-	return tool, ok
+	tool, ok := r.wrapped[name]
+	r.mu.RUnlock()
+	if ok {
+		return tool, true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if tool, ok := r.wrapped[name]; ok {
+		return tool, true
+	}
+	tool, ok = r.tools[name]
+	if !ok {
+		return nil, false
+	}
+	if len(r.global) > 0 || len(r.perTool[name]) > 0 {
+		tool = Chain(r.global...)(Chain(r.perTool[name]...)(tool))
+	}
+	r.wrapped[name] = tool
+	return tool, true
 }
 
 // List returns all registered tool names.
@@ -56,34 +107,7 @@ func (r *ToolRegistry) List() []string {
 
 // ToAPITools converts registered tools to Claude API format.
 func (r *ToolRegistry) ToAPITools() []anthropic.ToolUnionParam {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	tools := make([]anthropic.ToolUnionParam, 0, len(r.tools))
-	for _, tool := range r.tools {
-		schema := tool.Schema()
-		properties, _ := schema["properties"].(map[string]interface{})
-		required := []string{}
-		if reqField, ok := schema["required"].([]interface{}); ok {
-			for _, r := range reqField {
-				if str, ok := r.(string); ok {
-					required = append(required, str)
-				}
-			}
-		}
-
-		tools = append(tools, anthropic.ToolUnionParam{
-			OfTool: &anthropic.ToolParam{
-				Name:        tool.Name(),
-				Description: anthropic.String(tool.Description()),
-				InputSchema: anthropic.ToolInputSchemaParam{
-					Properties: properties,
-					Required:   required,
-				},
-			},
-		})
-	}
-	return tools
+	return r.ToAPIToolsFiltered(func(core.Tool) bool { return true })
 }
 
 // ToAPIToolsFiltered returns tools matching the filter.
@@ -91,28 +115,14 @@ func (r *ToolRegistry) ToAPIToolsFiltered(filter func(core.Tool) bool) []anthrop
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	var tools []anthropic.ToolUnionParam
+	tools := make([]anthropic.ToolUnionParam, 0, len(r.tools))
 	for _, tool := range r.tools {
 		if filter(tool) {
-			schema := tool.Schema()
-			properties, _ := schema["properties"].(map[string]interface{})
-			required := []string{}
-			if reqField, ok := schema["required"].([]interface{}); ok {
-				for _, r := range reqField {
-					if str, ok := r.(string); ok {
-						required = append(required, str)
-					}
-				}
-			}
-
 			tools = append(tools, anthropic.ToolUnionParam{
 				OfTool: &anthropic.ToolParam{
 					Name:        tool.Name(),
 					Description: anthropic.String(tool.Description()),
-					InputSchema: anthropic.ToolInputSchemaParam{
-						Properties: properties,
-						Required:   required,
-					},
+					InputSchema: ToInputSchema(tool.Schema()),
 				},
 			})
 		}
@@ -120,6 +130,39 @@ func (r *ToolRegistry) ToAPIToolsFiltered(filter func(core.Tool) bool) []anthrop
 	return tools
 }
 
+// ToInputSchema converts a tool's JSON Schema to the API's input schema.
+// Keywords other than properties and required, such as additionalProperties
+// or oneOf, are passed through unchanged.
+func ToInputSchema(schema map[string]interface{}) anthropic.ToolInputSchemaParam {
+	param := anthropic.ToolInputSchemaParam{}
+	extra := map[string]any{}
+	for key, value := range schema {
+		switch key {
+		case "type":
+			// Always "object"
+		case "properties":
+			param.Properties = value
+		case "required":
+			switch required := value.(type) {
+			case []string:
+				param.Required = required
+			case []interface{}:
+				for _, r := range required {
+					if str, ok := r.(string); ok {
+						param.Required = append(param.Required, str)
+					}
+				}
+			}
+		default:
+			extra[key] = value
+		}
+	}
+	if len(extra) > 0 {
+		param.ExtraFields = extra
+	}
+	return param
+}
+
 // FilterByNames returns a filter that matches tools by name.
 func FilterByNames(names ...string) func(core.Tool) bool {
 	nameSet := make(map[string]bool)
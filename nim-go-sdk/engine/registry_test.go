@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+func TestToAPIToolsPassesFullSchema(t *testing.T) {
+	registry := NewToolRegistry()
+	registry.Register(core.NewBaseTool(core.ToolDefinition{
+		ToolName:        "pay_invoice",
+		ToolDescription: "Pay an invoice",
+		InputSchema: tools.With(tools.ObjectSchema(map[string]interface{}{
+			"amount": tools.MoneyProperty("Amount", 2),
+			"payee": tools.ObjectProperty("Who to pay", map[string]interface{}{
+				"email": tools.EmailProperty("Payee email"),
+			}, []string{"email"}),
+			"due": tools.OneOfProperty("When to pay",
+				tools.DateProperty("Due date"),
+				tools.StringEnumProperty("Relative date", "today", "tomorrow"),
+			),
+			"retries": tools.IntegerProperty("Retry count", tools.Minimum(0), tools.Maximum(3), tools.Default(1)),
+		}, "amount", "payee"), tools.NoAdditionalProperties()),
+	}, nil))
+
+	apiTools := registry.ToAPITools()
+	if len(apiTools) != 1 {
+		t.Fatalf("expected 1 tool, got %d", len(apiTools))
+	}
+	data, err := json.Marshal(apiTools[0].OfTool.InputSchema)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+
+	want := `{"additionalProperties":false,"properties":{` +
+		`"amount":{"description":"Amount","format":"decimal","pattern":"^\\d+(\\.\\d{1,2})?$","type":"string"},` +
+		`"due":{"description":"When to pay","oneOf":[{"description":"Due date","format":"date","type":"string"},{"description":"Relative date","enum":["today","tomorrow"],"type":"string"}]},` +
+		`"payee":{"description":"Who to pay","properties":{"email":{"description":"Payee email","format":"email","type":"string"}},"required":["email"],"type":"object"},` +
+		`"retries":{"default":1,"description":"Retry count","maximum":3,"minimum":0,"type":"integer"}},` +
+		`"required":["amount","payee"],"type":"object"}`
+	if got := normalizeJSON(t, data); got != normalizeJSON(t, []byte(want)) {
+		t.Errorf("unexpected schema:\n got %s\nwant %s", got, want)
+	}
+}
+
+func normalizeJSON(t *testing.T, data []byte) string {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("invalid JSON %s: %v", data, err)
+	}
+	out, _ := json.Marshal(v)
+	return string(out)
+}
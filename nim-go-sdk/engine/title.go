@@ -26,22 +26,23 @@ func (e *Engine) GenerateTitle(ctx context.Context, history []core.Message) (str
 		return "New conversation", nil
 	}
 
-	// Convert history to API format
+	// Convert history to API format, using text from content blocks as well
+	// as plain content. Tool calls and results carry no text and are skipped.
 	messages := make([]anthropic.MessageParam, 0, len(history))
 	for _, msg := range history {
+		text := msg.GetText()
+		if text == "" {
+			continue
+		}
 		switch msg.Role {
 		case core.RoleUser:
-			if msg.Content != "" {
-				messages = append(messages, anthropic.NewUserMessage(
-					anthropic.NewTextBlock(msg.Content),
-				))
-			}
+			messages = append(messages, anthropic.NewUserMessage(
+				anthropic.NewTextBlock(text),
+			))
 		case core.RoleAssistant:
-			if msg.Content != "" {
-				messages = append(messages, anthropic.NewAssistantMessage(
-					anthropic.NewTextBlock(msg.Content),
-				))
-			}
+			messages = append(messages, anthropic.NewAssistantMessage(
+				anthropic.NewTextBlock(text),
+			))
 		}
 	}
 
@@ -54,9 +55,14 @@ func (e *Engine) GenerateTitle(ctx context.Context, history []core.Message) (str
 		anthropic.NewTextBlock("Based on this conversation, generate a short title (3-6 words):"),
 	))
 
-	// Use a smaller model for cost efficiency
+	// Use a smaller model for cost efficiency unless overridden
+	model := anthropic.ModelClaude3_5HaikuLatest
+	if e.titleModel != "" {
+		model = anthropic.Model(e.titleModel)
+	}
+
 	params := anthropic.MessageNewParams{
-		Model:     anthropic.ModelClaude3_5HaikuLatest,
+		Model:     model,
 		MaxTokens: 50, // Titles are short
 		Messages:  messages,
 		System: []anthropic.TextBlockParam{
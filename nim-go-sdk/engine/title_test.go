@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/becomeliminal/nim-go-sdk/anthropictest"
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+func TestGenerateTitle(t *testing.T) {
+	fake := anthropictest.NewServer(anthropictest.Text(`"Balance check."`))
+	defer fake.Close()
+	client := anthropic.NewClient(option.WithAPIKey("test-key"), option.WithBaseURL(fake.URL))
+	e := NewEngine(&client, NewToolRegistry(), WithTitleModel("claude-title"))
+
+	title, err := e.GenerateTitle(context.Background(), []core.Message{
+		core.NewUserMessage("What's my balance?"),
+		{Role: core.RoleAssistant, ContentBlocks: []core.ContentBlock{
+			{Type: core.TextBlockType, Text: "Let me check."},
+			{Type: core.ToolUseBlockType, ToolUse: &core.ToolUseContent{ID: "tool_1", Name: "get_balance", Input: json.RawMessage(`{}`)}},
+		}},
+		{Role: core.RoleUser, ContentBlocks: []core.ContentBlock{
+			{Type: core.ToolResultBlockType, ToolResult: &core.ToolResultContent{ToolUseID: "tool_1", Content: "SECRET 42.00"}},
+		}},
+		{Role: core.RoleAssistant, ContentBlocks: []core.ContentBlock{
+			{Type: core.TextBlockType, Text: "You have "},
+			{Type: core.TextBlockType, Text: "42.00 USD."},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateTitle failed: %v", err)
+	}
+	if title != "Balance check" {
+		t.Errorf("expected quotes and punctuation to be trimmed, got %q", title)
+	}
+
+	req := fake.Requests()[0]
+	if req.Model != "claude-title" {
+		t.Errorf("expected the title model, got %q", req.Model)
+	}
+	// Text blocks are kept, tool calls and results are dropped
+	if len(req.Messages) != 4 {
+		t.Fatalf("expected 3 text messages and the title request, got %d", len(req.Messages))
+	}
+	for i, want := range []string{"What's my balance?", "Let me check.", "You have 42.00 USD."} {
+		if !strings.Contains(string(req.Messages[i]), want) {
+			t.Errorf("message %d = %s, want %q", i, req.Messages[i], want)
+		}
+	}
+	if body := string(req.Body); strings.Contains(body, "SECRET") || strings.Contains(body, "get_balance") {
+		t.Errorf("expected tool blocks to be dropped: %s", body)
+	}
+
+	title, err = e.GenerateTitle(context.Background(), []core.Message{
+		{Role: core.RoleUser, ContentBlocks: []core.ContentBlock{{Type: core.ToolResultBlockType, ToolResult: &core.ToolResultContent{Content: "{}"}}}},
+	})
+	if err != nil || title != "New conversation" || len(fake.Requests()) != 1 {
+		t.Errorf("expected the default title without calling the API, got %q, %v", title, err)
+	}
+}
@@ -3,7 +3,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"log"
 	"os"
 
@@ -11,6 +10,11 @@ import (
 	"github.com/becomeliminal/nim-go-sdk/tools"
 )
 
+// WeatherInput is the get_weather tool's input; its schema is derived from the tags.
+type WeatherInput struct {
+	Location string `json:"location" description:"The city name (e.g., 'San Francisco')"`
+}
+
 func main() {
 	// Get API key from environment
 	anthropicKey := os.Getenv("ANTHROPIC_API_KEY")
@@ -30,25 +34,16 @@ Be conversational and helpful.`,
 	}
 
 	// Add a custom tool
-	weatherTool := tools.New("get_weather").
+	weatherTool := tools.Typed("get_weather", func(ctx context.Context, in WeatherInput) (map[string]interface{}, error) {
+		// In a real app, call a weather API
+		return map[string]interface{}{
+			"location":    in.Location,
+			"temperature": "72°F",
+			"conditions":  "Sunny",
+			"humidity":    "45%",
+		}, nil
+	}).
 		Description("Get the current weather for a location").
-		Schema(tools.ObjectSchema(map[string]interface{}{
-			"location": tools.StringProperty("The city name (e.g., 'San Francisco')"),
-		}, "location")).
-		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
-			var params struct {
-				Location string `json:"location"`
-			}
-			json.Unmarshal(input, &params)
-
-			// In a real app, call a weather API
-			return map[string]interface{}{
-				"location":    params.Location,
-				"temperature": "72°F",
-				"conditions":  "Sunny",
-				"humidity":    "45%",
-			}, nil
-		}).
 		Build()
 
 	srv.AddTool(weatherTool)
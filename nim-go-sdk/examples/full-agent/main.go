@@ -11,6 +11,7 @@ import (
 
 	"github.com/becomeliminal/nim-go-sdk/core"
 	"github.com/becomeliminal/nim-go-sdk/executor"
+	"github.com/becomeliminal/nim-go-sdk/fx"
 	"github.com/becomeliminal/nim-go-sdk/server"
 	"github.com/becomeliminal/nim-go-sdk/tools"
 )
@@ -52,7 +53,17 @@ func main() {
 
 	// Add custom tools
 	srv.AddTool(createThinkTool())
-	srv.AddTool(createCalculateTool())
+	srv.AddTool(tools.NewCalculatorTool())
+
+	// Add currency conversion if a rate file is configured
+	if path := os.Getenv("FX_RATES_FILE"); path != "" {
+		rates, err := fx.LoadFile(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		srv.AddTool(fx.NewConvertTool(rates))
+		log.Printf("Exchange rates loaded from %s", path)
+	}
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -110,29 +121,6 @@ func createThinkTool() core.Tool {
 		Build()
 }
 
-// createCalculateTool creates a simple calculator tool.
-func createCalculateTool() core.Tool {
-	return tools.New("calculate").
-		Description("Perform basic arithmetic calculations").
-		Schema(tools.ObjectSchema(map[string]interface{}{
-			"expression": tools.StringProperty("Mathematical expression (e.g., '100 + 50 * 0.05')"),
-		}, "expression")).
-		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
-			var params struct {
-				Expression string `json:"expression"`
-			}
-			json.Unmarshal(input, &params)
-
-			// For safety, we'd use a proper expression parser in production
-			// This is just a placeholder
-			return map[string]interface{}{
-				"expression": params.Expression,
-				"note":       "Expression received - implement safe evaluation",
-			}, nil
-		}).
-		Build()
-}
-
 const nimSystemPrompt = `You are Nim, a friendly financial assistant for Liminal.
 
 CONVERSATIONAL GUIDELINES:
@@ -40,7 +40,7 @@ export default function Dashboard() {
     const emps = employeesQuery.data ?? []
 
     const rows = emps.map((e, idx) => {
-      const wageNum = e.wage
+      const wageNum = Number(e.wage)
       return {
         key: e.id ? String(e.id) : `${e.recipient ?? 'emp'}-${idx}`,
         name: `${e.firstName} ${e.lastName}`.trim(),
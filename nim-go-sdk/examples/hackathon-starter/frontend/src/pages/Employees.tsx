@@ -3,7 +3,7 @@ import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query'
 import type { Employee } from '../types/payroll'
 import { listEmployees, createEmployee, deleteEmployee } from '../api/employees'
 
-const empty: Omit<Employee, 'id'> = { firstName: '', lastName: '', recipient: '', wage: 0, department: '' }
+const empty: Omit<Employee, 'id'> = { firstName: '', lastName: '', recipient: '', wage: '', department: '' }
 
 export default function Employees() {
   const qc = useQueryClient()
@@ -32,7 +32,7 @@ export default function Employees() {
       form.firstName.trim().length > 0 &&
       form.lastName.trim().length > 0 &&
       form.recipient.trim().length > 0 &&
-      form.wage > 0 &&
+      Number(form.wage) > 0 &&
       form.department.trim().length > 0
     )
   }, [form])
@@ -61,8 +61,9 @@ export default function Employees() {
           <input
             placeholder="Wage"
             type="number"
-            value={form.wage || ''}
-            onChange={(e) => setForm((f) => ({ ...f, wage: parseFloat(e.target.value) || 0 }))}
+            step="0.01"
+            value={form.wage}
+            onChange={(e) => setForm((f) => ({ ...f, wage: e.target.value }))}
           />
           <input
             placeholder="Department"
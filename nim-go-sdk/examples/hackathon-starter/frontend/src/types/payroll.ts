@@ -3,7 +3,7 @@ export type Employee = {
   firstName: string
   lastName: string
   recipient: string
-  wage: number
+  wage: string // exact decimal in USD, e.g. "85000.00"
   department: string
 }
 
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/becomeliminal/nim-go-sdk/core/money"
 	"github.com/becomeliminal/nim-go-sdk/examples/hackathon-starter/internal/storage"
 )
 
@@ -16,8 +17,8 @@ func TestListEmployees(t *testing.T) {
 	defer db.Close()
 
 	// Create test data
-	emp1 := &storage.Employee{FirstName: "Alice", LastName: "Smith", Recipient: "@alice", Wage: 75000, Department: "Engineering"}
-	emp2 := &storage.Employee{FirstName: "Bob", LastName: "Jones", Recipient: "@bob", Wage: 80000, Department: "Sales"}
+	emp1 := &storage.Employee{FirstName: "Alice", LastName: "Smith", Recipient: "@alice", Wage: money.NewDecimal(75000, 0), Department: "Engineering"}
+	emp2 := &storage.Employee{FirstName: "Bob", LastName: "Jones", Recipient: "@bob", Wage: money.NewDecimal(80000, 0), Department: "Sales"}
 
 	id1, err := db.CreateEmployee(emp1)
 	if err != nil {
@@ -63,7 +64,7 @@ func TestCreateEmployee(t *testing.T) {
 		FirstName:  "Charlie",
 		LastName:   "Brown",
 		Recipient:  "@charlie",
-		Wage:       85000,
+		Wage:       money.NewDecimal(85000, 0),
 		Department: "Engineering",
 	}
 
@@ -102,7 +103,7 @@ func TestGetEmployee(t *testing.T) {
 	defer db.Close()
 
 	// Create test data
-	emp := &storage.Employee{FirstName: "Diana", LastName: "Prince", Recipient: "@diana", Wage: 90000, Department: "Management"}
+	emp := &storage.Employee{FirstName: "Diana", LastName: "Prince", Recipient: "@diana", Wage: money.NewDecimal(90000, 0), Department: "Management"}
 	id, err := db.CreateEmployee(emp)
 	if err != nil {
 		t.Fatalf("failed to create employee fixture: %v", err)
@@ -138,7 +139,7 @@ func TestUpdateEmployee(t *testing.T) {
 	defer db.Close()
 
 	// Create test data
-	emp := &storage.Employee{FirstName: "Eve", LastName: "Wilson", Recipient: "@eve", Wage: 70000, Department: "HR"}
+	emp := &storage.Employee{FirstName: "Eve", LastName: "Wilson", Recipient: "@eve", Wage: money.NewDecimal(70000, 0), Department: "HR"}
 	id, err := db.CreateEmployee(emp)
 	if err != nil {
 		t.Fatalf("failed to create employee fixture: %v", err)
@@ -151,7 +152,7 @@ func TestUpdateEmployee(t *testing.T) {
 		FirstName:  "Eve",
 		LastName:   "Wilson",
 		Recipient:  "@eve",
-		Wage:       75000, // Changed wage
+		Wage:       money.NewDecimal(75000, 0), // Changed wage
 		Department: "HR",
 	}
 
@@ -173,11 +174,11 @@ func TestUpdateEmployee(t *testing.T) {
 	var result storage.Employee
 	json.NewDecoder(w.Body).Decode(&result)
 
-	if result.Wage != 75000 {
+	if !result.Wage.Equal(money.NewDecimal(75000, 0)) {
 		t.Errorf("expected wage 75000, got %v", result.Wage)
 	}
 
-	t.Logf("✓ Updated employee (ID: %d, New wage: %.0f)", id, result.Wage)
+	t.Logf("✓ Updated employee (ID: %d, New wage: %s)", id, result.Wage)
 }
 
 // TestDeleteEmployee tests the DELETE /api/employees/{id} endpoint
@@ -186,7 +187,7 @@ func TestDeleteEmployee(t *testing.T) {
 	defer db.Close()
 
 	// Create test data
-	emp := &storage.Employee{FirstName: "Frank", LastName: "Miller", Recipient: "@frank", Wage: 80000, Department: "Engineering"}
+	emp := &storage.Employee{FirstName: "Frank", LastName: "Miller", Recipient: "@frank", Wage: money.NewDecimal(80000, 0), Department: "Engineering"}
 	id, err := db.CreateEmployee(emp)
 	if err != nil {
 		t.Fatalf("failed to create employee fixture: %v", err)
@@ -222,9 +223,9 @@ func TestListEmployeesByDepartment(t *testing.T) {
 	defer db.Close()
 
 	// Create test data
-	emp1 := &storage.Employee{FirstName: "Grace", LastName: "Lee", Recipient: "@grace", Wage: 85000, Department: "Engineering"}
-	emp2 := &storage.Employee{FirstName: "Henry", LastName: "Chen", Recipient: "@henry", Wage: 80000, Department: "Engineering"}
-	emp3 := &storage.Employee{FirstName: "Iris", LastName: "Kumar", Recipient: "@iris", Wage: 70000, Department: "Sales"}
+	emp1 := &storage.Employee{FirstName: "Grace", LastName: "Lee", Recipient: "@grace", Wage: money.NewDecimal(85000, 0), Department: "Engineering"}
+	emp2 := &storage.Employee{FirstName: "Henry", LastName: "Chen", Recipient: "@henry", Wage: money.NewDecimal(80000, 0), Department: "Engineering"}
+	emp3 := &storage.Employee{FirstName: "Iris", LastName: "Kumar", Recipient: "@iris", Wage: money.NewDecimal(70000, 0), Department: "Sales"}
 
 	db.CreateEmployee(emp1)
 	db.CreateEmployee(emp2)
@@ -308,7 +309,7 @@ func TestCreateEmployeeInvalidData(t *testing.T) {
 		FirstName:  "Jack",
 		LastName:   "", // Missing last name
 		Recipient:  "@jack",
-		Wage:       80000,
+		Wage:       money.NewDecimal(80000, 0),
 		Department: "Sales",
 	}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -44,17 +45,26 @@ func NewDB(path string) (*DB, error) {
 	return &DB{conn: db}, nil
 }
 
-// initializeSchema creates the employees table if it doesn't exist
-func initializeSchema(ctx context.Context, db *sql.DB) error {
-	query := `
+// employeesTable is the employees table's definition. Wages are stored as
+// exact decimal strings.
+const employeesTable = `
 	CREATE TABLE IF NOT EXISTS employees (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		first_name TEXT NOT NULL,
 		last_name TEXT NOT NULL,
 		recipient TEXT UNIQUE NOT NULL,
-		wage REAL NOT NULL DEFAULT 0,
+		wage TEXT NOT NULL DEFAULT '0',
 		department TEXT NOT NULL DEFAULT ''
-	);
+	);`
+
+// initializeSchema creates the employees table if it doesn't exist, and
+// migrates tables created when wages were REAL.
+func initializeSchema(ctx context.Context, db *sql.DB) error {
+	if err := migrateWages(ctx, db); err != nil {
+		return fmt.Errorf("error migrating employees table: %w", err)
+	}
+
+	query := employeesTable + `
 	CREATE INDEX IF NOT EXISTS idx_employees_recipient ON employees(recipient);
 	CREATE INDEX IF NOT EXISTS idx_employees_department ON employees(department);
 	`
@@ -66,6 +76,41 @@ func initializeSchema(ctx context.Context, db *sql.DB) error {
 	return nil
 }
 
+// migrateWages rebuilds an employees table whose wage column is REAL, so
+// its wages are stored as decimal strings like those written since. SQLite
+// can't change a column's type in place.
+func migrateWages(ctx context.Context, db *sql.DB) error {
+	var wageType string
+	err := db.QueryRowContext(ctx, `SELECT type FROM pragma_table_info('employees') WHERE name = 'wage'`).Scan(&wageType)
+	if err == sql.ErrNoRows || strings.EqualFold(wageType, "TEXT") {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Dropping the old table drops its indexes; initializeSchema recreates them
+	steps := []string{
+		`ALTER TABLE employees RENAME TO employees_old`,
+		employeesTable,
+		`INSERT INTO employees (id, first_name, last_name, recipient, wage, department)
+		SELECT id, first_name, last_name, recipient, CAST(wage AS TEXT), department FROM employees_old`,
+		`DROP TABLE employees_old`,
+	}
+	for _, step := range steps {
+		if _, err := tx.ExecContext(ctx, step); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
 // Close closes the database connection
 func (d *DB) Close() error {
 	if d.conn != nil {
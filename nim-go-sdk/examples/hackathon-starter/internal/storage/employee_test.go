@@ -1,8 +1,11 @@
 package storage
 
 import (
+	"database/sql"
 	"path/filepath"
 	"testing"
+
+	"github.com/becomeliminal/nim-go-sdk/core/money"
 )
 
 func newTestDB(t *testing.T) *DB {
@@ -28,7 +31,7 @@ func TestEmployeeCRUD(t *testing.T) {
 		FirstName:  "Ada",
 		LastName:   "Lovelace",
 		Recipient:  "@ada",
-		Wage:       123.45,
+		Wage:       money.MustParseDecimal("123.45"),
 		Department: "Engineering",
 	}
 
@@ -44,7 +47,7 @@ func TestEmployeeCRUD(t *testing.T) {
 	if err != nil {
 		t.Fatalf("GetEmployee failed: %v", err)
 	}
-	if got.FirstName != emp.FirstName || got.LastName != emp.LastName || got.Recipient != emp.Recipient || got.Wage != emp.Wage || got.Department != emp.Department {
+	if got.FirstName != emp.FirstName || got.LastName != emp.LastName || got.Recipient != emp.Recipient || !got.Wage.Equal(emp.Wage) || got.Department != emp.Department {
 		t.Fatalf("GetEmployee mismatch: got=%+v want=%+v", got, emp)
 	}
 
@@ -57,7 +60,7 @@ func TestEmployeeCRUD(t *testing.T) {
 	}
 
 	got.Recipient = "@ada-updated"
-	got.Wage = 200.00
+	got.Wage = money.MustParseDecimal("200.00")
 	got.Department = "Research"
 	if err := db.UpdateEmployee(got); err != nil {
 		t.Fatalf("UpdateEmployee failed: %v", err)
@@ -67,7 +70,7 @@ func TestEmployeeCRUD(t *testing.T) {
 	if err != nil {
 		t.Fatalf("GetEmployee after update failed: %v", err)
 	}
-	if updated.Recipient != "@ada-updated" || updated.Wage != 200.00 || updated.Department != "Research" {
+	if updated.Recipient != "@ada-updated" || !updated.Wage.Equal(money.MustParseDecimal("200.00")) || updated.Department != "Research" {
 		t.Fatalf("UpdateEmployee mismatch: got=%+v", updated)
 	}
 
@@ -99,7 +102,7 @@ func TestEmployeeValidation(t *testing.T) {
 				FirstName:  "John",
 				LastName:   "Doe",
 				Recipient:  "@john",
-				Wage:       50000,
+				Wage:       money.NewDecimal(50000, 0),
 				Department: "Sales",
 			},
 			wantErr: false,
@@ -110,7 +113,7 @@ func TestEmployeeValidation(t *testing.T) {
 				FirstName:  "",
 				LastName:   "Doe",
 				Recipient:  "@john",
-				Wage:       50000,
+				Wage:       money.NewDecimal(50000, 0),
 				Department: "Sales",
 			},
 			wantErr: true,
@@ -121,7 +124,7 @@ func TestEmployeeValidation(t *testing.T) {
 				FirstName:  "John",
 				LastName:   "",
 				Recipient:  "@john",
-				Wage:       50000,
+				Wage:       money.NewDecimal(50000, 0),
 				Department: "Sales",
 			},
 			wantErr: true,
@@ -132,7 +135,7 @@ func TestEmployeeValidation(t *testing.T) {
 				FirstName:  "John",
 				LastName:   "Doe",
 				Recipient:  "",
-				Wage:       50000,
+				Wage:       money.NewDecimal(50000, 0),
 				Department: "Sales",
 			},
 			wantErr: true,
@@ -143,7 +146,18 @@ func TestEmployeeValidation(t *testing.T) {
 				FirstName:  "John",
 				LastName:   "Doe",
 				Recipient:  "@john",
-				Wage:       -100,
+				Wage:       money.NewDecimal(-100, 0),
+				Department: "Sales",
+			},
+			wantErr: true,
+		},
+		{
+			name: "fractional cent wage",
+			emp: &Employee{
+				FirstName:  "John",
+				LastName:   "Doe",
+				Recipient:  "@john",
+				Wage:       money.MustParseDecimal("50000.005"),
 				Department: "Sales",
 			},
 			wantErr: true,
@@ -154,7 +168,7 @@ func TestEmployeeValidation(t *testing.T) {
 				FirstName:  "John",
 				LastName:   "Doe",
 				Recipient:  "@john",
-				Wage:       50000,
+				Wage:       money.NewDecimal(50000, 0),
 				Department: "",
 			},
 			wantErr: true,
@@ -199,9 +213,9 @@ func TestGetEmployeesByDepartment(t *testing.T) {
 
 	// Create multiple employees in different departments
 	emps := []*Employee{
-		{FirstName: "Alice", LastName: "Smith", Recipient: "@alice", Wage: 80000, Department: "Engineering"},
-		{FirstName: "Bob", LastName: "Jones", Recipient: "@bob", Wage: 70000, Department: "Engineering"},
-		{FirstName: "Charlie", LastName: "Brown", Recipient: "@charlie", Wage: 60000, Department: "Sales"},
+		{FirstName: "Alice", LastName: "Smith", Recipient: "@alice", Wage: money.NewDecimal(80000, 0), Department: "Engineering"},
+		{FirstName: "Bob", LastName: "Jones", Recipient: "@bob", Wage: money.NewDecimal(70000, 0), Department: "Engineering"},
+		{FirstName: "Charlie", LastName: "Brown", Recipient: "@charlie", Wage: money.NewDecimal(60000, 0), Department: "Sales"},
 	}
 
 	for _, emp := range emps {
@@ -234,3 +248,65 @@ func TestGetEmployeesByDepartment(t *testing.T) {
 		t.Fatalf("GetEmployeesByDepartment expected error for empty department")
 	}
 }
+
+func TestNewDBMigratesRealWages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "employees.db")
+	old, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = old.Exec(`
+	CREATE TABLE employees (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		first_name TEXT NOT NULL,
+		last_name TEXT NOT NULL,
+		recipient TEXT UNIQUE NOT NULL,
+		wage REAL NOT NULL DEFAULT 0,
+		department TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX idx_employees_recipient ON employees(recipient);
+	INSERT INTO employees (id, first_name, last_name, recipient, wage, department)
+	VALUES (7, 'Ada', 'Lovelace', '@ada', 1234.56, 'Engineering');`)
+	old.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := NewDB(path)
+	if err != nil {
+		t.Fatalf("NewDB failed: %v", err)
+	}
+	defer db.Close()
+
+	var wageType string
+	if err := db.conn.QueryRow(`SELECT typeof(wage) FROM employees WHERE id = 7`).Scan(&wageType); err != nil || wageType != "text" {
+		t.Fatalf("stored wage type = %q, %v; want text", wageType, err)
+	}
+	got, err := db.GetEmployee(7)
+	if err != nil {
+		t.Fatalf("GetEmployee failed: %v", err)
+	}
+	if !got.Wage.Equal(money.MustParseDecimal("1234.56")) || got.Recipient != "@ada" {
+		t.Errorf("migrated employee = %+v", got)
+	}
+
+	// New rows follow the migrated ones, and recipients stay unique
+	id, err := db.CreateEmployee(&Employee{FirstName: "Grace", LastName: "Hopper", Recipient: "@grace", Wage: money.MustParseDecimal("10.00"), Department: "Research"})
+	if err != nil || id != 8 {
+		t.Errorf("CreateEmployee = %d, %v; want 8", id, err)
+	}
+	if _, err := db.CreateEmployee(&Employee{FirstName: "Ada", LastName: "Again", Recipient: "@ada", Wage: money.MustParseDecimal("1.00"), Department: "Research"}); err == nil {
+		t.Error("expected a duplicate recipient to be refused")
+	}
+
+	// Opening the migrated database again leaves it alone
+	db.Close()
+	reopened, err := NewDB(path)
+	if err != nil {
+		t.Fatalf("reopening failed: %v", err)
+	}
+	defer reopened.Close()
+	if employees, err := reopened.ListEmployees(); err != nil || len(employees) != 2 {
+		t.Errorf("ListEmployees = %v, %v", employees, err)
+	}
+}
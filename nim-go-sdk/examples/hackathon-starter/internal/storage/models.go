@@ -3,16 +3,26 @@ package storage
 import (
 	"fmt"
 	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core/money"
 )
 
+// WageCurrency is the currency employees are paid in
+const WageCurrency = "USD"
+
 // Employee represents an employee record in the database
 type Employee struct {
-	ID         int     `db:"id" json:"id"`
-	FirstName  string  `db:"first_name" json:"firstName"`
-	LastName   string  `db:"last_name" json:"lastName"`
-	Recipient  string  `db:"recipient" json:"recipient"`
-	Wage       float64 `db:"wage" json:"wage"`
-	Department string  `db:"department" json:"department"`
+	ID         int           `db:"id" json:"id"`
+	FirstName  string        `db:"first_name" json:"firstName"`
+	LastName   string        `db:"last_name" json:"lastName"`
+	Recipient  string        `db:"recipient" json:"recipient"`
+	Wage       money.Decimal `db:"wage" json:"wage"`
+	Department string        `db:"department" json:"department"`
+}
+
+// WageAmount returns the employee's wage in WageCurrency
+func (e *Employee) WageAmount() money.Amount {
+	return money.New(e.Wage, WageCurrency)
 }
 
 // Validate checks if an Employee has valid field values
@@ -26,9 +36,12 @@ func (e *Employee) Validate() error {
 	if strings.TrimSpace(e.Recipient) == "" {
 		return fmt.Errorf("recipient cannot be empty")
 	}
-	if e.Wage < 0 {
+	if e.Wage.Sign() < 0 {
 		return fmt.Errorf("wage cannot be negative")
 	}
+	if p := money.Precision(WageCurrency); e.Wage.Scale() > p && !e.Wage.Round(p, money.RoundDown).Equal(e.Wage) {
+		return fmt.Errorf("wage cannot have more than %d decimal places", p)
+	}
 	if strings.TrimSpace(e.Department) == "" {
 		return fmt.Errorf("department cannot be empty")
 	}
@@ -5,6 +5,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -12,10 +13,12 @@ import (
 	"os"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/core/money"
 	"github.com/becomeliminal/nim-go-sdk/examples/hackathon-starter/internal/api"
 	"github.com/becomeliminal/nim-go-sdk/examples/hackathon-starter/internal/storage"
 	"github.com/becomeliminal/nim-go-sdk/executor"
@@ -54,6 +57,9 @@ func main() {
 		employeesDBPath = "employees.db"
 	}
 
+	// Optional YAML/JSON file declaring extra REST endpoint tools
+	httpToolsPath := os.Getenv("HTTP_TOOLS_FILE")
+
 	// ============================================================================
 	// LIMINAL EXECUTOR SETUP
 	// ============================================================================
@@ -144,6 +150,16 @@ func main() {
 	srv.AddTool(getCashFlowInsights(liminalExecutor))
 	log.Println("✅ Added custom cash flow insight and projection tools")
 
+	// Declarative REST tools (FX rates, ticketing, CRM lookups, ...)
+	if httpToolsPath != "" {
+		httpTools, err := tools.LoadHTTPTools(httpToolsPath)
+		if err != nil {
+			log.Fatalf("❌ Failed to load HTTP tools: %v", err)
+		}
+		srv.AddTools(httpTools...)
+		log.Printf("✅ Added %d HTTP tools from %s", len(httpTools), httpToolsPath)
+	}
+
 	// TODO: Add more custom tools here!
 	// Examples:
 	//   - Savings goal tracker
@@ -300,42 +316,15 @@ func doPayroll(liminalExecutor core.ToolExecutor, db storage.DB) core.Tool {
 	return tools.New("fulfill_remaining_payroll").
 		Description("does the payroll for all employees").
 		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
-			txRequest := map[string]interface{}{
-				"limit": 100, // Get up to 100 transactions
-			}
-
-			txRequestJSON, _ := json.Marshal(txRequest)
-			txResponse, err := liminalExecutor.Execute(ctx, &core.ExecuteRequest{
-				UserID:    toolParams.UserID,
-				Tool:      "get_transactions",
-				Input:     txRequestJSON,
-				RequestID: toolParams.RequestID,
-			})
+			// Only the last two weeks matter for payroll
+			transactions, truncated, err := fetchTransactions(ctx, liminalExecutor, toolParams.UserID, time.Now().AddDate(0, 0, -14))
 			if err != nil {
 				return &core.ToolResult{
 					Success: false,
 					Error:   fmt.Sprintf("failed to fetch transactions: %v", err),
 				}, nil
 			}
-			if !txResponse.Success {
-				return &core.ToolResult{
-					Success: false,
-					Error:   fmt.Sprintf("transaction fetch failed: %s", txResponse.Error),
-				}, nil
-			}
-			var transactions []map[string]interface{}
-			var txData map[string]interface{}
-			if err := json.Unmarshal(txResponse.Data, &txData); err == nil {
-				if txArray, ok := txData["transactions"].([]interface{}); ok {
-					for _, tx := range txArray {
-						if txMap, ok := tx.(map[string]interface{}); ok {
-							transactions = append(transactions, txMap)
-						}
-					}
-				}
-			}
-			newTransactions := removeOld(transactions)
-			peopleWhoNeedToBePaid := checkPayments(newTransactions, db)
+			peopleWhoNeedToBePaid := checkPayments(transactions, db)
 
 			L, e := (db).ListEmployees()
 			if e != nil {
@@ -345,10 +334,12 @@ func doPayroll(liminalExecutor core.ToolExecutor, db storage.DB) core.Tool {
 			for _, v := range L {
 				if slices.Contains(peopleWhoNeedToBePaid, v.Recipient) {
 
+					// Send the wage as an exact decimal string, never a float
+					wage := v.WageAmount().Round(money.RoundHalfEven)
 					payRequest := map[string]interface{}{
 						"recipient": v.Recipient,
-						"amount":    v.Wage,
-						"currency":  "USD",
+						"amount":    wage.Value,
+						"currency":  wage.Currency,
 						"note":      v.Recipient + " payroll",
 					}
 					paymentRequests = append(paymentRequests, payRequest)
@@ -358,6 +349,9 @@ func doPayroll(liminalExecutor core.ToolExecutor, db storage.DB) core.Tool {
 			result := map[string]interface{}{
 				"payment requests": paymentRequests,
 			}
+			if truncated {
+				result["truncated"] = true
+			}
 			log.Println(result)
 
 			return &core.ToolResult{
@@ -371,45 +365,21 @@ func isPayrollDone(liminalExecutor core.ToolExecutor, db storage.DB) core.Tool {
 		Description("checks if all payroll is done").
 		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
 
-			txRequest := map[string]interface{}{
-				"limit": 100, // Get up to 100 transactions
-			}
-
-			txRequestJSON, _ := json.Marshal(txRequest)
-			txResponse, err := liminalExecutor.Execute(ctx, &core.ExecuteRequest{
-				UserID:    toolParams.UserID,
-				Tool:      "get_transactions",
-				Input:     txRequestJSON,
-				RequestID: toolParams.RequestID,
-			})
+			// Only the last two weeks matter for payroll
+			transactions, truncated, err := fetchTransactions(ctx, liminalExecutor, toolParams.UserID, time.Now().AddDate(0, 0, -14))
 			if err != nil {
 				return &core.ToolResult{
 					Success: false,
 					Error:   fmt.Sprintf("failed to fetch transactions: %v", err),
 				}, nil
 			}
-			if !txResponse.Success {
-				return &core.ToolResult{
-					Success: false,
-					Error:   fmt.Sprintf("transaction fetch failed: %s", txResponse.Error),
-				}, nil
-			}
-			var transactions []map[string]interface{}
-			var txData map[string]interface{}
-			if err := json.Unmarshal(txResponse.Data, &txData); err == nil {
-				if txArray, ok := txData["transactions"].([]interface{}); ok {
-					for _, tx := range txArray {
-						if txMap, ok := tx.(map[string]interface{}); ok {
-							transactions = append(transactions, txMap)
-						}
-					}
-				}
-			}
-			newTransactions := removeOld(transactions)
-			answer := checkPayments(newTransactions, db)
+			answer := checkPayments(transactions, db)
 			result := map[string]interface{}{
 				"people who have not been paid": answer,
 			}
+			if truncated {
+				result["truncated"] = true
+			}
 			return &core.ToolResult{
 				Success: true,
 				Data:    result,
@@ -418,24 +388,48 @@ func isPayrollDone(liminalExecutor core.ToolExecutor, db storage.DB) core.Tool {
 		}).Build()
 
 }
-func removeOld(transactions []map[string]interface{}) []map[string]interface{} {
 
-	var newTransactions []map[string]interface{}
-	for _, v := range transactions {
-		log.Println(v["createdAt"])
-		var timeString string = v["createdAt"].(string)
-		var time, e = time.Parse(time.RFC3339, timeString)
-		if e != nil {
-			log.Println("time not formated correctly")
-		}
-		log.Println("time:", time)
-		log.Println((time).After(time.Local().AddDate(0, 0, -14)))
-		if (time).After(time.Local().AddDate(0, 0, -14)) {
-			newTransactions = append(newTransactions, v)
-		}
+// maxTransactionPages caps how much history custom tools read per call.
+const maxTransactionPages = 10
+
+// fetchTransactions pages through the user's transactions since the given
+// time, up to maxTransactionPages pages, as generic maps for the analysis helpers.
+// truncated reports that the page limit was hit before the history ran out.
+func fetchTransactions(ctx context.Context, liminalExecutor core.ToolExecutor, userID string, since time.Time) (transactions []map[string]interface{}, truncated bool, err error) {
+	txs, err := executor.ListTransactions(ctx, liminalExecutor, userID, executor.TransactionQuery{
+		Limit:     100,
+		StartDate: since.UTC().Format(time.RFC3339),
+	}, maxTransactionPages)
+	truncated = errors.Is(err, executor.ErrMaxPages)
+	if err != nil && !truncated {
+		return nil, false, err
+	}
+
+	// Round-trip through JSON so the helpers below see the API's field names
+	data, err := json.Marshal(txs)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := json.Unmarshal(data, &transactions); err != nil {
+		return nil, false, err
+	}
+	return transactions, truncated, nil
+}
 
+// txnAmount reads an amount sent as an exact decimal string or, from older
+// APIs, a JSON number
+func txnAmount(v interface{}) (money.Decimal, error) {
+	switch v := v.(type) {
+	case string:
+		return money.ParseDecimal(v)
+	case float64:
+		return money.ParseDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+	case int:
+		return money.NewDecimal(int64(v), 0), nil
+	case int64:
+		return money.NewDecimal(v, 0), nil
 	}
-	return newTransactions
+	return money.Decimal{}, fmt.Errorf("invalid amount %v", v)
 }
 
 func checkPayments(transactions []map[string]interface{}, db storage.DB) []string {
@@ -486,11 +480,11 @@ func createEmployeeTools(db *storage.DB) []core.Tool {
 func createEmployeeTool(db *storage.DB) core.Tool {
 	handler := func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
 		var params struct {
-			FirstName  string  `json:"first_name"`
-			LastName   string  `json:"last_name"`
-			Recipient  string  `json:"recipient"`
-			Wage       float64 `json:"wage"`
-			Department string  `json:"department"`
+			FirstName  string        `json:"first_name"`
+			LastName   string        `json:"last_name"`
+			Recipient  string        `json:"recipient"`
+			Wage       money.Decimal `json:"wage"`
+			Department string        `json:"department"`
 		}
 		if err := json.Unmarshal(toolParams.Input, &params); err != nil {
 			return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
@@ -519,7 +513,7 @@ func createEmployeeTool(db *storage.DB) core.Tool {
 			"first_name": tools.StringProperty("Employee first name"),
 			"last_name":  tools.StringProperty("Employee last name"),
 			"recipient":  tools.StringProperty("Employee recipient handle, e.g. @ada"),
-			"wage":       tools.NumberProperty("Employee wage (non-negative)"),
+			"wage":       tools.MoneyProperty("Employee wage in USD, e.g. '85000.00' (non-negative)", 2),
 			"department": tools.StringProperty("Employee department"),
 		})).
 		Handler(handler).
@@ -571,12 +565,12 @@ func listEmployeesTool(db *storage.DB) core.Tool {
 func updateEmployeeTool(db *storage.DB) core.Tool {
 	handler := func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
 		var params struct {
-			ID         int     `json:"id"`
-			FirstName  string  `json:"first_name"`
-			LastName   string  `json:"last_name"`
-			Recipient  string  `json:"recipient"`
-			Wage       float64 `json:"wage"`
-			Department string  `json:"department"`
+			ID         int           `json:"id"`
+			FirstName  string        `json:"first_name"`
+			LastName   string        `json:"last_name"`
+			Recipient  string        `json:"recipient"`
+			Wage       money.Decimal `json:"wage"`
+			Department string        `json:"department"`
 		}
 		if err := json.Unmarshal(toolParams.Input, &params); err != nil {
 			return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
@@ -605,7 +599,7 @@ func updateEmployeeTool(db *storage.DB) core.Tool {
 			"first_name": tools.StringProperty("Employee first name"),
 			"last_name":  tools.StringProperty("Employee last name"),
 			"recipient":  tools.StringProperty("Employee recipient handle, e.g. @ada"),
-			"wage":       tools.NumberProperty("Employee wage (non-negative)"),
+			"wage":       tools.MoneyProperty("Employee wage in USD, e.g. '85000.00' (non-negative)", 2),
 			"department": tools.StringProperty("Employee department"),
 		})).
 		Handler(handler).
@@ -683,43 +677,14 @@ func cashFlowAnalysisTool(liminalExecutor core.ToolExecutor) core.Tool {
 				params.DaysToPredict = 7
 			}
 
-			// Fetch transactions from Liminal API
-			txRequest := map[string]interface{}{
-				"limit": 100, // Get up to 100 transactions for analysis
-			}
-
-			txRequestJSON, _ := json.Marshal(txRequest)
-			txResponse, err := liminalExecutor.Execute(ctx, &core.ExecuteRequest{
-				UserID:    toolParams.UserID,
-				Tool:      "get_transactions",
-				Input:     txRequestJSON,
-				RequestID: toolParams.RequestID,
-			})
+			// Fetch up to 90 days of transactions from Liminal API
+			transactions, truncated, err := fetchTransactions(ctx, liminalExecutor, toolParams.UserID, time.Now().AddDate(0, 0, -90))
 			if err != nil {
 				return &core.ToolResult{
 					Success: false,
 					Error:   fmt.Sprintf("failed to fetch transactions: %v", err),
 				}, nil
 			}
-			if !txResponse.Success {
-				return &core.ToolResult{
-					Success: false,
-					Error:   fmt.Sprintf("transaction fetch failed: %s", txResponse.Error),
-				}, nil
-			}
-
-			// Parse transactions
-			var transactions []map[string]interface{}
-			var txData map[string]interface{}
-			if err := json.Unmarshal(txResponse.Data, &txData); err == nil {
-				if txArray, ok := txData["transactions"].([]interface{}); ok {
-					for _, tx := range txArray {
-						if txMap, ok := tx.(map[string]interface{}); ok {
-							transactions = append(transactions, txMap)
-						}
-					}
-				}
-			}
 
 			if len(transactions) == 0 {
 				return &core.ToolResult{
@@ -738,6 +703,9 @@ func cashFlowAnalysisTool(liminalExecutor core.ToolExecutor) core.Tool {
 					Error:   errMsg,
 				}, nil
 			}
+			if truncated {
+				result["truncated"] = true
+			}
 
 			return &core.ToolResult{
 				Success: true,
@@ -783,40 +751,20 @@ func predictBalanceTool(liminalExecutor core.ToolExecutor) core.Tool {
 			var balanceData map[string]interface{}
 			json.Unmarshal(balanceResponse.Data, &balanceData)
 
-			currentBalance := 0.0
+			var currentBalance money.Decimal
 			if balances, ok := balanceData["balances"].([]interface{}); ok && len(balances) > 0 {
 				if bal, ok := balances[0].(map[string]interface{}); ok {
-					if amount, ok := bal["amount"].(float64); ok {
+					if amount, err := txnAmount(bal["amount"]); err == nil {
 						currentBalance = amount
 					}
 				}
 			}
 
-			// Get cash flow analysis
-			txRequest := map[string]interface{}{"limit": 100}
-			txRequestJSON, _ := json.Marshal(txRequest)
-			txResponse, err := liminalExecutor.Execute(ctx, &core.ExecuteRequest{
-				UserID:    toolParams.UserID,
-				Tool:      "get_transactions",
-				Input:     txRequestJSON,
-				RequestID: toolParams.RequestID,
-			})
-			if err != nil || !txResponse.Success {
+			transactions, truncated, err := fetchTransactions(ctx, liminalExecutor, toolParams.UserID, time.Now().AddDate(0, 0, -90))
+			if err != nil {
 				return &core.ToolResult{Success: false, Error: "failed to fetch transactions"}, nil
 			}
 
-			var transactions []map[string]interface{}
-			var txData map[string]interface{}
-			if err := json.Unmarshal(txResponse.Data, &txData); err == nil {
-				if txArray, ok := txData["transactions"].([]interface{}); ok {
-					for _, tx := range txArray {
-						if txMap, ok := tx.(map[string]interface{}); ok {
-							transactions = append(transactions, txMap)
-						}
-					}
-				}
-			}
-
 			if len(transactions) == 0 {
 				return &core.ToolResult{Success: false, Error: "no transactions available"}, nil
 			}
@@ -833,17 +781,22 @@ func predictBalanceTool(liminalExecutor core.ToolExecutor) core.Tool {
 
 			// Calculate predicted balance change
 			// Daily trend * number of days
-			predictedChange := weight * float64(params.DaysAhead)
-			predictedBalance := currentBalance + predictedChange
+			// The trend is an estimate, so only it goes through float64; the
+			// balance itself stays exact
+			predictedChange, _ := money.ParseDecimal(strconv.FormatFloat(weight*float64(params.DaysAhead), 'f', 2, 64))
+			predictedBalance := currentBalance.Add(predictedChange)
 
 			result := map[string]interface{}{
-				"current_balance":   math.Round(currentBalance*100) / 100,
-				"predicted_balance": math.Round(predictedBalance*100) / 100,
-				"predicted_change":  math.Round(predictedChange*100) / 100,
+				"current_balance":   currentBalance.Round(2, money.RoundHalfEven),
+				"predicted_balance": predictedBalance.Round(2, money.RoundHalfEven),
+				"predicted_change":  predictedChange,
 				"days_ahead":        params.DaysAhead,
 				"daily_trend":       math.Round(weight*100) / 100,
 				"trend_direction":   getTrendDirection(weight),
 			}
+			if truncated {
+				result["truncated"] = true
+			}
 
 			return &core.ToolResult{Success: true, Data: result}, nil
 		}).
@@ -856,31 +809,11 @@ func getCashFlowInsights(liminalExecutor core.ToolExecutor) core.Tool {
 		Description("Get natural language insights about cash flow patterns, trends, and recommendations based on regression analysis.").
 		Schema(tools.ObjectSchema(map[string]interface{}{})).
 		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
-			// Get transactions
-			txRequest := map[string]interface{}{"limit": 100}
-			txRequestJSON, _ := json.Marshal(txRequest)
-			txResponse, err := liminalExecutor.Execute(ctx, &core.ExecuteRequest{
-				UserID:    toolParams.UserID,
-				Tool:      "get_transactions",
-				Input:     txRequestJSON,
-				RequestID: toolParams.RequestID,
-			})
-			if err != nil || !txResponse.Success {
+			transactions, truncated, err := fetchTransactions(ctx, liminalExecutor, toolParams.UserID, time.Now().AddDate(0, 0, -90))
+			if err != nil {
 				return &core.ToolResult{Success: false, Error: "failed to fetch transactions"}, nil
 			}
 
-			var transactions []map[string]interface{}
-			var txData map[string]interface{}
-			if err := json.Unmarshal(txResponse.Data, &txData); err == nil {
-				if txArray, ok := txData["transactions"].([]interface{}); ok {
-					for _, tx := range txArray {
-						if txMap, ok := tx.(map[string]interface{}); ok {
-							transactions = append(transactions, txMap)
-						}
-					}
-				}
-			}
-
 			if len(transactions) == 0 {
 				return &core.ToolResult{Success: false, Error: "no transactions available"}, nil
 			}
@@ -895,8 +828,8 @@ func getCashFlowInsights(liminalExecutor core.ToolExecutor) core.Tool {
 			modelData, _ := analysis["model"].(map[string]interface{})
 
 			trend, _ := insights["trend"].(string)
-			avgPerDay, _ := insights["avg_amount_per_day"].(float64)
-			totalAmount, _ := insights["total_amount"].(float64)
+			avgPerDay, _ := insights["avg_amount_per_day"].(money.Decimal)
+			totalAmount, _ := insights["total_amount"].(money.Decimal)
 			weight, _ := modelData["weight"].(float64)
 			rSquared, _ := modelData["r_squared"].(float64)
 
@@ -922,7 +855,7 @@ func getCashFlowInsights(liminalExecutor core.ToolExecutor) core.Tool {
 			}
 
 			// Average spending insight
-			insightsList = append(insightsList, fmt.Sprintf("You average $%.2f in daily transactions.", math.Abs(avgPerDay)))
+			insightsList = append(insightsList, fmt.Sprintf("You average $%s in daily transactions.", avgPerDay.Abs().StringFixed(2)))
 
 			// Recommendation based on trend
 			if trend == "decreasing" && weight < -10 {
@@ -938,6 +871,9 @@ func getCashFlowInsights(liminalExecutor core.ToolExecutor) core.Tool {
 				"predictability": rSquared,
 				"total_analyzed": totalAmount,
 			}
+			if truncated {
+				result["truncated"] = true
+			}
 
 			return &core.ToolResult{Success: true, Data: result}, nil
 		}).
@@ -963,7 +899,7 @@ type OLSModel struct {
 type DayData struct {
 	DayNumber int
 	Date      time.Time
-	Amount    float64
+	Amount    money.Decimal
 	Count     int
 }
 
@@ -1180,17 +1116,8 @@ func analyseCashFlow(transactions []map[string]interface{}, days int) map[string
 		}
 
 		// Extract amount
-		var amount float64
-		switch v := txn["amount"].(type) {
-		case float64:
-			amount = v
-		case float32:
-			amount = float64(v)
-		case int:
-			amount = float64(v)
-		case int64:
-			amount = float64(v)
-		default:
+		amount, err := txnAmount(txn["amount"])
+		if err != nil {
 			log.Printf("Warning: transaction %d has invalid amount format", i)
 			continue
 		}
@@ -1204,12 +1131,11 @@ func analyseCashFlow(transactions []map[string]interface{}, days int) map[string
 		dateKey := txnDate.Format("2006-01-02")
 		if _, exists := dayMap[dateKey]; !exists {
 			dayMap[dateKey] = &DayData{
-				Date:   txnDate,
-				Amount: 0,
-				Count:  0,
+				Date:  txnDate,
+				Count: 0,
 			}
 		}
-		dayMap[dateKey].Amount += amount
+		dayMap[dateKey].Amount = dayMap[dateKey].Amount.Add(amount)
 		dayMap[dateKey].Count++
 	}
 
@@ -1243,7 +1169,7 @@ func analyseCashFlow(transactions []map[string]interface{}, days int) map[string
 
 	for i, data := range dayDataSlice {
 		X[i] = float64(data.DayNumber)
-		y[i] = data.Amount
+		y[i] = data.Amount.Float64()
 	}
 
 	// ========================================================================
@@ -1296,24 +1222,24 @@ func analyseCashFlow(transactions []map[string]interface{}, days int) map[string
 	// ========================================================================
 	// STEP 6: Calculate historical statistics
 	// ========================================================================
-	var totalAmount, totalCount float64
-	var minAmount, maxAmount float64
-	minAmount = y[0]
-	maxAmount = y[0]
+	var totalAmount money.Decimal
+	var totalCount float64
+	minAmount := dayDataSlice[0].Amount
+	maxAmount := dayDataSlice[0].Amount
 
-	for i, data := range dayDataSlice {
-		totalAmount += data.Amount
+	for _, data := range dayDataSlice {
+		totalAmount = totalAmount.Add(data.Amount)
 		totalCount += float64(data.Count)
 
-		if y[i] < minAmount {
-			minAmount = y[i]
+		if data.Amount.Cmp(minAmount) < 0 {
+			minAmount = data.Amount
 		}
-		if y[i] > maxAmount {
-			maxAmount = y[i]
+		if data.Amount.Cmp(maxAmount) > 0 {
+			maxAmount = data.Amount
 		}
 	}
 
-	avgAmount := totalAmount / float64(len(dayDataSlice))
+	avgAmount, _ := totalAmount.Quo(money.NewDecimal(int64(len(dayDataSlice)), 0), 2, money.RoundHalfEven)
 	avgTransactionsPerDay := totalCount / float64(len(dayDataSlice))
 
 	// ========================================================================
@@ -1322,12 +1248,12 @@ func analyseCashFlow(transactions []map[string]interface{}, days int) map[string
 	historicalData := make([]map[string]interface{}, len(dayDataSlice))
 	for i, data := range dayDataSlice {
 		predicted := model.Predict(float64(data.DayNumber))
-		residual := data.Amount - predicted
+		residual := data.Amount.Float64() - predicted
 
 		historicalData[i] = map[string]interface{}{
 			"day":               data.DayNumber,
 			"date":              data.Date.Format("2006-01-02"),
-			"actual_amount":     math.Round(data.Amount*100) / 100,
+			"actual_amount":     data.Amount.Round(2, money.RoundHalfEven),
 			"predicted_amount":  math.Round(predicted*100) / 100,
 			"residual":          math.Round(residual*100) / 100,
 			"transaction_count": data.Count,
@@ -1349,12 +1275,12 @@ func analyseCashFlow(transactions []map[string]interface{}, days int) map[string
 		"insights": map[string]interface{}{
 			"trend":                    trend,
 			"total_days":               len(dayDataSlice),
-			"total_amount":             math.Round(totalAmount*100) / 100,
+			"total_amount":             totalAmount.Round(2, money.RoundHalfEven),
 			"total_transactions":       int(totalCount),
-			"avg_amount_per_day":       math.Round(avgAmount*100) / 100,
+			"avg_amount_per_day":       avgAmount,
 			"avg_transactions_per_day": math.Round(avgTransactionsPerDay*100) / 100,
-			"min_daily_amount":         math.Round(minAmount*100) / 100,
-			"max_daily_amount":         math.Round(maxAmount*100) / 100,
+			"min_daily_amount":         minAmount.Round(2, money.RoundHalfEven),
+			"max_daily_amount":         maxAmount.Round(2, money.RoundHalfEven),
 			"date_range": map[string]string{
 				"start": dayDataSlice[0].Date.Format("2006-01-02"),
 				"end":   dayDataSlice[len(dayDataSlice)-1].Date.Format("2006-01-02"),
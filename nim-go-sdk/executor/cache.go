@@ -0,0 +1,317 @@
+package executor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+const (
+	// defaultCacheTTL applies to tools without an entry in CacheConfig.TTLs.
+	defaultCacheTTL = 30 * time.Second
+
+	// defaultCacheMaxEntries bounds the cache when CacheConfig.MaxEntries is zero.
+	defaultCacheMaxEntries = 1000
+
+	// defaultCacheFetchTimeout bounds a shared upstream read.
+	defaultCacheFetchTimeout = 30 * time.Second
+)
+
+// CacheConfig configures a CachingExecutor.
+type CacheConfig struct {
+	// TTLs sets how long each read-only tool's results are cached, e.g.
+	// {"get_balance": 10 * time.Second, "get_vault_rates": 5 * time.Minute}.
+	// A negative TTL disables caching for that tool.
+	TTLs map[string]time.Duration
+
+	// DefaultTTL applies to tools not in TTLs. Defaults to 30 seconds;
+	// negative disables caching for unlisted tools.
+	DefaultTTL time.Duration
+
+	// MaxEntries bounds the number of cached results. Defaults to 1000.
+	MaxEntries int
+
+	// FetchTimeout bounds an upstream read. The read is shared by identical
+	// requests, so it doesn't stop when the caller that started it gives up.
+	// Defaults to 30 seconds.
+	FetchTimeout time.Duration
+
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+// CacheStats reports cache effectiveness.
+type CacheStats struct {
+	Hits          int64 // served from cache
+	Misses        int64 // fetched from the wrapped executor
+	Shared        int64 // merged into an identical in-flight request
+	Invalidations int64 // per-user invalidations after writes
+	Entries       int   // results currently cached
+}
+
+// CachingExecutor is a core.ToolExecutor that caches successful read-only
+// results per user. Concurrent identical reads share one upstream call, and
+// any write that executes for a user clears that user's cache.
+type CachingExecutor struct {
+	inner        core.ToolExecutor
+	ttls         map[string]time.Duration
+	defaultTTL   time.Duration
+	maxEntries   int
+	fetchTimeout time.Duration
+	now          func() time.Time
+
+	mu          sync.Mutex
+	entries     map[string]*cacheEntry
+	byUser      map[string]map[string]struct{} // userID -> keys
+	generations map[string]uint64              // userID -> invalidation count
+	inflight    map[string]*cacheCall
+
+	hits, misses, shared, invalidations atomic.Int64
+}
+
+type cacheEntry struct {
+	resp      *core.ExecuteResponse
+	userID    string
+	expiresAt time.Time
+}
+
+// cacheCall is an in-flight upstream read shared by identical requests.
+type cacheCall struct {
+	done chan struct{}
+	resp *core.ExecuteResponse
+	err  error
+}
+
+// Verify CachingExecutor implements core.ToolExecutor.
+var _ core.ToolExecutor = (*CachingExecutor)(nil)
+
+// NewCachingExecutor wraps inner with a read cache.
+func NewCachingExecutor(inner core.ToolExecutor, cfg CacheConfig) *CachingExecutor {
+	defaultTTL := cfg.DefaultTTL
+	if defaultTTL == 0 {
+		defaultTTL = defaultCacheTTL
+	}
+	maxEntries := cfg.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultCacheMaxEntries
+	}
+	fetchTimeout := cfg.FetchTimeout
+	if fetchTimeout <= 0 {
+		fetchTimeout = defaultCacheFetchTimeout
+	}
+	now := cfg.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	return &CachingExecutor{
+		inner:        inner,
+		ttls:         cfg.TTLs,
+		defaultTTL:   defaultTTL,
+		maxEntries:   maxEntries,
+		fetchTimeout: fetchTimeout,
+		now:          now,
+		entries:      make(map[string]*cacheEntry),
+		byUser:       make(map[string]map[string]struct{}),
+		generations:  make(map[string]uint64),
+		inflight:     make(map[string]*cacheCall),
+	}
+}
+
+// Execute serves a read-only tool from cache, or fetches and caches it.
+func (c *CachingExecutor) Execute(ctx context.Context, req *core.ExecuteRequest) (*core.ExecuteResponse, error) {
+	ttl := c.ttlFor(req.Tool)
+	if ttl < 0 {
+		return c.inner.Execute(ctx, req)
+	}
+	key := cacheKey(req.UserID, req.Tool, req.Input)
+
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok {
+		if c.now().Before(entry.expiresAt) {
+			c.mu.Unlock()
+			c.hits.Add(1)
+			return copyResponse(entry.resp), nil
+		}
+		c.removeLocked(key)
+	}
+	call, ok := c.inflight[key]
+	if ok {
+		c.shared.Add(1)
+	} else {
+		call = &cacheCall{done: make(chan struct{})}
+		c.inflight[key] = call
+		c.misses.Add(1)
+		go c.fetch(ctx, key, req, ttl, c.generations[req.UserID], call)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return copyResponse(call.resp), call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// fetch runs a shared upstream read and caches its result. It ignores the
+// starting caller's cancellation, so other callers waiting on the read
+// aren't failed by it, and is bounded by the fetch timeout instead.
+func (c *CachingExecutor) fetch(ctx context.Context, key string, req *core.ExecuteRequest, ttl time.Duration, generation uint64, call *cacheCall) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.fetchTimeout)
+	defer cancel()
+	call.resp, call.err = c.inner.Execute(ctx, req)
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	// Skip caching if a write invalidated the user while we were fetching
+	if call.err == nil && call.resp != nil && call.resp.Success && c.generations[req.UserID] == generation {
+		c.storeLocked(key, req.UserID, call.resp, ttl)
+	}
+	c.mu.Unlock()
+	close(call.done)
+}
+
+// ExecuteWrite passes through, invalidating the user's cache if the write
+// executed without needing confirmation.
+func (c *CachingExecutor) ExecuteWrite(ctx context.Context, req *core.ExecuteRequest) (*core.ExecuteResponse, error) {
+	resp, err := c.inner.ExecuteWrite(ctx, req)
+	if err == nil && resp != nil && resp.Success && !resp.RequiresConfirmation {
+		c.Invalidate(req.UserID)
+	}
+	return resp, err
+}
+
+// Confirm passes through and invalidates the user's cache once the write runs.
+func (c *CachingExecutor) Confirm(ctx context.Context, userID, confirmationID string) (*core.ExecuteResponse, error) {
+	resp, err := c.inner.Confirm(ctx, userID, confirmationID)
+	if err == nil && resp != nil && resp.Success {
+		c.Invalidate(userID)
+	}
+	return resp, err
+}
+
+// Cancel passes through.
+func (c *CachingExecutor) Cancel(ctx context.Context, userID, confirmationID string) error {
+	return c.inner.Cancel(ctx, userID, confirmationID)
+}
+
+// CheckHealth delegates to the wrapped executor if it implements core.HealthChecker.
+func (c *CachingExecutor) CheckHealth(ctx context.Context) error {
+	if checker, ok := c.inner.(core.HealthChecker); ok {
+		return checker.CheckHealth(ctx)
+	}
+	return nil
+}
+
+// Invalidate drops all cached results for a user.
+func (c *CachingExecutor) Invalidate(userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.byUser[userID] {
+		delete(c.entries, key)
+	}
+	delete(c.byUser, userID)
+	c.generations[userID]++
+	c.invalidations.Add(1)
+}
+
+// Stats returns hit/miss counters and the current cache size.
+func (c *CachingExecutor) Stats() CacheStats {
+	c.mu.Lock()
+	entries := len(c.entries)
+	c.mu.Unlock()
+
+	return CacheStats{
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Shared:        c.shared.Load(),
+		Invalidations: c.invalidations.Load(),
+		Entries:       entries,
+	}
+}
+
+func (c *CachingExecutor) ttlFor(tool string) time.Duration {
+	if ttl, ok := c.ttls[tool]; ok {
+		return ttl
+	}
+	return c.defaultTTL
+}
+
+// storeLocked caches resp, evicting expired or arbitrary entries when full.
+// The caller must hold c.mu.
+func (c *CachingExecutor) storeLocked(key, userID string, resp *core.ExecuteResponse, ttl time.Duration) {
+	if len(c.entries) >= c.maxEntries {
+		now := c.now()
+		for k, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				c.removeLocked(k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < c.maxEntries {
+				break
+			}
+			c.removeLocked(k)
+		}
+	}
+
+	c.entries[key] = &cacheEntry{
+		resp:      copyResponse(resp),
+		userID:    userID,
+		expiresAt: c.now().Add(ttl),
+	}
+	if c.byUser[userID] == nil {
+		c.byUser[userID] = make(map[string]struct{})
+	}
+	c.byUser[userID][key] = struct{}{}
+}
+
+// removeLocked deletes a cache entry. The caller must hold c.mu.
+func (c *CachingExecutor) removeLocked(key string) {
+	entry, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	delete(c.entries, key)
+	if keys := c.byUser[entry.userID]; keys != nil {
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(c.byUser, entry.userID)
+		}
+	}
+}
+
+// cacheKey hashes the user, tool and canonicalised input, in the same way as
+// engine.GenerateIdempotencyKey but without a time bucket.
+func cacheKey(userID, tool string, input json.RawMessage) string {
+	var parsed interface{}
+	if err := json.Unmarshal(input, &parsed); err != nil {
+		parsed = string(input)
+	}
+	canonical, _ := json.Marshal(parsed)
+
+	data := fmt.Sprintf("%s:%s:%s", userID, tool, string(canonical))
+	hash := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(hash[:])
+}
+
+// copyResponse returns a copy so callers can't mutate cached data.
+func copyResponse(resp *core.ExecuteResponse) *core.ExecuteResponse {
+	if resp == nil {
+		return nil
+	}
+	copied := *resp
+	if resp.Data != nil {
+		copied.Data = append(json.RawMessage(nil), resp.Data...)
+	}
+	return &copied
+}
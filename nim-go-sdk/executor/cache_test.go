@@ -0,0 +1,173 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+// countingExecutor counts reads and blocks them until release is closed.
+type countingExecutor struct {
+	reads   atomic.Int64
+	release chan struct{}
+}
+
+func (e *countingExecutor) Execute(ctx context.Context, req *core.ExecuteRequest) (*core.ExecuteResponse, error) {
+	n := e.reads.Add(1)
+	if e.release != nil {
+		select {
+		case <-e.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	data, _ := json.Marshal(map[string]int64{"read": n})
+	return &core.ExecuteResponse{Success: true, Data: data}, nil
+}
+
+func (e *countingExecutor) ExecuteWrite(ctx context.Context, req *core.ExecuteRequest) (*core.ExecuteResponse, error) {
+	return &core.ExecuteResponse{Success: true, RequiresConfirmation: true}, nil
+}
+
+func (e *countingExecutor) Confirm(ctx context.Context, userID, confirmationID string) (*core.ExecuteResponse, error) {
+	return &core.ExecuteResponse{Success: true}, nil
+}
+
+func (e *countingExecutor) Cancel(ctx context.Context, userID, confirmationID string) error {
+	return nil
+}
+
+func TestCachingExecutor(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	inner := &countingExecutor{}
+	cache := NewCachingExecutor(inner, CacheConfig{
+		TTLs: map[string]time.Duration{"get_profile": -1},
+		Now:  func() time.Time { return now },
+	})
+	ctx := context.Background()
+	read := func(userID, tool, input string) {
+		t.Helper()
+		if _, err := cache.Execute(ctx, &core.ExecuteRequest{UserID: userID, Tool: tool, Input: json.RawMessage(input)}); err != nil {
+			t.Fatalf("%s failed: %v", tool, err)
+		}
+	}
+
+	// Key order and whitespace don't matter
+	read("user_1", "get_transactions", `{"limit":10,"type":"send"}`)
+	read("user_1", "get_transactions", `{ "type": "send", "limit": 10 }`)
+	read("user_2", "get_transactions", `{"limit":10,"type":"send"}`)
+	read("user_1", "get_profile", `{}`)
+	read("user_1", "get_profile", `{}`)
+	if got := inner.reads.Load(); got != 4 {
+		t.Fatalf("expected 4 upstream reads, got %d", got)
+	}
+
+	// A write awaiting confirmation leaves the cache alone; confirming clears it
+	cache.ExecuteWrite(ctx, &core.ExecuteRequest{UserID: "user_1", Tool: "send_money"})
+	read("user_1", "get_transactions", `{"limit":10,"type":"send"}`)
+	cache.Confirm(ctx, "user_1", "conf_1")
+	read("user_1", "get_transactions", `{"limit":10,"type":"send"}`)
+	read("user_2", "get_transactions", `{"limit":10,"type":"send"}`)
+	if got := inner.reads.Load(); got != 5 {
+		t.Fatalf("expected confirm to invalidate only user_1, got %d upstream reads", got)
+	}
+
+	now = now.Add(defaultCacheTTL)
+	read("user_2", "get_transactions", `{"limit":10,"type":"send"}`)
+	if got := inner.reads.Load(); got != 6 {
+		t.Fatalf("expected expired entry to be refetched, got %d upstream reads", got)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 3 || stats.Misses != 4 || stats.Invalidations != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestCachingExecutorSingleFlight(t *testing.T) {
+	inner := &countingExecutor{release: make(chan struct{})}
+	cache := NewCachingExecutor(inner, CacheConfig{})
+
+	const callers = 5
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := cache.Execute(context.Background(), &core.ExecuteRequest{UserID: "user_1", Tool: "get_balance"})
+			if err != nil || !resp.Success {
+				t.Errorf("get_balance failed: %v %+v", err, resp)
+			}
+		}()
+	}
+
+	// Wait until every caller is either fetching or sharing
+	for cache.Stats().Misses+cache.Stats().Shared < callers {
+		time.Sleep(time.Millisecond)
+	}
+	close(inner.release)
+	wg.Wait()
+
+	if got := inner.reads.Load(); got != 1 {
+		t.Errorf("expected 1 upstream read, got %d", got)
+	}
+	if stats := cache.Stats(); stats.Shared != callers-1 {
+		t.Errorf("expected %d shared calls, got %+v", callers-1, stats)
+	}
+}
+
+func TestCachingExecutorCallerCancel(t *testing.T) {
+	inner := &countingExecutor{release: make(chan struct{})}
+	cache := NewCachingExecutor(inner, CacheConfig{})
+	req := &core.ExecuteRequest{UserID: "user_1", Tool: "get_balance"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := cache.Execute(ctx, req)
+		first <- err
+	}()
+	for cache.Stats().Misses == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	second := make(chan *core.ExecuteResponse, 1)
+	go func() {
+		resp, err := cache.Execute(context.Background(), req)
+		if err != nil {
+			t.Errorf("expected the waiting caller to succeed, got %v", err)
+		}
+		second <- resp
+	}()
+	for cache.Stats().Shared == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The caller that started the read gives up; the read carries on
+	cancel()
+	if err := <-first; err != context.Canceled {
+		t.Errorf("expected the cancelled caller to return context.Canceled, got %v", err)
+	}
+	close(inner.release)
+	if resp := <-second; resp == nil || !resp.Success {
+		t.Errorf("expected the shared result, got %+v", resp)
+	}
+	if _, err := cache.Execute(context.Background(), req); err != nil || inner.reads.Load() != 1 {
+		t.Errorf("expected the result to be cached, got %v after %d reads", err, inner.reads.Load())
+	}
+}
+
+func TestCachingExecutorFetchTimeout(t *testing.T) {
+	inner := &countingExecutor{release: make(chan struct{})}
+	defer close(inner.release)
+	cache := NewCachingExecutor(inner, CacheConfig{FetchTimeout: 10 * time.Millisecond})
+
+	_, err := cache.Execute(context.Background(), &core.ExecuteRequest{UserID: "user_1", Tool: "get_balance"})
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected the read to time out, got %v", err)
+	}
+}
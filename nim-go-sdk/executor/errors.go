@@ -0,0 +1,115 @@
+package executor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error kinds returned by HTTPExecutor. Match them with errors.Is:
+//
+//	if errors.Is(err, executor.ErrRateLimited) { ... }
+//
+// Use errors.As with *APIError for the status code and gateway message.
+var (
+	// ErrAuth means the gateway rejected the credentials (401 or 403).
+	ErrAuth = errors.New("authentication failed")
+
+	// ErrValidation means the gateway rejected the request as invalid (other 4xx).
+	ErrValidation = errors.New("invalid request")
+
+	// ErrRateLimited means the gateway is throttling requests (429).
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrUpstream means the gateway or a service behind it failed (5xx).
+	ErrUpstream = errors.New("upstream error")
+)
+
+// APIError is an error response from the agent_gateway.
+type APIError struct {
+	// Kind is one of ErrAuth, ErrValidation, ErrRateLimited or ErrUpstream.
+	Kind error
+
+	// StatusCode is the HTTP status code.
+	StatusCode int
+
+	// Code is the gRPC status code from a grpc-gateway error body, if present.
+	Code int
+
+	// Message is the gateway's error message, or the raw body if it
+	// wasn't a grpc-gateway error.
+	Message string
+
+	// RetryAfter is the delay requested by a Retry-After header, if any.
+	RetryAfter time.Duration
+}
+
+// Error keeps the "HTTP <status>: <message>" form tools have always reported.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// Is reports whether target is the error's Kind.
+func (e *APIError) Is(target error) bool {
+	return e.Kind == target
+}
+
+// Unwrap returns the error's Kind.
+func (e *APIError) Unwrap() error {
+	return e.Kind
+}
+
+// newAPIError builds an APIError from a failed gateway response.
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    strings.TrimSpace(string(body)),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+
+	var status struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &status); err == nil && status.Message != "" {
+		apiErr.Code = status.Code
+		apiErr.Message = status.Message
+	}
+
+	apiErr.Kind = errorKind(resp.StatusCode)
+	return apiErr
+}
+
+// errorKind classifies an HTTP error status.
+func errorKind(status int) error {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrAuth
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status >= 500:
+		return ErrUpstream
+	default:
+		return ErrValidation
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
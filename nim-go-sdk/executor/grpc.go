@@ -54,6 +54,13 @@ type LedgerService interface {
 	GetTransactions(ctx context.Context, userID string, limit int, txType *string) (json.RawMessage, error)
 }
 
+// TransactionQuerier is an optional interface for LedgerServices that support
+// cursors and the full get_transactions filter set. Without it, GRPCExecutor
+// filters a single page from GetTransactions and cannot follow cursors.
+type TransactionQuerier interface {
+	QueryTransactions(ctx context.Context, userID string, query TransactionQuery) (json.RawMessage, error)
+}
+
 // GRPCExecutorConfig configures the gRPC executor.
 type GRPCExecutorConfig struct {
 	Wallets       WalletService
@@ -220,6 +227,33 @@ func (e *GRPCExecutor) Cancel(ctx context.Context, userID, confirmationID string
 	return e.confirmations.Cancel(ctx, userID, confirmationID)
 }
 
+// CheckHealth probes each configured service and the confirmation store
+// that implements core.HealthChecker. Services that don't are assumed healthy.
+func (e *GRPCExecutor) CheckHealth(ctx context.Context) error {
+	deps := []struct {
+		name string
+		dep  interface{}
+	}{
+		{"wallets", e.wallets},
+		{"payments", e.payments},
+		{"savings", e.savings},
+		{"users", e.users},
+		{"ledger", e.ledger},
+		{"confirmations", e.confirmations},
+	}
+
+	for _, d := range deps {
+		checker, ok := d.dep.(core.HealthChecker)
+		if !ok {
+			continue
+		}
+		if err := checker.CheckHealth(ctx); err != nil {
+			return fmt.Errorf("%s: %w", d.name, err)
+		}
+	}
+	return nil
+}
+
 // Read operation implementations
 
 func (e *GRPCExecutor) executeGetBalance(ctx context.Context, req *core.ExecuteRequest) (json.RawMessage, error) {
@@ -261,18 +295,43 @@ func (e *GRPCExecutor) executeGetTransactions(ctx context.Context, req *core.Exe
 		return nil, fmt.Errorf("ledger service not configured")
 	}
 
-	var input struct {
-		Limit int     `json:"limit"`
-		Type  *string `json:"type"`
+	var query TransactionQuery
+	if len(req.Input) > 0 {
+		if err := json.Unmarshal(req.Input, &query); err != nil {
+			return nil, fmt.Errorf("invalid input: %w", err)
+		}
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	if query.Limit == 0 {
+		query.Limit = 10
 	}
-	json.Unmarshal(req.Input, &input)
 
-	limit := input.Limit
-	if limit == 0 {
-		limit = 10
+	if querier, ok := e.ledger.(TransactionQuerier); ok {
+		return querier.QueryTransactions(ctx, req.UserID, query)
+	}
+	if query.Cursor != "" {
+		return nil, fmt.Errorf("ledger service does not support cursors")
 	}
 
-	return e.ledger.GetTransactions(ctx, req.UserID, limit, input.Type)
+	var txType *string
+	if query.Type != "" {
+		txType = &query.Type
+	}
+	data, err := e.ledger.GetTransactions(ctx, req.UserID, query.Limit, txType)
+	if err != nil {
+		return nil, err
+	}
+	var page GetTransactionsResponse
+	if err := json.Unmarshal(data, &page); err != nil {
+		return nil, fmt.Errorf("failed to parse transactions: %w", err)
+	}
+	filtered, err := FilterTransactions(&page, query)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(filtered)
 }
 
 func (e *GRPCExecutor) executeGetProfile(ctx context.Context, req *core.ExecuteRequest) (json.RawMessage, error) {
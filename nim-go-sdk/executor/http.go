@@ -5,10 +5,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
-	"strings"
+	"net/url"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/becomeliminal/nim-go-sdk/core"
@@ -17,12 +21,32 @@ import (
 // HTTPExecutor implements ToolExecutor by calling the agent_gateway over HTTP.
 // This is the public implementation used by external developers.
 type HTTPExecutor struct {
-	baseURL    string
-	apiKey     string  // Deprecated: use jwtToken
-	jwtToken   string  // JWT for Bearer authentication
-	httpClient *http.Client
+	baseURL        string
+	apiKey         string // Deprecated: use jwtToken
+	httpClient     *http.Client
+	maxRetries     int
+	retryBackoff   time.Duration
+	tokenRefresher TokenRefresher
+
+	mu       sync.RWMutex
+	jwtToken string // JWT for Bearer authentication
 }
 
+// TokenRefresher returns a fresh JWT. HTTPExecutor calls it at most once per
+// request, when the gateway answers 401, and retries the request with the new token.
+type TokenRefresher func(ctx context.Context) (string, error)
+
+const (
+	// defaultMaxRetries is the number of retries when MaxRetries is zero.
+	defaultMaxRetries = 2
+
+	// defaultRetryBackoff is the initial retry delay when RetryBackoff is zero.
+	defaultRetryBackoff = 250 * time.Millisecond
+
+	// maxRetryDelay caps a single retry delay, including Retry-After.
+	maxRetryDelay = 10 * time.Second
+)
+
 // HTTPExecutorConfig configures the HTTP executor.
 type HTTPExecutorConfig struct {
 	// BaseURL is the agent_gateway URL (e.g., "https://api.liminal.cash").
@@ -37,6 +61,23 @@ type HTTPExecutorConfig struct {
 
 	// Timeout is the HTTP request timeout.
 	Timeout time.Duration
+
+	// MaxRetries is how many times a failed request is retried after network
+	// errors, 429s and 5xx responses. Reads are always retryable; writes only
+	// when the request carries an IdempotencyKey. Defaults to 2; negative disables.
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry, doubling (with jitter)
+	// for each subsequent retry. A longer Retry-After from the gateway wins.
+	// Defaults to 250ms.
+	RetryBackoff time.Duration
+
+	// TokenRefresher, if set, is called when the gateway answers 401 so the
+	// request can be retried once with a fresh JWT.
+	TokenRefresher TokenRefresher
+
+	// HTTPClient overrides the HTTP client. If set, Timeout is ignored.
+	HTTPClient *http.Client
 }
 
 // NewHTTPExecutor creates a new HTTP-based tool executor.
@@ -45,42 +86,121 @@ func NewHTTPExecutor(cfg HTTPExecutorConfig) *HTTPExecutor {
 	if timeout == 0 {
 		timeout = 30 * time.Second
 	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{
+			Timeout: timeout,
+		}
+	}
+
+	maxRetries := cfg.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultMaxRetries
+	}
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	backoff := cfg.RetryBackoff
+	if backoff == 0 {
+		backoff = defaultRetryBackoff
+	}
 
 	return &HTTPExecutor{
-		baseURL:  cfg.BaseURL,
-		apiKey:   cfg.APIKey,   // Keep for backward compatibility
-		jwtToken: cfg.JWTToken, // New JWT field
-		httpClient: &http.Client{
-			Timeout: timeout,
-		},
+		baseURL:        cfg.BaseURL,
+		apiKey:         cfg.APIKey,   // Keep for backward compatibility
+		jwtToken:       cfg.JWTToken, // New JWT field
+		httpClient:     client,
+		maxRetries:     maxRetries,
+		retryBackoff:   backoff,
+		tokenRefresher: cfg.TokenRefresher,
 	}
 }
 
 // Execute runs a read-only tool via HTTP.
 func (e *HTTPExecutor) Execute(ctx context.Context, req *core.ExecuteRequest) (*core.ExecuteResponse, error) {
+	if req.Tool == "get_transactions" {
+		return e.executeGetTransactions(ctx, req)
+	}
 	endpoint := e.endpointForTool(req.Tool)
-	return e.doRequest(ctx, "GET", endpoint, req, req.Tool)
+	return e.doRequest(ctx, "GET", endpoint, req, req.Tool, "")
+}
+
+// executeGetTransactions fetches a page of transactions and applies the
+// query's filters to it, as GRPCExecutor does, so results don't depend on
+// which filters the gateway honours.
+func (e *HTTPExecutor) executeGetTransactions(ctx context.Context, req *core.ExecuteRequest) (*core.ExecuteResponse, error) {
+	var query TransactionQuery
+	if len(req.Input) > 0 {
+		if err := json.Unmarshal(req.Input, &query); err != nil {
+			return &core.ExecuteResponse{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+		}
+	}
+	if err := query.Validate(); err != nil {
+		return &core.ExecuteResponse{Success: false, Error: err.Error()}, nil
+	}
+
+	resp, err := e.doRequest(ctx, "GET", e.endpointForTool(req.Tool), req, req.Tool, "")
+	if err != nil {
+		return nil, err
+	}
+	var page GetTransactionsResponse
+	if err := json.Unmarshal(resp.Data, &page); err != nil {
+		return nil, fmt.Errorf("failed to parse transactions: %w", err)
+	}
+	filtered, err := FilterTransactions(&page, query)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(filtered)
+	if err != nil {
+		return nil, err
+	}
+	return &core.ExecuteResponse{Success: true, Data: data}, nil
 }
 
 // ExecuteWrite runs a write tool that may require confirmation.
+// Failed writes are only retried when req.IdempotencyKey is set.
 func (e *HTTPExecutor) ExecuteWrite(ctx context.Context, req *core.ExecuteRequest) (*core.ExecuteResponse, error) {
 	endpoint := e.endpointForTool(req.Tool)
-	return e.doRequest(ctx, "POST", endpoint, req, req.Tool)
+	return e.doRequest(ctx, "POST", endpoint, req, req.Tool, req.IdempotencyKey)
 }
 
 // Confirm executes a previously confirmed write operation.
+// It is never retried: the gateway doesn't document deduplicating confirms.
 func (e *HTTPExecutor) Confirm(ctx context.Context, userID, confirmationID string) (*core.ExecuteResponse, error) {
-	endpoint := fmt.Sprintf("/nim/v1/agent/confirmations/%s/confirm", confirmationID)
-	return e.doRequest(ctx, "POST", endpoint, nil, "")
+	endpoint := fmt.Sprintf("/nim/v1/agent/confirmations/%s/confirm", url.PathEscape(confirmationID))
+	return e.doRequest(ctx, "POST", endpoint, nil, "", "")
 }
 
-// Cancel cancels a pending confirmation.
+// Cancel cancels a pending confirmation. Like Confirm, it is never retried.
 func (e *HTTPExecutor) Cancel(ctx context.Context, userID, confirmationID string) error {
-	endpoint := fmt.Sprintf("/nim/v1/agent/confirmations/%s/cancel", confirmationID)
-	_, err := e.doRequest(ctx, "POST", endpoint, nil, "")
+	endpoint := fmt.Sprintf("/nim/v1/agent/confirmations/%s/cancel", url.PathEscape(confirmationID))
+	_, err := e.doRequest(ctx, "POST", endpoint, nil, "", "")
 	return err
 }
 
+// CheckHealth reports whether the agent_gateway is reachable and healthy.
+// Only a 2xx response from /health counts as healthy; the endpoint doesn't
+// require authentication.
+func (e *HTTPExecutor) CheckHealth(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", e.baseURL+"/health", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("agent gateway unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("agent gateway unhealthy: HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // endpointForTool maps tool names to HTTP endpoints.
 func (e *HTTPExecutor) endpointForTool(tool string) string {
 	// Map tool names to nim_gateway endpoints
@@ -103,11 +223,12 @@ func (e *HTTPExecutor) endpointForTool(tool string) string {
 	return fmt.Sprintf("/nim/v1/agent/tools/%s", tool)
 }
 
-// doRequest performs an HTTP request to the agent_gateway.
-func (e *HTTPExecutor) doRequest(ctx context.Context, method, endpoint string, body interface{}, toolName string) (*core.ExecuteResponse, error) {
+// doRequest performs an HTTP request to the agent_gateway, retrying
+// transient failures and refreshing the JWT once on 401.
+func (e *HTTPExecutor) doRequest(ctx context.Context, method, endpoint string, body interface{}, toolName, idempotencyKey string) (*core.ExecuteResponse, error) {
 	urlStr := e.baseURL + endpoint
 
-	var bodyReader io.Reader
+	var bodyBytes []byte
 
 	// For GET requests, encode parameters as query string instead of body
 	if method == "GET" && body != nil {
@@ -115,21 +236,74 @@ func (e *HTTPExecutor) doRequest(ctx context.Context, method, endpoint string, b
 			// Parse Input JSON and add as query parameters
 			var params map[string]interface{}
 			if err := json.Unmarshal(execReq.Input, &params); err == nil {
-				query := make([]string, 0, len(params))
-				for k, v := range params {
-					query = append(query, fmt.Sprintf("%s=%v", k, v))
-				}
+				query := url.Values{}
+				encodeQuery(query, "", params)
 				if len(query) > 0 {
-					urlStr += "?" + strings.Join(query, "&")
+					urlStr += "?" + query.Encode()
 				}
 			}
 		}
-		bodyReader = nil
 	} else if body != nil {
-		bodyBytes, err := json.Marshal(body)
+		var err error
+		bodyBytes, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request: %w", err)
 		}
+	}
+
+	// Reads are idempotent; writes only when the caller supplied a key
+	retryable := method == "GET" || idempotencyKey != ""
+	refreshed := false
+
+	for attempt := 0; ; attempt++ {
+		respBody, err := e.send(ctx, method, urlStr, bodyBytes, idempotencyKey)
+		if err == nil {
+			return parseToolResponse(toolName, respBody)
+		}
+
+		var apiErr *APIError
+		isAPIErr := errors.As(err, &apiErr)
+
+		// A 401 means the request was not processed, so it's safe to replay
+		// with a fresh token regardless of idempotency.
+		if isAPIErr && apiErr.StatusCode == http.StatusUnauthorized && e.tokenRefresher != nil && !refreshed {
+			refreshed = true
+			token, refreshErr := e.tokenRefresher(ctx)
+			if refreshErr != nil {
+				return nil, fmt.Errorf("token refresh failed: %w", refreshErr)
+			}
+			e.UpdateJWT(token)
+			attempt--
+			continue
+		}
+
+		if !retryable || attempt >= e.maxRetries || !shouldRetry(ctx, err) {
+			return nil, err
+		}
+
+		delay := e.backoff(attempt)
+		if isAPIErr && apiErr.RetryAfter > delay {
+			delay = apiErr.RetryAfter
+		}
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// send performs a single HTTP request and returns the response body, or an
+// *APIError for 4xx/5xx responses.
+func (e *HTTPExecutor) send(ctx context.Context, method, urlStr string, bodyBytes []byte, idempotencyKey string) ([]byte, error) {
+	var bodyReader io.Reader
+	if bodyBytes != nil {
 		bodyReader = bytes.NewReader(bodyBytes)
 	}
 
@@ -141,10 +315,16 @@ func (e *HTTPExecutor) doRequest(ctx context.Context, method, endpoint string, b
 	if method != "GET" {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
 
 	// Prefer JWT over API key
-	if e.jwtToken != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", e.jwtToken))
+	e.mu.RLock()
+	jwtToken := e.jwtToken
+	e.mu.RUnlock()
+	if jwtToken != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", jwtToken))
 	} else if e.apiKey != "" {
 		// Fallback to API key for backward compatibility
 		req.Header.Set("X-API-Key", e.apiKey)
@@ -162,12 +342,13 @@ func (e *HTTPExecutor) doRequest(ctx context.Context, method, endpoint string, b
 	}
 
 	if resp.StatusCode >= 400 {
-		return &core.ExecuteResponse{
-			Success: false,
-			Error:   fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(respBody)),
-		}, nil
+		return nil, newAPIError(resp, respBody)
 	}
+	return respBody, nil
+}
 
+// parseToolResponse validates a gateway response against the tool's type.
+func parseToolResponse(toolName string, respBody []byte) (*core.ExecuteResponse, error) {
 	// Gateway returns raw proto response (not wrapped in ExecuteResponse)
 	// Unmarshal into the proper type to validate the structure
 	responseType := toolResponseType(toolName)
@@ -187,8 +368,66 @@ func (e *HTTPExecutor) doRequest(ctx context.Context, method, endpoint string, b
 	}, nil
 }
 
+// shouldRetry reports whether err is transient: a network error, 429 or 5xx.
+func shouldRetry(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Kind == ErrRateLimited || apiErr.Kind == ErrUpstream
+	}
+	// Network failures surface as *url.Error from the client, or as a
+	// truncated body; request construction and parse errors aren't retried
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// backoff returns the delay before retry number attempt+1: exponential with
+// jitter in [d/2, d).
+func (e *HTTPExecutor) backoff(attempt int) time.Duration {
+	d := e.retryBackoff << attempt
+	if d <= 0 || d > maxRetryDelay {
+		d = maxRetryDelay
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// encodeQuery flattens JSON parameters into query values. Nested objects use
+// dotted keys ("filter.currency=USD") and arrays repeat the key, matching
+// grpc-gateway's query parameter mapping.
+func encodeQuery(values url.Values, prefix string, v interface{}) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			encodeQuery(values, key, child)
+		}
+	case []interface{}:
+		for _, child := range val {
+			encodeQuery(values, prefix, child)
+		}
+	case nil:
+		// Omit nulls
+	case string:
+		values.Add(prefix, val)
+	case float64:
+		values.Add(prefix, strconv.FormatFloat(val, 'f', -1, 64))
+	case bool:
+		values.Add(prefix, strconv.FormatBool(val))
+	default:
+		values.Add(prefix, fmt.Sprint(val))
+	}
+}
+
 // UpdateJWT updates the JWT token used for authentication.
 // This should be called when the token is refreshed.
 func (e *HTTPExecutor) UpdateJWT(jwt string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	e.jwtToken = jwt
 }
@@ -0,0 +1,205 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+func newTestExecutor(url string, cfg HTTPExecutorConfig) *HTTPExecutor {
+	cfg.BaseURL = url
+	cfg.RetryBackoff = time.Millisecond
+	return NewHTTPExecutor(cfg)
+}
+
+func TestQueryEncoding(t *testing.T) {
+	var query string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"transactions":[]}`))
+	}))
+	defer ts.Close()
+
+	exec := newTestExecutor(ts.URL, HTTPExecutorConfig{})
+	_, err := exec.Execute(context.Background(), &core.ExecuteRequest{
+		Tool:  "get_transactions",
+		Input: json.RawMessage(`{"limit":100,"counterparty":"@bob & co","filter":{"types":["send","receive"],"min":1.5},"note":null}`),
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	want := "counterparty=%40bob+%26+co&filter.min=1.5&filter.types=send&filter.types=receive&limit=100"
+	if query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"code":14,"message":"unavailable"}`))
+			return
+		}
+		if r.Method == "POST" && r.Header.Get("Idempotency-Key") != "key-1" {
+			t.Errorf("expected idempotency key header, got %q", r.Header.Get("Idempotency-Key"))
+		}
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer ts.Close()
+	exec := newTestExecutor(ts.URL, HTTPExecutorConfig{})
+
+	// Reads are retried
+	if _, err := exec.Execute(context.Background(), &core.ExecuteRequest{Tool: "get_balance"}); err != nil {
+		t.Fatalf("expected read to succeed after retries, got %v", err)
+	}
+
+	// Writes without an idempotency key are not
+	calls.Store(0)
+	_, err := exec.ExecuteWrite(context.Background(), &core.ExecuteRequest{Tool: "send_money", Input: json.RawMessage(`{}`)})
+	var apiErr *APIError
+	if !errors.Is(err, ErrUpstream) || !errors.As(err, &apiErr) || apiErr.Message != "unavailable" || calls.Load() != 1 {
+		t.Fatalf("expected a single upstream error, got %v after %d calls", err, calls.Load())
+	}
+
+	// Writes with one are
+	calls.Store(0)
+	_, err = exec.ExecuteWrite(context.Background(), &core.ExecuteRequest{
+		Tool:           "send_money",
+		Input:          json.RawMessage(`{}`),
+		IdempotencyKey: "key-1",
+	})
+	if err != nil || calls.Load() != 3 {
+		t.Fatalf("expected write to succeed on third call, got %v after %d calls", err, calls.Load())
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		kind   error
+	}{
+		{http.StatusUnauthorized, ErrAuth},
+		{http.StatusForbidden, ErrAuth},
+		{http.StatusBadRequest, ErrValidation},
+		{http.StatusNotFound, ErrValidation},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusBadGateway, ErrUpstream},
+	}
+	for _, tt := range tests {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+		exec := newTestExecutor(ts.URL, HTTPExecutorConfig{MaxRetries: -1})
+		_, err := exec.Execute(context.Background(), &core.ExecuteRequest{Tool: "get_balance"})
+		if !errors.Is(err, tt.kind) {
+			t.Errorf("status %d: expected %v, got %v", tt.status, tt.kind, err)
+		}
+		ts.Close()
+	}
+}
+
+func TestTokenRefresh(t *testing.T) {
+	var refreshes atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"balances":[]}`))
+	}))
+	defer ts.Close()
+
+	exec := newTestExecutor(ts.URL, HTTPExecutorConfig{
+		JWTToken: "expired",
+		TokenRefresher: func(ctx context.Context) (string, error) {
+			refreshes.Add(1)
+			return "fresh", nil
+		},
+	})
+	if _, err := exec.Execute(context.Background(), &core.ExecuteRequest{Tool: "get_balance"}); err != nil {
+		t.Fatalf("expected success after refresh, got %v", err)
+	}
+	if refreshes.Load() != 1 {
+		t.Errorf("expected one refresh, got %d", refreshes.Load())
+	}
+
+	// A refreshed token that is still rejected is reported, not refreshed again
+	stale := newTestExecutor(ts.URL, HTTPExecutorConfig{
+		JWTToken: "expired",
+		TokenRefresher: func(ctx context.Context) (string, error) {
+			refreshes.Add(1)
+			return "also-expired", nil
+		},
+	})
+	refreshes.Store(0)
+	_, err := stale.Execute(context.Background(), &core.ExecuteRequest{Tool: "get_balance"})
+	if !errors.Is(err, ErrAuth) || refreshes.Load() != 1 {
+		t.Errorf("expected auth error after one refresh, got %v after %d refreshes", err, refreshes.Load())
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	for status, healthy := range map[int]bool{
+		http.StatusOK:                 true,
+		http.StatusNoContent:          true,
+		http.StatusMovedPermanently:   false,
+		http.StatusUnauthorized:       false,
+		http.StatusNotFound:           false,
+		http.StatusServiceUnavailable: false,
+	} {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/health" {
+				t.Errorf("unexpected path %s", r.URL.Path)
+			}
+			w.WriteHeader(status)
+		}))
+		err := newTestExecutor(ts.URL, HTTPExecutorConfig{}).CheckHealth(context.Background())
+		if (err == nil) != healthy {
+			t.Errorf("status %d: expected healthy=%v, got %v", status, healthy, err)
+		}
+		ts.Close()
+	}
+}
+
+func TestConfirmedWrites(t *testing.T) {
+	var calls atomic.Int32
+	var key atomic.Value
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		key.Store(r.Header.Get("Idempotency-Key"))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+	exec := newTestExecutor(ts.URL, HTTPExecutorConfig{})
+
+	// Confirm and Cancel are not retried
+	if _, err := exec.Confirm(context.Background(), "user_1", "action_1"); !errors.Is(err, ErrUpstream) || calls.Load() != 1 {
+		t.Errorf("expected a single confirm call, got %v after %d calls", err, calls.Load())
+	}
+	calls.Store(0)
+	if err := exec.Cancel(context.Background(), "user_1", "action_1"); !errors.Is(err, ErrUpstream) || calls.Load() != 1 {
+		t.Errorf("expected a single cancel call, got %v after %d calls", err, calls.Load())
+	}
+
+	// Writes carry the pending action's idempotency key and are retried
+	calls.Store(0)
+	tool := core.NewExecutorTool(core.ToolDefinition{ToolName: "send_money", RequiresUserConfirmation: true}, exec)
+	result, _ := tool.Execute(context.Background(), &core.ToolParams{
+		UserID:         "user_1",
+		Input:          json.RawMessage(`{}`),
+		IdempotencyKey: "key-1",
+	})
+	if result.Success || calls.Load() != 3 || key.Load() != "key-1" {
+		t.Errorf("expected 3 calls with the idempotency key, got %d with %q", calls.Load(), key.Load())
+	}
+}
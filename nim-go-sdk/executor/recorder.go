@@ -0,0 +1,470 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+// RecordMode controls how a Recorder uses its cassette.
+type RecordMode string
+
+const (
+	// ModeRecord calls the wrapped executor and records every interaction.
+	ModeRecord RecordMode = "record"
+
+	// ModeStrict replays from the cassette. Requests must match a recorded
+	// interaction exactly (method, user, tool and canonical input).
+	ModeStrict RecordMode = "strict"
+
+	// ModeFuzzy replays from the cassette, matching on method and tool and
+	// picking the interaction whose input is most similar. User IDs and
+	// RecorderConfig.IgnoreFields are not compared.
+	ModeFuzzy RecordMode = "fuzzy"
+
+	// ModePassthrough replays exact matches and calls the wrapped executor
+	// (recording the result) for anything else.
+	ModePassthrough RecordMode = "passthrough"
+)
+
+// ErrNoInteraction is returned when a replayed request has no recorded match.
+var ErrNoInteraction = errors.New("no matching interaction in cassette")
+
+// cassetteVersion is the current cassette file format.
+const cassetteVersion = 1
+
+// Cassette is a set of recorded executor interactions, stored as JSON.
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded executor call.
+type Interaction struct {
+	// Method is "execute", "execute_write", "confirm" or "cancel".
+	Method   string                `json:"method"`
+	Request  RecordedRequest       `json:"request"`
+	Response *core.ExecuteResponse `json:"response,omitempty"`
+	Error    *RecordedError        `json:"error,omitempty"`
+}
+
+// RecordedRequest is the request half of an interaction.
+type RecordedRequest struct {
+	UserID         string          `json:"user_id,omitempty"`
+	Tool           string          `json:"tool,omitempty"`
+	Input          json.RawMessage `json:"input,omitempty"`
+	ConfirmationID string          `json:"confirmation_id,omitempty"`
+}
+
+// RecordedError is an error returned by the wrapped executor. APIErrors keep
+// their status so replayed errors still match ErrAuth, ErrRateLimited, etc.
+type RecordedError struct {
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Code       int    `json:"code,omitempty"`
+}
+
+// RedactionRule masks sensitive values before they are written to a cassette.
+// Requests are redacted the same way before matching, so replays still match.
+type RedactionRule struct {
+	// Fields are JSON object keys (case-insensitive) whose values are replaced
+	// wherever they appear.
+	Fields []string
+
+	// Pattern replaces matching substrings of any string value.
+	Pattern *regexp.Regexp
+
+	// Replacement is the masked value. Defaults to "[REDACTED]".
+	Replacement string
+}
+
+// DefaultRedactions masks common PII and credentials: names, emails, phone
+// numbers, tokens and JWTs.
+func DefaultRedactions() []RedactionRule {
+	return []RedactionRule{
+		{Fields: []string{"email", "phone", "firstName", "lastName", "first_name", "last_name"}},
+		{Fields: []string{"token", "jwt", "authorization", "apiKey", "api_key", "password", "secret"}},
+		{Pattern: regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)},
+		{Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)},
+	}
+}
+
+// RecorderConfig configures a Recorder.
+type RecorderConfig struct {
+	// Path is the cassette file. It is read in replay modes and written by Save.
+	Path string
+
+	// Mode selects recording or replay behaviour. Defaults to ModeStrict.
+	Mode RecordMode
+
+	// Redactions are applied to recorded requests and responses.
+	// If nil, DefaultRedactions is used; use an empty slice to disable.
+	Redactions []RedactionRule
+
+	// IgnoreFields are input keys not compared in ModeFuzzy (e.g. "cursor").
+	IgnoreFields []string
+}
+
+// Recorder is a core.ToolExecutor that records interactions with a wrapped
+// executor to a cassette, or replays them without it. It works with any
+// executor, including HTTPExecutor and GRPCExecutor:
+//
+//	rec, _ := executor.NewRecorder(httpExec, executor.RecorderConfig{
+//		Path: "testdata/payroll.json",
+//		Mode: executor.ModeRecord,
+//	})
+//	defer rec.Save()
+type Recorder struct {
+	inner  core.ToolExecutor
+	config RecorderConfig
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// Verify Recorder implements core.ToolExecutor.
+var _ core.ToolExecutor = (*Recorder)(nil)
+
+// NewRecorder wraps inner. In ModeStrict and ModeFuzzy inner may be nil and
+// the cassette at cfg.Path must exist; in ModePassthrough it is loaded if present.
+func NewRecorder(inner core.ToolExecutor, cfg RecorderConfig) (*Recorder, error) {
+	if cfg.Mode == "" {
+		cfg.Mode = ModeStrict
+	}
+	if cfg.Redactions == nil {
+		cfg.Redactions = DefaultRedactions()
+	}
+
+	r := &Recorder{
+		inner:    inner,
+		config:   cfg,
+		cassette: &Cassette{Version: cassetteVersion},
+	}
+
+	switch cfg.Mode {
+	case ModeRecord:
+		if inner == nil {
+			return nil, fmt.Errorf("recorder: %s mode requires an executor", cfg.Mode)
+		}
+	case ModeStrict, ModeFuzzy:
+		cassette, err := LoadCassette(cfg.Path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = cassette
+	case ModePassthrough:
+		if inner == nil {
+			return nil, fmt.Errorf("recorder: %s mode requires an executor", cfg.Mode)
+		}
+		cassette, err := LoadCassette(cfg.Path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if cassette != nil {
+			r.cassette = cassette
+		}
+	default:
+		return nil, fmt.Errorf("recorder: unknown mode %q", cfg.Mode)
+	}
+
+	// Saved cassettes are indented; normalise inputs so they compare exactly
+	for i := range r.cassette.Interactions {
+		in := &r.cassette.Interactions[i]
+		in.Request.Input = canonicalJSON(in.Request.Input)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// LoadCassette reads a cassette file.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("recorder: failed to read cassette: %w", err)
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("recorder: failed to parse cassette %s: %w", path, err)
+	}
+	if cassette.Version != cassetteVersion {
+		return nil, fmt.Errorf("recorder: unsupported cassette version %d", cassette.Version)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette to RecorderConfig.Path. It is a no-op in replay-only modes.
+func (r *Recorder) Save() error {
+	if r.config.Mode != ModeRecord && r.config.Mode != ModePassthrough {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("recorder: failed to encode cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.config.Path), 0o755); err != nil {
+		return fmt.Errorf("recorder: failed to create cassette directory: %w", err)
+	}
+	return os.WriteFile(r.config.Path, append(data, '\n'), 0o644)
+}
+
+// Interactions returns a copy of the recorded interactions.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.cassette.Interactions...)
+}
+
+// Execute runs or replays a read-only tool.
+func (r *Recorder) Execute(ctx context.Context, req *core.ExecuteRequest) (*core.ExecuteResponse, error) {
+	return r.do("execute", RecordedRequest{UserID: req.UserID, Tool: req.Tool, Input: req.Input}, func() (*core.ExecuteResponse, error) {
+		return r.inner.Execute(ctx, req)
+	})
+}
+
+// ExecuteWrite runs or replays a write tool.
+func (r *Recorder) ExecuteWrite(ctx context.Context, req *core.ExecuteRequest) (*core.ExecuteResponse, error) {
+	return r.do("execute_write", RecordedRequest{UserID: req.UserID, Tool: req.Tool, Input: req.Input}, func() (*core.ExecuteResponse, error) {
+		return r.inner.ExecuteWrite(ctx, req)
+	})
+}
+
+// Confirm runs or replays a confirmation.
+func (r *Recorder) Confirm(ctx context.Context, userID, confirmationID string) (*core.ExecuteResponse, error) {
+	return r.do("confirm", RecordedRequest{UserID: userID, ConfirmationID: confirmationID}, func() (*core.ExecuteResponse, error) {
+		return r.inner.Confirm(ctx, userID, confirmationID)
+	})
+}
+
+// Cancel runs or replays a cancellation.
+func (r *Recorder) Cancel(ctx context.Context, userID, confirmationID string) error {
+	_, err := r.do("cancel", RecordedRequest{UserID: userID, ConfirmationID: confirmationID}, func() (*core.ExecuteResponse, error) {
+		return nil, r.inner.Cancel(ctx, userID, confirmationID)
+	})
+	return err
+}
+
+func (r *Recorder) do(method string, req RecordedRequest, call func() (*core.ExecuteResponse, error)) (*core.ExecuteResponse, error) {
+	req.UserID = r.redactString(req.UserID)
+	req.Input = r.redactJSON(canonicalJSON(req.Input))
+
+	if r.config.Mode != ModeRecord {
+		if interaction, ok := r.match(method, req); ok {
+			return interaction.replay()
+		}
+		if r.config.Mode != ModePassthrough {
+			return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, method, req.Tool)
+		}
+	}
+
+	resp, err := call()
+	r.record(method, req, resp, err)
+	return resp, err
+}
+
+// match finds the interaction to replay, preferring ones not yet used.
+func (r *Recorder) match(method string, req RecordedRequest) (*Interaction, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fuzzy := r.config.Mode == ModeFuzzy
+	best, bestScore, bestUsed := -1, -1, true
+	for i := range r.cassette.Interactions {
+		in := &r.cassette.Interactions[i]
+		if in.Method != method || in.Request.Tool != req.Tool {
+			continue
+		}
+
+		score := 0
+		if fuzzy {
+			score = similarity(in.Request.Input, req.Input, r.config.IgnoreFields)
+		} else if in.Request.UserID != req.UserID || in.Request.ConfirmationID != req.ConfirmationID ||
+			string(in.Request.Input) != string(req.Input) {
+			continue
+		}
+
+		// Unused interactions win; among equals, the highest score, then the earliest
+		if (bestUsed && !r.used[i]) || (bestUsed == r.used[i] && score > bestScore) {
+			best, bestScore, bestUsed = i, score, r.used[i]
+		}
+	}
+	if best < 0 {
+		return nil, false
+	}
+	r.used[best] = true
+	return &r.cassette.Interactions[best], true
+}
+
+func (r *Recorder) record(method string, req RecordedRequest, resp *core.ExecuteResponse, err error) {
+	interaction := Interaction{Method: method, Request: req}
+	if resp != nil {
+		redacted := *resp
+		redacted.Data = r.redactJSON(resp.Data)
+		redacted.Error = r.redactString(resp.Error)
+		interaction.Response = &redacted
+	}
+	if err != nil {
+		recorded := &RecordedError{Message: r.redactString(err.Error())}
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			recorded.Message = r.redactString(apiErr.Message)
+			recorded.StatusCode = apiErr.StatusCode
+			recorded.Code = apiErr.Code
+		}
+		interaction.Error = recorded
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.used = append(r.used, true)
+}
+
+// replay returns the recorded response or error.
+func (in *Interaction) replay() (*core.ExecuteResponse, error) {
+	var resp *core.ExecuteResponse
+	if in.Response != nil {
+		copied := *in.Response
+		resp = &copied
+	}
+	if in.Error == nil {
+		return resp, nil
+	}
+	if in.Error.StatusCode != 0 {
+		// Rebuild the APIError so errors.Is works on replayed errors
+		return resp, &APIError{
+			Kind:       errorKind(in.Error.StatusCode),
+			StatusCode: in.Error.StatusCode,
+			Code:       in.Error.Code,
+			Message:    in.Error.Message,
+		}
+	}
+	return resp, errors.New(in.Error.Message)
+}
+
+// redactJSON applies redaction rules to every value in a JSON document.
+func (r *Recorder) redactJSON(data json.RawMessage) json.RawMessage {
+	if len(data) == 0 || len(r.config.Redactions) == 0 {
+		return data
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return json.RawMessage(r.redactString(string(data)))
+	}
+	redacted, err := json.Marshal(r.redactValue(v))
+	if err != nil {
+		return data
+	}
+	return redacted
+}
+
+func (r *Recorder) redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			if replacement, ok := r.redactedField(k); ok {
+				if child != nil {
+					val[k] = replacement
+				}
+				continue
+			}
+			val[k] = r.redactValue(child)
+		}
+		return val
+	case []interface{}:
+		for i, child := range val {
+			val[i] = r.redactValue(child)
+		}
+		return val
+	case string:
+		return r.redactString(val)
+	default:
+		return val
+	}
+}
+
+func (r *Recorder) redactedField(key string) (string, bool) {
+	for _, rule := range r.config.Redactions {
+		for _, field := range rule.Fields {
+			if strings.EqualFold(field, key) {
+				return replacement(rule), true
+			}
+		}
+	}
+	return "", false
+}
+
+func (r *Recorder) redactString(s string) string {
+	for _, rule := range r.config.Redactions {
+		if rule.Pattern != nil {
+			s = rule.Pattern.ReplaceAllLiteralString(s, replacement(rule))
+		}
+	}
+	return s
+}
+
+func replacement(rule RedactionRule) string {
+	if rule.Replacement != "" {
+		return rule.Replacement
+	}
+	return "[REDACTED]"
+}
+
+// canonicalJSON re-encodes JSON with sorted keys so equivalent inputs compare equal.
+func canonicalJSON(data json.RawMessage) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return data
+	}
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return data
+	}
+	return canonical
+}
+
+// similarity counts the top-level input fields two requests share, ignoring ignore.
+func similarity(a, b json.RawMessage, ignore []string) int {
+	var ma, mb map[string]interface{}
+	json.Unmarshal(a, &ma)
+	json.Unmarshal(b, &mb)
+
+	score := 0
+	for k, va := range ma {
+		if containsFold(ignore, k) {
+			continue
+		}
+		if vb, ok := mb[k]; ok {
+			ja, _ := json.Marshal(va)
+			jb, _ := json.Marshal(vb)
+			if string(ja) == string(jb) {
+				score++
+			}
+		}
+	}
+	return score
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
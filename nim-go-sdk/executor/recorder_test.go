@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+func TestRecorderRecordAndReplay(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/nim/v1/agent/profile":
+			w.Write([]byte(`{"userId":"user_1","displayTag":"@alice","email":"alice@example.com","phone":"+15550000001"}`))
+		case "/nim/v1/agent/transactions":
+			w.Write([]byte(`{"transactions":[{"id":"tx_1","type":"send","note":"paid alice@example.com"}]}`))
+		default:
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "profile.json")
+	httpExec := NewHTTPExecutor(HTTPExecutorConfig{BaseURL: ts.URL, MaxRetries: -1})
+	rec, err := NewRecorder(httpExec, RecorderConfig{Path: path, Mode: ModeRecord})
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+
+	ctx := context.Background()
+	profileReq := &core.ExecuteRequest{UserID: "user_1", Tool: "get_profile"}
+	txReq := &core.ExecuteRequest{UserID: "user_1", Tool: "get_transactions", Input: json.RawMessage(`{"limit": 10, "type": "send"}`)}
+	if _, err := rec.Execute(ctx, profileReq); err != nil {
+		t.Fatalf("record get_profile failed: %v", err)
+	}
+	if _, err := rec.Execute(ctx, txReq); err != nil {
+		t.Fatalf("record get_transactions failed: %v", err)
+	}
+	if _, err := rec.Execute(ctx, &core.ExecuteRequest{UserID: "user_1", Tool: "get_balance"}); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected rate limit error while recording, got %v", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "alice@example.com") || strings.Contains(string(data), "+15550000001") {
+		t.Errorf("cassette contains unredacted PII:\n%s", data)
+	}
+
+	// Strict replay needs no executor
+	replay, err := NewRecorder(nil, RecorderConfig{Path: path, Mode: ModeStrict})
+	if err != nil {
+		t.Fatalf("NewRecorder (strict) failed: %v", err)
+	}
+	resp, err := replay.Execute(ctx, &core.ExecuteRequest{
+		UserID: "user_1",
+		Tool:   "get_transactions",
+		Input:  json.RawMessage(`{"type":"send","limit":10}`), // same input, different key order
+	})
+	if err != nil || !resp.Success || !strings.Contains(string(resp.Data), "tx_1") {
+		t.Fatalf("strict replay failed: %v %+v", err, resp)
+	}
+	if _, err := replay.Execute(ctx, &core.ExecuteRequest{UserID: "user_1", Tool: "get_balance"}); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected replayed rate limit error, got %v", err)
+	}
+	if _, err := replay.Execute(ctx, &core.ExecuteRequest{UserID: "user_1", Tool: "get_transactions", Input: json.RawMessage(`{"limit":50}`)}); !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("expected strict mismatch, got %v", err)
+	}
+
+	// Fuzzy replay tolerates different users and inputs
+	fuzzy, err := NewRecorder(nil, RecorderConfig{Path: path, Mode: ModeFuzzy})
+	if err != nil {
+		t.Fatalf("NewRecorder (fuzzy) failed: %v", err)
+	}
+	resp, err = fuzzy.Execute(ctx, &core.ExecuteRequest{UserID: "user_2", Tool: "get_transactions", Input: json.RawMessage(`{"limit":50}`)})
+	if err != nil || !strings.Contains(string(resp.Data), "tx_1") {
+		t.Errorf("fuzzy replay failed: %v %+v", err, resp)
+	}
+}
+
+func TestRecorderPassthrough(t *testing.T) {
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"balances":[],"totalUsd":"0.00"}`))
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "balance.json")
+	rec, err := NewRecorder(NewHTTPExecutor(HTTPExecutorConfig{BaseURL: ts.URL}), RecorderConfig{Path: path, Mode: ModePassthrough})
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+
+	req := &core.ExecuteRequest{UserID: "user_1", Tool: "get_balance"}
+	for i := 0; i < 3; i++ {
+		if _, err := rec.Execute(context.Background(), req); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+	}
+	if calls != 1 || len(rec.Interactions()) != 1 {
+		t.Errorf("expected one upstream call and one interaction, got %d and %d", calls, len(rec.Interactions()))
+	}
+}
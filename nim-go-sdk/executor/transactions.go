@@ -0,0 +1,306 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/core/money"
+)
+
+// defaultMaxPages bounds a TransactionPager when no page limit is given.
+const defaultMaxPages = 10
+
+// ErrMaxPages is returned by a TransactionPager that stopped at its page
+// limit while more history remained.
+var ErrMaxPages = errors.New("transaction page limit reached")
+
+// TransactionQuery is the input of the get_transactions tool.
+type TransactionQuery struct {
+	// Limit is the page size. Defaults to 10.
+	Limit int `json:"limit,omitempty"`
+
+	// Type filters by transaction type: send, receive, deposit or withdraw.
+	Type string `json:"type,omitempty"`
+
+	// Cursor continues from a previous page's NextCursor.
+	Cursor string `json:"cursor,omitempty"`
+
+	// StartDate and EndDate bound CreatedAt, inclusive. Each is a date
+	// (2006-01-02) or an RFC 3339 timestamp; a date EndDate covers the whole day.
+	StartDate string `json:"start_date,omitempty"`
+	EndDate   string `json:"end_date,omitempty"`
+
+	// Counterparty matches display tags or names, case-insensitively and
+	// ignoring a leading "@".
+	Counterparty string `json:"counterparty,omitempty"`
+
+	// MinAmount is a decimal string; smaller transactions are skipped.
+	MinAmount string `json:"min_amount,omitempty"`
+}
+
+// Validate checks the date and amount filters.
+func (q TransactionQuery) Validate() error {
+	_, err := q.compile()
+	return err
+}
+
+// Match reports whether tx passes the query's filters. Limit and Cursor are
+// ignored. Invalid filters match nothing; use Validate to report them.
+func (q TransactionQuery) Match(tx Transaction) bool {
+	f, err := q.compile()
+	if err != nil {
+		return false
+	}
+	return f.match(tx)
+}
+
+// transactionFilter is a TransactionQuery with parsed filters.
+type transactionFilter struct {
+	txType       string
+	start, end   time.Time // zero when unbounded; end is exclusive
+	counterparty string
+	minAmount    *money.Decimal
+}
+
+func (q TransactionQuery) compile() (*transactionFilter, error) {
+	f := &transactionFilter{
+		txType:       q.Type,
+		counterparty: normalizeCounterparty(q.Counterparty),
+	}
+
+	var err error
+	if q.StartDate != "" {
+		if f.start, err = parseQueryTime(q.StartDate, false); err != nil {
+			return nil, fmt.Errorf("invalid start_date: %w", err)
+		}
+	}
+	if q.EndDate != "" {
+		if f.end, err = parseQueryTime(q.EndDate, true); err != nil {
+			return nil, fmt.Errorf("invalid end_date: %w", err)
+		}
+	}
+	if !f.start.IsZero() && !f.end.IsZero() && !f.start.Before(f.end) {
+		return nil, fmt.Errorf("start_date must be before end_date")
+	}
+	if q.MinAmount != "" {
+		amount, err := money.ParseDecimal(q.MinAmount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min_amount %q", q.MinAmount)
+		}
+		f.minAmount = &amount
+	}
+	return f, nil
+}
+
+func (f *transactionFilter) match(tx Transaction) bool {
+	if f.txType != "" && tx.Type != f.txType {
+		return false
+	}
+	if !f.start.IsZero() || !f.end.IsZero() {
+		created, err := time.Parse(time.RFC3339, tx.CreatedAt)
+		if err != nil {
+			return false
+		}
+		if created.Before(f.start) || (!f.end.IsZero() && !created.Before(f.end)) {
+			return false
+		}
+	}
+	if f.counterparty != "" && !strings.Contains(normalizeCounterparty(tx.Counterparty), f.counterparty) {
+		return false
+	}
+	if f.minAmount != nil && tx.Amount.Abs().Cmp(*f.minAmount) < 0 {
+		return false
+	}
+	return true
+}
+
+// before reports whether tx is older than the start of the range.
+func (f *transactionFilter) before(tx Transaction) bool {
+	if f.start.IsZero() {
+		return false
+	}
+	created, err := time.Parse(time.RFC3339, tx.CreatedAt)
+	return err == nil && created.Before(f.start)
+}
+
+// parseQueryTime parses a date or RFC 3339 timestamp. With endOfDay, a date
+// is moved to the start of the following day so ranges include it.
+func parseQueryTime(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected YYYY-MM-DD or RFC 3339, got %q", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+func normalizeCounterparty(s string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(s), "@"))
+}
+
+// FilterTransactions returns the transactions in resp that match q.
+func FilterTransactions(resp *GetTransactionsResponse, q TransactionQuery) (*GetTransactionsResponse, error) {
+	f, err := q.compile()
+	if err != nil {
+		return nil, err
+	}
+	filtered := &GetTransactionsResponse{Transactions: []Transaction{}, NextCursor: resp.NextCursor}
+	for _, tx := range resp.Transactions {
+		if f.match(tx) {
+			filtered.Transactions = append(filtered.Transactions, tx)
+		}
+	}
+	return filtered, nil
+}
+
+// TransactionPager walks get_transactions pages lazily through any
+// core.ToolExecutor, newest first:
+//
+//	pager := executor.NewTransactionPager(exec, userID, executor.TransactionQuery{
+//		StartDate: "2026-01-01",
+//		EndDate:   "2026-01-31",
+//	}, 5)
+//	for pager.Next(ctx) {
+//		tx := pager.Transaction()
+//		...
+//	}
+//	if err := pager.Err(); err != nil { ... }
+//
+// Filters are sent to the executor and also applied to each page, so
+// backends that ignore them still yield only matching transactions. Paging
+// stops early once transactions are older than StartDate.
+type TransactionPager struct {
+	exec     core.ToolExecutor
+	userID   string
+	query    TransactionQuery
+	maxPages int
+
+	filter *transactionFilter
+	page   []Transaction
+	index  int
+	pages  int
+	done   bool
+	err    error
+}
+
+// NewTransactionPager creates a pager that fetches at most maxPages pages.
+// If maxPages is zero or negative, it defaults to 10.
+func NewTransactionPager(exec core.ToolExecutor, userID string, query TransactionQuery, maxPages int) *TransactionPager {
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+	p := &TransactionPager{
+		exec:     exec,
+		userID:   userID,
+		query:    query,
+		maxPages: maxPages,
+		index:    -1,
+	}
+	p.filter, p.err = query.compile()
+	return p
+}
+
+// Next advances to the next matching transaction, fetching a page if needed.
+// It returns false when history is exhausted or on error.
+func (p *TransactionPager) Next(ctx context.Context) bool {
+	for p.err == nil {
+		if err := ctx.Err(); err != nil {
+			p.err = err
+			return false
+		}
+		for p.index+1 < len(p.page) {
+			p.index++
+			tx := p.page[p.index]
+			if p.filter.before(tx) {
+				p.done = true
+				p.page = nil
+				return false
+			}
+			if p.filter.match(tx) {
+				return true
+			}
+		}
+		if p.done {
+			return false
+		}
+		if p.pages == p.maxPages {
+			p.err = ErrMaxPages
+			return false
+		}
+		p.err = p.fetch(ctx)
+	}
+	return false
+}
+
+func (p *TransactionPager) fetch(ctx context.Context) error {
+	input, err := json.Marshal(p.query)
+	if err != nil {
+		return err
+	}
+	resp, err := p.exec.Execute(ctx, &core.ExecuteRequest{
+		UserID: p.userID,
+		Tool:   "get_transactions",
+		Input:  input,
+	})
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return fmt.Errorf("get_transactions failed: %s", resp.Error)
+	}
+
+	var page GetTransactionsResponse
+	if err := json.Unmarshal(resp.Data, &page); err != nil {
+		return fmt.Errorf("failed to parse transactions: %w", err)
+	}
+
+	p.pages++
+	p.page = page.Transactions
+	p.index = -1
+	p.query.Cursor = page.NextCursor
+	if page.NextCursor == "" {
+		p.done = true
+	}
+	return nil
+}
+
+// Transaction returns the current transaction.
+func (p *TransactionPager) Transaction() Transaction {
+	if p.index < 0 || p.index >= len(p.page) {
+		return Transaction{}
+	}
+	return p.page[p.index]
+}
+
+// Err returns the error that stopped the pager, if any. It is ErrMaxPages
+// when the page limit cut the walk short.
+func (p *TransactionPager) Err() error {
+	return p.err
+}
+
+// Pages returns the number of pages fetched so far.
+func (p *TransactionPager) Pages() int {
+	return p.pages
+}
+
+// ListTransactions collects every transaction matching query, fetching at
+// most maxPages pages. On ErrMaxPages the transactions found so far are
+// returned with the error.
+func ListTransactions(ctx context.Context, exec core.ToolExecutor, userID string, query TransactionQuery, maxPages int) ([]Transaction, error) {
+	pager := NewTransactionPager(exec, userID, query, maxPages)
+	var txs []Transaction
+	for pager.Next(ctx) {
+		txs = append(txs, pager.Transaction())
+	}
+	return txs, pager.Err()
+}
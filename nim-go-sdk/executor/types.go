@@ -1,32 +1,41 @@
 package executor
 
+import "github.com/becomeliminal/nim-go-sdk/core/money"
+
 // Response types that match nim/gateway proto definitions
-// These use camelCase JSON tags to match the grpc-gateway JSON output
+// These use camelCase JSON tags to match the grpc-gateway JSON output.
+// Amounts are money.Decimal, which keeps the gateway's decimal strings
+// exact and marshals back to the same strings.
 
 // Wallet types
 type GetBalanceResponse struct {
 	Balances []WalletBalance `json:"balances"`
-	TotalUSD string          `json:"totalUsd"`
+	TotalUSD money.Decimal   `json:"totalUsd"`
 }
 
 type WalletBalance struct {
-	Currency string `json:"currency"`
-	Amount   string `json:"amount"`
-	USDValue string `json:"usdValue"`
+	Currency string        `json:"currency"`
+	Amount   money.Decimal `json:"amount"`
+	USDValue money.Decimal `json:"usdValue"`
+}
+
+// Money returns the balance as an amount in its currency.
+func (b WalletBalance) Money() money.Amount {
+	return money.New(b.Amount, b.Currency)
 }
 
 // Savings types
 type GetSavingsBalanceResponse struct {
 	Positions []SavingsPosition `json:"positions"`
-	TotalUSD  string            `json:"totalUsd"`
+	TotalUSD  money.Decimal     `json:"totalUsd"`
 }
 
 type SavingsPosition struct {
-	Currency     string `json:"currency"`
-	Deposited    string `json:"deposited"`
-	CurrentValue string `json:"currentValue"`
-	APY          string `json:"apy"`
-	Earnings     string `json:"earnings"`
+	Currency     string        `json:"currency"`
+	Deposited    money.Decimal `json:"deposited"`
+	CurrentValue money.Decimal `json:"currentValue"`
+	APY          money.Decimal `json:"apy"`
+	Earnings     money.Decimal `json:"earnings"`
 }
 
 type GetVaultRatesResponse struct {
@@ -34,9 +43,9 @@ type GetVaultRatesResponse struct {
 }
 
 type VaultRate struct {
-	Currency string `json:"currency"`
-	APY      string `json:"apy"`
-	TVL      string `json:"tvl"`
+	Currency string        `json:"currency"`
+	APY      money.Decimal `json:"apy"`
+	TVL      money.Decimal `json:"tvl"`
 }
 
 type DepositResponse struct {
@@ -68,17 +77,22 @@ type GetTransactionsResponse struct {
 }
 
 type Transaction struct {
-	ID           string `json:"id"`
-	Type         string `json:"type"`
-	Amount       string `json:"amount"`
-	Currency     string `json:"currency"`
-	USDValue     string `json:"usdValue"`
-	Counterparty string `json:"counterparty"`
-	Note         string `json:"note"`
-	Status       string `json:"status"`
-	Direction    string `json:"direction"`
-	CreatedAt    string `json:"createdAt"`
-	TxHash       string `json:"txHash"`
+	ID           string        `json:"id"`
+	Type         string        `json:"type"`
+	Amount       money.Decimal `json:"amount"`
+	Currency     string        `json:"currency"`
+	USDValue     money.Decimal `json:"usdValue"`
+	Counterparty string        `json:"counterparty"`
+	Note         string        `json:"note"`
+	Status       string        `json:"status"`
+	Direction    string        `json:"direction"`
+	CreatedAt    string        `json:"createdAt"`
+	TxHash       string        `json:"txHash"`
+}
+
+// Money returns the transaction amount in its currency.
+func (t Transaction) Money() money.Amount {
+	return money.New(t.Amount, t.Currency)
 }
 
 // Users types
@@ -116,11 +130,11 @@ type ConversationSummary struct {
 }
 
 type GetConversationResponse struct {
-	ID        string         `json:"id"`
-	Title     string         `json:"title"`
-	Messages  []ChatMessage  `json:"messages"`
-	CreatedAt int64          `json:"createdAt"`
-	UpdatedAt int64          `json:"updatedAt"`
+	ID        string        `json:"id"`
+	Title     string        `json:"title"`
+	Messages  []ChatMessage `json:"messages"`
+	CreatedAt int64         `json:"createdAt"`
+	UpdatedAt int64         `json:"updatedAt"`
 }
 
 type ChatMessage struct {
@@ -0,0 +1,210 @@
+// Package fx converts amounts between currencies.
+//
+// A RateSource quotes exchange rates. StaticSource serves a fixed table,
+// optionally loaded from a JSON or YAML file, for offline use:
+//
+//	rates, err := fx.LoadFile("rates.yaml")
+//	usd, rate, err := fx.Convert(ctx, rates, money.MustParse("100.00", "EUR"), "USD")
+//
+// Every conversion reports the Rate it used, including when the rate was
+// quoted, so the agent can say how fresh it is. NewConvertTool exposes
+// conversion as the convert_currency tool, and RebaseBalances and
+// RebaseTransactions express executor results in the user's currency.
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core/money"
+	"gopkg.in/yaml.v3"
+)
+
+// RatePlaces is the number of decimal places kept in derived rates, such
+// as inverse and cross rates.
+const RatePlaces = 10
+
+// ErrRateNotFound is returned when a source has no rate for a pair.
+var ErrRateNotFound = errors.New("fx: rate not found")
+
+// Rate is the price of one unit of From in To: 1 From = Rate To.
+type Rate struct {
+	From string        `json:"from"`
+	To   string        `json:"to"`
+	Rate money.Decimal `json:"rate"`
+
+	// AsOf is when the rate was quoted. It is zero for a currency's rate
+	// to itself.
+	AsOf time.Time `json:"as_of"`
+
+	// Source names where the rate came from, e.g. "static".
+	Source string `json:"source,omitempty"`
+}
+
+// MarshalJSON omits as_of when it is zero.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	type rate Rate
+	v := struct {
+		rate
+		AsOf *time.Time `json:"as_of,omitempty"`
+	}{rate: rate(r)}
+	if !r.AsOf.IsZero() {
+		v.AsOf = &r.AsOf
+	}
+	return json.Marshal(v)
+}
+
+// RateSource quotes exchange rates. Implementations must be safe for
+// concurrent use.
+type RateSource interface {
+	// Rate returns the rate from one currency to another, or an error
+	// wrapping ErrRateNotFound.
+	Rate(ctx context.Context, from, to string) (Rate, error)
+}
+
+// Convert converts amount into currency to, rounded half-even to the
+// currency's precision, and returns the rate it used.
+func Convert(ctx context.Context, src RateSource, amount money.Amount, to string) (money.Amount, Rate, error) {
+	to = strings.ToUpper(to)
+	if amount.Currency == to {
+		return amount, Rate{From: to, To: to, Rate: money.NewDecimal(1, 0)}, nil
+	}
+	rate, err := src.Rate(ctx, amount.Currency, to)
+	if err != nil {
+		return money.Amount{}, Rate{}, err
+	}
+	return money.New(amount.Value, to).Mul(rate.Rate, money.RoundHalfEven), rate, nil
+}
+
+// StaticSource serves rates from a fixed table of units per base currency.
+// Rates between other currencies are derived through the base. Use Update
+// to replace the table while the source is in use.
+type StaticSource struct {
+	mu     sync.RWMutex
+	base   string
+	rates  map[string]money.Decimal
+	asOf   time.Time
+	source string
+}
+
+// NewStaticSource creates a source from rates quoted as units of each
+// currency per one unit of base, e.g. base "USD" with {"EUR": "0.92"}.
+// asOf is when the rates were quoted.
+func NewStaticSource(base string, rates map[string]money.Decimal, asOf time.Time) (*StaticSource, error) {
+	s := &StaticSource{source: "static"}
+	if err := s.Update(base, rates, asOf); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update replaces the source's rates.
+func (s *StaticSource) Update(base string, rates map[string]money.Decimal, asOf time.Time) error {
+	base = strings.ToUpper(strings.TrimSpace(base))
+	if base == "" {
+		return errors.New("fx: base currency is required")
+	}
+	table := map[string]money.Decimal{base: money.NewDecimal(1, 0)}
+	for currency, rate := range rates {
+		if rate.Sign() <= 0 {
+			return fmt.Errorf("fx: rate for %s must be positive", currency)
+		}
+		table[strings.ToUpper(currency)] = rate
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.base, s.rates, s.asOf = base, table, asOf
+	return nil
+}
+
+// Currencies returns the currencies the source has rates for.
+func (s *StaticSource) Currencies() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	currencies := make([]string, 0, len(s.rates))
+	for currency := range s.rates {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// Rate returns the rate from one currency to another.
+func (s *StaticSource) Rate(ctx context.Context, from, to string) (Rate, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fromRate, ok := s.rates[from]
+	if !ok {
+		return Rate{}, fmt.Errorf("%w: %s", ErrRateNotFound, from)
+	}
+	toRate, ok := s.rates[to]
+	if !ok {
+		return Rate{}, fmt.Errorf("%w: %s", ErrRateNotFound, to)
+	}
+
+	// 1 from = (toRate / fromRate) to, both quoted per unit of base
+	rate := toRate
+	if from != s.base {
+		rate, _ = toRate.Quo(fromRate, RatePlaces, money.RoundHalfEven)
+	}
+	return Rate{From: from, To: to, Rate: rate, AsOf: s.asOf, Source: s.source}, nil
+}
+
+// rateFile is the file format read by LoadFile and Parse.
+type rateFile struct {
+	Base   string                   `yaml:"base"`
+	AsOf   time.Time                `yaml:"as_of"`
+	Source string                   `yaml:"source"`
+	Rates  map[string]money.Decimal `yaml:"rates"`
+}
+
+// LoadFile reads rates from a JSON or YAML file. If the file has no
+// as_of, its modification time is used.
+//
+//	base: USD
+//	as_of: 2026-10-18T09:00:00Z
+//	rates:
+//	  EUR: 0.92
+//	  GBP: 0.79
+func LoadFile(path string) (*StaticSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if s.asOf.IsZero() {
+		if info, err := os.Stat(path); err == nil {
+			s.asOf = info.ModTime().UTC()
+		}
+	}
+	return s, nil
+}
+
+// Parse reads rates in the LoadFile format.
+func Parse(data []byte) (*StaticSource, error) {
+	var file rateFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid rate file: %w", err)
+	}
+	s, err := NewStaticSource(file.Base, file.Rates, file.AsOf)
+	if err != nil {
+		return nil, err
+	}
+	if file.Source != "" {
+		s.source = file.Source
+	}
+	return s, nil
+}
@@ -0,0 +1,189 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/core/money"
+	"github.com/becomeliminal/nim-go-sdk/executor"
+)
+
+var quoted = time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+func testSource(t *testing.T) *StaticSource {
+	t.Helper()
+	s, err := NewStaticSource("usd", map[string]money.Decimal{
+		"eur": money.MustParseDecimal("0.92"),
+		"GBP": money.MustParseDecimal("0.79"),
+		"JPY": money.MustParseDecimal("150"),
+	}, quoted)
+	if err != nil {
+		t.Fatalf("NewStaticSource: %v", err)
+	}
+	return s
+}
+
+// countingSource counts rate lookups.
+type countingSource struct {
+	RateSource
+	calls int
+}
+
+func (c *countingSource) Rate(ctx context.Context, from, to string) (Rate, error) {
+	c.calls++
+	return c.RateSource.Rate(ctx, from, to)
+}
+
+func TestStaticSource(t *testing.T) {
+	s := testSource(t)
+	for _, tt := range []struct{ from, to, want string }{
+		{"USD", "EUR", "0.92"},
+		{"eur", "usd", "1.0869565217"},
+		{"EUR", "GBP", "0.8586956522"},
+		{"JPY", "USD", "0.0066666667"},
+	} {
+		rate, err := s.Rate(context.Background(), tt.from, tt.to)
+		if err != nil || rate.Rate.String() != tt.want || !rate.AsOf.Equal(quoted) || rate.Source != "static" {
+			t.Errorf("Rate(%s, %s) = %+v, %v; want %s", tt.from, tt.to, rate, err, tt.want)
+		}
+	}
+	if _, err := s.Rate(context.Background(), "USD", "CHF"); !errors.Is(err, ErrRateNotFound) {
+		t.Errorf("expected ErrRateNotFound, got %v", err)
+	}
+	if got := strings.Join(s.Currencies(), ","); got != "EUR,GBP,JPY,USD" {
+		t.Errorf("Currencies = %s", got)
+	}
+
+	if _, err := NewStaticSource("", nil, quoted); err == nil {
+		t.Error("expected an error without a base")
+	}
+	if _, err := NewStaticSource("USD", map[string]money.Decimal{"EUR": {}}, quoted); err == nil {
+		t.Error("expected an error for a zero rate")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	s := testSource(t)
+	ctx := context.Background()
+
+	usd, rate, err := Convert(ctx, s, money.MustParse("100.00", "EUR"), "usd")
+	if err != nil || usd.String() != "108.70 USD" || rate.Rate.String() != "1.0869565217" {
+		t.Errorf("Convert = %s, %+v, %v", usd, rate, err)
+	}
+	yen, _, err := Convert(ctx, s, money.MustParse("10.00", "USD"), "JPY")
+	if err != nil || yen.String() != "1500 JPY" {
+		t.Errorf("Convert to JPY = %s, %v", yen, err)
+	}
+	same, rate, err := Convert(ctx, s, money.MustParse("5", "CHF"), "CHF")
+	if err != nil || same.String() != "5.00 CHF" || rate.Rate.String() != "1" || !rate.AsOf.IsZero() {
+		t.Errorf("Convert to the same currency = %s, %+v, %v", same, rate, err)
+	}
+	data, _ := json.Marshal(rate)
+	if string(data) != `{"from":"CHF","to":"CHF","rate":"1"}` {
+		t.Errorf("identity rate JSON = %s", data)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "rates.yaml")
+	os.WriteFile(yamlPath, []byte("base: EUR\nas_of: 2026-10-18T09:00:00Z\nsource: ecb\nrates:\n  USD: 1.0869565217\n  GBP: '0.8586956522'\n"), 0o600)
+	s, err := LoadFile(yamlPath)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	rate, err := s.Rate(context.Background(), "EUR", "USD")
+	if err != nil || rate.Rate.String() != "1.0869565217" || !rate.AsOf.Equal(quoted) || rate.Source != "ecb" {
+		t.Errorf("Rate = %+v, %v", rate, err)
+	}
+
+	jsonPath := filepath.Join(dir, "rates.json")
+	os.WriteFile(jsonPath, []byte(`{"base": "USD", "rates": {"EUR": 0.92}}`), 0o600)
+	modTime := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	os.Chtimes(jsonPath, modTime, modTime)
+	s, err = LoadFile(jsonPath)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	if rate, _ := s.Rate(context.Background(), "USD", "EUR"); !rate.AsOf.Equal(modTime) {
+		t.Errorf("expected the file's modification time, got %v", rate.AsOf)
+	}
+
+	if _, err := Parse([]byte("rates: [1, 2]")); err == nil {
+		t.Error("expected an error for an invalid file")
+	}
+}
+
+func TestRebase(t *testing.T) {
+	src := &countingSource{RateSource: testSource(t)}
+	ctx := context.Background()
+
+	summary, err := RebaseBalances(ctx, src, []executor.WalletBalance{
+		{Currency: "USD", Amount: money.MustParseDecimal("100.00")},
+		{Currency: "EUR", Amount: money.MustParseDecimal("50.00")},
+		{Currency: "USD", Amount: money.MustParseDecimal("0.50")},
+	}, PreferredCurrency(&core.UserPreferences{Currency: "eur"}))
+	if err != nil {
+		t.Fatalf("RebaseBalances: %v", err)
+	}
+	if summary.Currency != "EUR" || summary.Total.String() != "142.46 EUR" || len(summary.Balances) != 3 || summary.Balances[0].Amount.String() != "92.00 EUR" {
+		t.Errorf("summary = %+v", summary)
+	}
+	if src.calls != 1 {
+		t.Errorf("expected one rate lookup, got %d", src.calls)
+	}
+
+	txs, err := RebaseTransactions(ctx, src, []executor.Transaction{
+		{ID: "tx_1", Currency: "GBP", Amount: money.MustParseDecimal("79.00")},
+	}, "USD")
+	if err != nil || len(txs) != 1 || txs[0].Converted.Amount.String() != "100.00 USD" {
+		t.Fatalf("RebaseTransactions = %+v, %v", txs, err)
+	}
+	data, _ := json.Marshal(txs[0])
+	if !strings.Contains(string(data), `"id":"tx_1"`) || !strings.Contains(string(data), `"converted":{"original":{"amount":"79.00","currency":"GBP"},"amount":{"amount":"100.00","currency":"USD"}`) {
+		t.Errorf("transaction JSON = %s", data)
+	}
+
+	if _, err := RebaseBalances(ctx, src, []executor.WalletBalance{{Currency: "LIL"}}, "USD"); !errors.Is(err, ErrRateNotFound) {
+		t.Errorf("expected ErrRateNotFound, got %v", err)
+	}
+	if PreferredCurrency(nil) != DefaultCurrency || PreferredCurrency(core.DefaultPreferences()) != "USD" {
+		t.Error("PreferredCurrency defaults")
+	}
+}
+
+func TestConvertTool(t *testing.T) {
+	tool := NewConvertTool(testSource(t))
+	tool.now = func() time.Time { return quoted.Add(90 * time.Minute) }
+	if !strings.Contains(tool.Description(), "Supported currencies: EUR, GBP, JPY, USD.") {
+		t.Errorf("description = %q", tool.Description())
+	}
+
+	result, err := tool.Execute(context.Background(), &core.ToolParams{Input: json.RawMessage(`{"amount":"250","from":"usd","to":"eur"}`)})
+	if err != nil || !result.Success {
+		t.Fatalf("Execute = %+v, %v", result, err)
+	}
+	data, _ := json.Marshal(result.Data)
+	want := `{"as_of":"2026-10-18T09:00:00Z","from":{"amount":"250.00","currency":"USD"},"rate":"0.92","rate_age":"1h30m0s","source":"static","to":{"amount":"230.00","currency":"EUR"}}`
+	if string(data) != want {
+		t.Errorf("data = %s\nwant   %s", data, want)
+	}
+
+	for input, want := range map[string]string{
+		`{"amount":12.5,"from":"USD","to":"CHF"}`:  "no exchange rate from USD to CHF",
+		`{"from":"USD","to":"EUR"}`:                "invalid input: amount, from and to are required",
+		`{"amount":"ten","from":"USD","to":"EUR"}`: "invalid input: money: invalid decimal \"ten\"",
+	} {
+		result, _ := tool.Execute(context.Background(), &core.ToolParams{Input: json.RawMessage(input)})
+		if result.Success || result.Error != want {
+			t.Errorf("%s: result = %+v, want %q", input, result, want)
+		}
+	}
+}
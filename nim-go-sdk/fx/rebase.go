@@ -0,0 +1,103 @@
+package fx
+
+import (
+	"context"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/core/money"
+	"github.com/becomeliminal/nim-go-sdk/executor"
+)
+
+// DefaultCurrency is used when the user has no preferred currency.
+const DefaultCurrency = "USD"
+
+// PreferredCurrency returns the user's preferred currency, or
+// DefaultCurrency if prefs is nil or has none.
+func PreferredCurrency(prefs *core.UserPreferences) string {
+	if prefs == nil || strings.TrimSpace(prefs.Currency) == "" {
+		return DefaultCurrency
+	}
+	return strings.ToUpper(strings.TrimSpace(prefs.Currency))
+}
+
+// Converted is an amount expressed in another currency.
+type Converted struct {
+	// Original is the amount as reported.
+	Original money.Amount `json:"original"`
+
+	// Amount is Original in the target currency.
+	Amount money.Amount `json:"amount"`
+
+	// Rate is the rate used, with when it was quoted.
+	Rate Rate `json:"rate"`
+}
+
+// BalanceSummary is a wallet expressed in one currency.
+type BalanceSummary struct {
+	Currency string       `json:"currency"`
+	Balances []Converted  `json:"balances"`
+	Total    money.Amount `json:"total"`
+}
+
+// ConvertedTransaction is a transaction with its amount in another currency.
+type ConvertedTransaction struct {
+	executor.Transaction
+	Converted Converted `json:"converted"`
+}
+
+// converter converts amounts into one currency, asking the source for each
+// rate only once.
+type converter struct {
+	src      RateSource
+	currency string
+	rates    map[string]Rate
+}
+
+func newConverter(src RateSource, currency string) *converter {
+	return &converter{src: src, currency: strings.ToUpper(currency), rates: make(map[string]Rate)}
+}
+
+func (c *converter) convert(ctx context.Context, amount money.Amount) (Converted, error) {
+	rate, ok := c.rates[amount.Currency]
+	if !ok {
+		var err error
+		if _, rate, err = Convert(ctx, c.src, money.Zero(amount.Currency), c.currency); err != nil {
+			return Converted{}, err
+		}
+		c.rates[amount.Currency] = rate
+	}
+	value := money.New(amount.Value, c.currency).Mul(rate.Rate, money.RoundHalfEven)
+	return Converted{Original: amount, Amount: value, Rate: rate}, nil
+}
+
+// RebaseBalances converts wallet balances into currency and totals them,
+// e.g. into PreferredCurrency(ctx.Preferences).
+func RebaseBalances(ctx context.Context, src RateSource, balances []executor.WalletBalance, currency string) (*BalanceSummary, error) {
+	c := newConverter(src, currency)
+	summary := &BalanceSummary{Currency: c.currency, Balances: []Converted{}, Total: money.Zero(c.currency).Round(money.RoundHalfEven)}
+	for _, b := range balances {
+		converted, err := c.convert(ctx, b.Money())
+		if err != nil {
+			return nil, err
+		}
+		summary.Balances = append(summary.Balances, converted)
+		summary.Total, _ = summary.Total.Add(converted.Amount)
+	}
+	return summary, nil
+}
+
+// RebaseTransactions converts transaction amounts into currency at the
+// source's current rates, not the rates on the transaction dates.
+func RebaseTransactions(ctx context.Context, src RateSource, txs []executor.Transaction, currency string) ([]ConvertedTransaction, error) {
+	c := newConverter(src, currency)
+	out := make([]ConvertedTransaction, 0, len(txs))
+	for _, tx := range txs {
+		converted, err := c.convert(ctx, tx.Money())
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, ConvertedTransaction{Transaction: tx, Converted: converted})
+	}
+	return out, nil
+}
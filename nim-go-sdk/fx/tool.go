@@ -0,0 +1,104 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/core/money"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ConvertToolName is the name of the currency conversion tool.
+const ConvertToolName = "convert_currency"
+
+// ConvertTool converts amounts between currencies at a RateSource's rates.
+type ConvertTool struct {
+	source RateSource
+	now    func() time.Time
+}
+
+// Verify ConvertTool implements core.Tool.
+var _ core.Tool = (*ConvertTool)(nil)
+
+// NewConvertTool creates a convert_currency tool backed by source.
+func NewConvertTool(source RateSource) *ConvertTool {
+	return &ConvertTool{source: source, now: time.Now}
+}
+
+// Name returns the tool's name.
+func (t *ConvertTool) Name() string {
+	return ConvertToolName
+}
+
+// Description returns the tool's description. It lists the supported
+// currencies if the source reports them.
+func (t *ConvertTool) Description() string {
+	desc := "Convert an amount between currencies at the current exchange rate. " +
+		"The result says when the rate was quoted (as_of, rate_age); mention it when you report a converted amount."
+	if s, ok := t.source.(interface{ Currencies() []string }); ok {
+		desc += " Supported currencies: " + strings.Join(s.Currencies(), ", ") + "."
+	}
+	return desc
+}
+
+// Schema returns the tool's input schema.
+func (t *ConvertTool) Schema() map[string]interface{} {
+	return tools.ObjectSchema(map[string]interface{}{
+		"amount": tools.DecimalProperty("Amount to convert (e.g., '125.50')"),
+		"from":   tools.StringProperty("Currency of the amount (e.g., 'EUR')"),
+		"to":     tools.StringProperty("Currency to convert into (e.g., 'USD')"),
+	}, "amount", "from", "to")
+}
+
+// RequiresConfirmation returns false - conversion has no side effects.
+func (t *ConvertTool) RequiresConfirmation() bool {
+	return false
+}
+
+// Execute converts the amount.
+func (t *ConvertTool) Execute(ctx context.Context, params *core.ToolParams) (*core.ToolResult, error) {
+	var input struct {
+		Amount *money.Decimal `json:"amount"`
+		From   string         `json:"from"`
+		To     string         `json:"to"`
+	}
+	if err := json.Unmarshal(params.Input, &input); err != nil {
+		return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+	}
+	if input.Amount == nil || strings.TrimSpace(input.From) == "" || strings.TrimSpace(input.To) == "" {
+		return &core.ToolResult{Success: false, Error: "invalid input: amount, from and to are required"}, nil
+	}
+
+	amount := money.New(*input.Amount, strings.TrimSpace(input.From))
+	converted, rate, err := Convert(ctx, t.source, amount, strings.TrimSpace(input.To))
+	if errors.Is(err, ErrRateNotFound) {
+		return &core.ToolResult{Success: false, Error: fmt.Sprintf("no exchange rate from %s to %s", amount.Currency, strings.ToUpper(input.To))}, nil
+	}
+	if err != nil {
+		return &core.ToolResult{Success: false, Error: fmt.Sprintf("failed to get exchange rate: %v", err)}, nil
+	}
+
+	data := map[string]interface{}{
+		"from": amount,
+		"to":   converted,
+		"rate": rate.Rate,
+	}
+	if !rate.AsOf.IsZero() {
+		data["as_of"] = rate.AsOf.UTC().Format(time.RFC3339)
+		data["rate_age"] = t.now().Sub(rate.AsOf).Round(time.Second).String()
+	}
+	if rate.Source != "" {
+		data["source"] = rate.Source
+	}
+	return &core.ToolResult{Success: true, Data: data}, nil
+}
+
+// GetSummary returns a summary (not used, as conversion needs no confirmation).
+func (t *ConvertTool) GetSummary(input json.RawMessage) string {
+	return "Convert currency"
+}
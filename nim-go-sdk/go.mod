@@ -7,6 +7,9 @@ require (
 	github.com/dgraph-io/ristretto v0.1.1
 	github.com/google/uuid v1.6.0
 	github.com/gorilla/websocket v1.5.3
+	github.com/tetratelabs/wazero v1.10.1
+	go.starlark.net v0.0.0-20231121155337-90ade8b19d09
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,483 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/engine"
+)
+
+// defaultCallTimeout bounds requests whose context has no deadline.
+const defaultCallTimeout = 60 * time.Second
+
+// ClientConfig configures a connection to one MCP server. Set Command for a
+// stdio server or URL for a streamable HTTP server.
+type ClientConfig struct {
+	// Name identifies the server in logs and confirmation summaries.
+	Name string
+
+	// Command, Args and Env launch a stdio server. Env entries are
+	// "KEY=value" pairs added to the current environment.
+	Command string
+	Args    []string
+	Env     []string
+
+	// Stderr receives a stdio server's logs. If nil, os.Stderr is used.
+	Stderr io.Writer
+
+	// URL is a streamable HTTP server endpoint, e.g. "https://hr.internal/mcp".
+	URL string
+
+	// Headers are sent with every HTTP request, e.g. Authorization.
+	Headers map[string]string
+
+	// HTTPClient is used for HTTP servers. If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// Transport overrides Command and URL with a custom transport.
+	Transport Transport
+
+	// ToolPrefix is prepended to tool names when registering them, to avoid
+	// collisions between servers (e.g. "hr_").
+	ToolPrefix string
+
+	// Tools limits which server tools are registered. If empty, all are.
+	Tools []string
+
+	// RequireConfirmation lists server tool names (without ToolPrefix) that
+	// need user confirmation before running. Use "*" for all tools.
+	RequireConfirmation []string
+
+	// CallTimeout bounds calls whose context has no deadline. Defaults to 60s.
+	CallTimeout time.Duration
+}
+
+// Client is a connection to an MCP server.
+type Client struct {
+	config    ClientConfig
+	transport Transport
+
+	nextID  atomic.Int64
+	mu      sync.Mutex
+	pending map[string]chan *message
+	closed  bool
+	done    chan struct{} // closed by Close to fail in-flight calls
+
+	serverInfo   Implementation
+	instructions string
+
+	syncMu     sync.Mutex
+	registry   *engine.ToolRegistry
+	registered map[string]bool // registered tool names
+}
+
+// Connect starts the transport and performs the MCP initialize handshake.
+func Connect(ctx context.Context, cfg ClientConfig) (*Client, error) {
+	transport := cfg.Transport
+	switch {
+	case transport != nil:
+	case cfg.Command != "":
+		transport = NewStdioTransport(cfg.Command, cfg.Args, cfg.Env, cfg.Stderr)
+	case cfg.URL != "":
+		transport = NewHTTPTransport(cfg.URL, cfg.Headers, cfg.HTTPClient)
+	default:
+		return nil, errors.New("mcp: ClientConfig needs Command, URL or Transport")
+	}
+	if cfg.Name == "" {
+		cfg.Name = cfg.Command + cfg.URL
+	}
+	if cfg.CallTimeout <= 0 {
+		cfg.CallTimeout = defaultCallTimeout
+	}
+
+	c := &Client{
+		config:     cfg,
+		transport:  transport,
+		pending:    make(map[string]chan *message),
+		done:       make(chan struct{}),
+		registered: make(map[string]bool),
+	}
+	if err := transport.Start(ctx, c.handle); err != nil {
+		return nil, err
+	}
+
+	var result initializeResult
+	err := c.call(ctx, methodInitialize, initializeParams{
+		ProtocolVersion: ProtocolVersion,
+		Capabilities:    map[string]interface{}{},
+		ClientInfo:      Implementation{Name: "nim-go-sdk", Version: "1.0.0"},
+	}, &result)
+	if err != nil {
+		transport.Close()
+		return nil, fmt.Errorf("mcp: initialize %s: %w", cfg.Name, err)
+	}
+	c.serverInfo = result.ServerInfo
+	c.instructions = result.Instructions
+
+	if err := c.notify(ctx, notifyInitialized, nil); err != nil {
+		transport.Close()
+		return nil, fmt.Errorf("mcp: initialize %s: %w", cfg.Name, err)
+	}
+	return c, nil
+}
+
+// ServerInfo returns the server's name and version.
+func (c *Client) ServerInfo() Implementation {
+	return c.serverInfo
+}
+
+// Instructions returns the server's usage instructions, if any.
+func (c *Client) Instructions() string {
+	return c.instructions
+}
+
+// ListTools returns all of the server's tools, following pagination.
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	var tools []Tool
+	cursor := ""
+	for {
+		var page listToolsResult
+		if err := c.call(ctx, methodToolsList, listToolsParams{Cursor: cursor}, &page); err != nil {
+			return nil, err
+		}
+		tools = append(tools, page.Tools...)
+		if page.NextCursor == "" {
+			return tools, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// CallTool calls a server tool with JSON arguments.
+func (c *Client) CallTool(ctx context.Context, name string, arguments json.RawMessage) (*CallToolResult, error) {
+	if len(arguments) == 0 || string(arguments) == "null" {
+		arguments = json.RawMessage(`{}`)
+	}
+	var result CallToolResult
+	if err := c.call(ctx, methodToolsCall, callToolParams{Name: name, Arguments: arguments}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Tools lists the server's tools as core.Tools, applying the config's tool
+// filter, prefix and confirmation settings.
+func (c *Client) Tools(ctx context.Context) ([]core.Tool, error) {
+	listed, err := c.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tools []core.Tool
+	for _, def := range listed {
+		if len(c.config.Tools) > 0 && !contains(c.config.Tools, def.Name) {
+			continue
+		}
+		tools = append(tools, &remoteTool{
+			client:               c,
+			def:                  def,
+			name:                 toolName(c.config.ToolPrefix + def.Name),
+			requiresConfirmation: contains(c.config.RequireConfirmation, "*") || contains(c.config.RequireConfirmation, def.Name),
+		})
+	}
+	return tools, nil
+}
+
+// Sync registers the server's tools in registry, and keeps them in sync:
+// when the server reports its tool list changed, new tools are registered
+// and removed ones unregistered. Tools whose names are already registered
+// by anything other than this client are skipped with a logged error.
+func (c *Client) Sync(ctx context.Context, registry *engine.ToolRegistry) error {
+	c.syncMu.Lock()
+	c.registry = registry
+	c.syncMu.Unlock()
+	return c.resync(ctx)
+}
+
+func (c *Client) resync(ctx context.Context) error {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+	if c.registry == nil {
+		return nil
+	}
+
+	tools, err := c.Tools(ctx)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		// Never replace a tool registered by someone else, such as a
+		// built-in that needs confirmation
+		if _, exists := c.registry.Get(tool.Name()); exists && !c.registered[tool.Name()] {
+			log.Printf("MCP %s: not registering %s: a tool with that name is already registered", c.config.Name, tool.Name())
+			continue
+		}
+		c.registry.Register(tool)
+		current[tool.Name()] = true
+	}
+	for name := range c.registered {
+		if !current[name] {
+			c.registry.Unregister(name)
+		}
+	}
+	c.registered = current
+	return nil
+}
+
+// Close ends the session and stops the transport.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	close(c.done)
+	c.mu.Unlock()
+	return c.transport.Close()
+}
+
+// call sends a request and decodes its result into out.
+func (c *Client) call(ctx context.Context, method string, params interface{}, out interface{}) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.CallTimeout)
+		defer cancel()
+	}
+
+	id := strconv.FormatInt(c.nextID.Add(1), 10)
+	msg, err := newMessage(json.RawMessage(id), method, params)
+	if err != nil {
+		return err
+	}
+
+	ch := make(chan *message, 1)
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return errors.New("mcp: client closed")
+	}
+	c.pending[id] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	if err := c.transport.Send(ctx, msg); err != nil {
+		return err
+	}
+
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if out == nil {
+			return nil
+		}
+		if err := json.Unmarshal(resp.Result, out); err != nil {
+			return fmt.Errorf("mcp: invalid %s result: %w", method, err)
+		}
+		return nil
+	case <-c.done:
+		return errors.New("mcp: client closed")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// notify sends a notification.
+func (c *Client) notify(ctx context.Context, method string, params interface{}) error {
+	msg, err := newMessage(nil, method, params)
+	if err != nil {
+		return err
+	}
+	return c.transport.Send(ctx, msg)
+}
+
+// handle routes an incoming message: responses to their callers, server
+// requests to a reply, and notifications to their handlers.
+func (c *Client) handle(data json.RawMessage) {
+	var msg message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		log.Printf("MCP %s: ignoring malformed message: %v", c.config.Name, err)
+		return
+	}
+
+	switch {
+	case msg.isResponse():
+		c.mu.Lock()
+		ch, ok := c.pending[string(msg.ID)]
+		c.mu.Unlock()
+		if ok {
+			// Buffered for one response; drop duplicates rather than block
+			select {
+			case ch <- &msg:
+			default:
+			}
+		}
+
+	case len(msg.ID) > 0:
+		// A request from the server; only ping is supported
+		go c.reply(msg)
+
+	case msg.Method == notifyToolsListChanged:
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), c.config.CallTimeout)
+			defer cancel()
+			if err := c.resync(ctx); err != nil {
+				log.Printf("MCP %s: failed to refresh tools: %v", c.config.Name, err)
+			}
+		}()
+	}
+}
+
+func (c *Client) reply(req message) {
+	resp := message{JSONRPC: jsonrpcVersion, ID: req.ID}
+	if req.Method == methodPing {
+		resp.Result = json.RawMessage(`{}`)
+	} else {
+		resp.Error = &RPCError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+	data, _ := json.Marshal(resp)
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.CallTimeout)
+	defer cancel()
+	if err := c.transport.Send(ctx, data); err != nil {
+		log.Printf("MCP %s: failed to reply to %s: %v", c.config.Name, req.Method, err)
+	}
+}
+
+func newMessage(id json.RawMessage, method string, params interface{}) (json.RawMessage, error) {
+	msg := message{JSONRPC: jsonrpcVersion, ID: id, Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		msg.Params = data
+	}
+	return json.Marshal(msg)
+}
+
+// remoteTool is a core.Tool that proxies calls to an MCP server.
+type remoteTool struct {
+	client               *Client
+	def                  Tool
+	name                 string
+	requiresConfirmation bool
+}
+
+// Verify remoteTool implements core.Tool.
+var _ core.Tool = (*remoteTool)(nil)
+
+func (t *remoteTool) Name() string {
+	return t.name
+}
+
+func (t *remoteTool) Description() string {
+	if t.def.Description == "" {
+		return t.def.Title
+	}
+	return t.def.Description
+}
+
+func (t *remoteTool) Schema() map[string]interface{} {
+	if t.def.InputSchema == nil {
+		return map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+	return t.def.InputSchema
+}
+
+func (t *remoteTool) RequiresConfirmation() bool {
+	return t.requiresConfirmation
+}
+
+func (t *remoteTool) GetSummary(input json.RawMessage) string {
+	title := t.def.Title
+	if title == "" {
+		title = t.def.Name
+	}
+	return fmt.Sprintf("%s on %s with %s", title, t.client.config.Name, compact(input))
+}
+
+// Execute calls the tool on the server. Text content that is valid JSON is
+// returned as parsed data; structured content is preferred when present.
+func (t *remoteTool) Execute(ctx context.Context, params *core.ToolParams) (*core.ToolResult, error) {
+	result, err := t.client.CallTool(ctx, t.def.Name, params.Input)
+	if err != nil {
+		return &core.ToolResult{Success: false, Error: err.Error()}, nil
+	}
+
+	text := contentText(result.Content)
+	if result.IsError {
+		if text == "" {
+			text = "tool reported an error"
+		}
+		return &core.ToolResult{Success: false, Error: text}, nil
+	}
+
+	var data interface{} = text
+	if result.StructuredContent != nil {
+		data = result.StructuredContent
+	} else if len(result.Content) == 1 && result.Content[0].Type == "text" {
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(text), &parsed); err == nil {
+			data = parsed
+		}
+	}
+	return &core.ToolResult{Success: true, Data: data}, nil
+}
+
+// contentText joins text content and describes other content types.
+func contentText(content []Content) string {
+	parts := make([]string, 0, len(content))
+	for _, c := range content {
+		switch c.Type {
+		case "text":
+			parts = append(parts, c.Text)
+		case "resource", "resource_link":
+			parts = append(parts, fmt.Sprintf("[%s: %s]", c.Type, c.URI))
+		default:
+			parts = append(parts, fmt.Sprintf("[%s: %s]", c.Type, c.MimeType))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// invalidToolChars matches characters the Claude API rejects in tool names.
+var invalidToolChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// toolName makes an MCP tool name valid for the Claude API.
+func toolName(name string) string {
+	name = invalidToolChars.ReplaceAllString(name, "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
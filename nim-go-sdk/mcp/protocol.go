@@ -0,0 +1,157 @@
+// Package mcp connects agents to the Model Context Protocol.
+//
+// A Client imports tools from an MCP server, over stdio or streamable HTTP,
+// and registers them in an engine.ToolRegistry as core.Tools:
+//
+//	client, err := mcp.Connect(ctx, mcp.ClientConfig{
+//		Name:                "hr",
+//		Command:             "hr-mcp-server",
+//		RequireConfirmation: []string{"update_salary"},
+//	})
+//	if err != nil { ... }
+//	defer client.Close()
+//	err = client.Sync(ctx, registry)
+//
+// A Server does the reverse, serving a registry's tools to other agents.
+// Tools that require confirmation store a pending action for approval
+// instead of running:
+//
+//	server, err := mcp.NewServer(mcp.ServerConfig{Registry: registry, UserID: userID})
+//	if err != nil { ... }
+//	err = server.ServeStdio(ctx, os.Stdin, os.Stdout)
+//
+// Messages are JSON-RPC 2.0 as described by the MCP specification.
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ProtocolVersion is the MCP revision this package speaks.
+const ProtocolVersion = "2025-06-18"
+
+// jsonrpcVersion is the JSON-RPC version of every message.
+const jsonrpcVersion = "2.0"
+
+// MCP methods and notifications.
+const (
+	methodInitialize       = "initialize"
+	methodPing             = "ping"
+	methodToolsList        = "tools/list"
+	methodToolsCall        = "tools/call"
+	notifyInitialized      = "notifications/initialized"
+	notifyToolsListChanged = "notifications/tools/list_changed"
+)
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// message is any JSON-RPC message: a request (ID and Method), a
+// notification (Method only) or a response (ID and Result or Error).
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+func (m *message) isResponse() bool {
+	return len(m.ID) > 0 && m.Method == ""
+}
+
+// RPCError is a JSON-RPC error returned by the other side.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("mcp error %d: %s", e.Code, e.Message)
+}
+
+// Implementation identifies a client or server.
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type initializeParams struct {
+	ProtocolVersion string                 `json:"protocolVersion"`
+	Capabilities    map[string]interface{} `json:"capabilities"`
+	ClientInfo      Implementation         `json:"clientInfo"`
+}
+
+type initializeResult struct {
+	ProtocolVersion string             `json:"protocolVersion"`
+	Capabilities    serverCapabilities `json:"capabilities"`
+	ServerInfo      Implementation     `json:"serverInfo"`
+	Instructions    string             `json:"instructions,omitempty"`
+}
+
+type serverCapabilities struct {
+	Tools *toolsCapability `json:"tools,omitempty"`
+}
+
+type toolsCapability struct {
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// Tool is a tool definition as listed by an MCP server.
+type Tool struct {
+	Name        string                 `json:"name"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+	Annotations *ToolAnnotations       `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations are hints about a tool's behaviour.
+type ToolAnnotations struct {
+	ReadOnlyHint    *bool `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool `json:"destructiveHint,omitempty"`
+}
+
+type listToolsParams struct {
+	Cursor string `json:"cursor,omitempty"`
+}
+
+type listToolsResult struct {
+	Tools      []Tool `json:"tools"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+type callToolParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// CallToolResult is the result of a tools/call request.
+type CallToolResult struct {
+	Content           []Content   `json:"content"`
+	StructuredContent interface{} `json:"structuredContent,omitempty"`
+	IsError           bool        `json:"isError,omitempty"`
+}
+
+// Content is a block of tool output.
+type Content struct {
+	Type     string `json:"type"` // "text", "image", "audio", "resource" or "resource_link"
+	Text     string `json:"text,omitempty"`
+	Data     string `json:"data,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+	URI      string `json:"uri,omitempty"`
+}
+
+// TextContent returns a text content block.
+func TextContent(text string) Content {
+	return Content{Type: "text", Text: text}
+}
@@ -0,0 +1,407 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/engine"
+	"github.com/becomeliminal/nim-go-sdk/store"
+)
+
+// defaultConfirmationTTL matches the engine's pending action lifetime.
+const defaultConfirmationTTL = 10 * time.Minute
+
+// maxRequestBytes bounds a single HTTP request body.
+const maxRequestBytes = 4 << 20
+
+// ServerConfig configures an MCP server.
+type ServerConfig struct {
+	// Name and Version identify the server to clients. Name defaults to "nim".
+	Name    string
+	Version string
+
+	// Instructions tell clients how to use the tools. Optional.
+	Instructions string
+
+	// Registry holds the tools to serve. Required.
+	Registry *engine.ToolRegistry
+
+	// Confirmations stores actions awaiting approval. If nil, an in-memory
+	// store is used.
+	Confirmations store.Confirmations
+
+	// ConfirmationTTL is how long a pending action can be approved.
+	// Defaults to 10 minutes.
+	ConfirmationTTL time.Duration
+
+	// UserID is the user tools run as over stdio.
+	UserID string
+
+	// Authenticate resolves the user for an HTTP request, e.g. from a
+	// bearer token. A non-nil error rejects the request with 401. Required
+	// for Handler; without it every HTTP request is refused.
+	Authenticate func(r *http.Request) (string, error)
+}
+
+// Server serves a tool registry over MCP. Tools that require confirmation
+// are not run when called; instead a pending action is stored and returned,
+// to be approved out of band with Confirm or rejected with Cancel.
+type Server struct {
+	config ServerConfig
+
+	mu       sync.Mutex
+	sessions map[string]string // HTTP session ID -> user ID
+}
+
+// NewServer creates an MCP server for cfg.Registry.
+func NewServer(cfg ServerConfig) (*Server, error) {
+	if cfg.Registry == nil {
+		return nil, errors.New("mcp: ServerConfig.Registry is required")
+	}
+	if cfg.Name == "" {
+		cfg.Name = "nim"
+	}
+	if cfg.Version == "" {
+		cfg.Version = "1.0.0"
+	}
+	if cfg.Confirmations == nil {
+		cfg.Confirmations = store.NewMemoryConfirmations()
+	}
+	if cfg.ConfirmationTTL <= 0 {
+		cfg.ConfirmationTTL = defaultConfirmationTTL
+	}
+	return &Server{config: cfg, sessions: make(map[string]string)}, nil
+}
+
+// ServeStdio serves newline-delimited messages from r, writing replies to w,
+// until r ends or ctx is cancelled. Requests are handled concurrently.
+func (s *Server) ServeStdio(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var writeMu sync.Mutex
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	lines := make(chan []byte)
+	scanErr := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), maxRequestBytes)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		scanErr <- scanner.Err()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-scanErr:
+			return err
+		case line := <-lines:
+			if len(line) == 0 {
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp := s.handleMessage(ctx, s.config.UserID, line)
+				if resp == nil {
+					return
+				}
+				writeMu.Lock()
+				defer writeMu.Unlock()
+				if _, err := w.Write(append(resp, '\n')); err != nil {
+					log.Printf("MCP: failed to write response: %v", err)
+				}
+			}()
+		}
+	}
+}
+
+// Handler returns an http.Handler speaking the streamable HTTP transport.
+// Replies are plain JSON; the server offers no GET notification stream.
+// Requests are refused unless ServerConfig.Authenticate is set.
+func (s *Server) Handler() http.Handler {
+	if s.config.Authenticate == nil {
+		log.Printf("MCP: ServerConfig.Authenticate is not set; HTTP requests will be refused")
+	}
+	return http.HandlerFunc(s.serveHTTP)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	// Never fall back to UserID: anyone who can reach the handler would
+	// act as that user
+	if s.config.Authenticate == nil {
+		http.Error(w, "authentication not configured", http.StatusInternalServerError)
+		return
+	}
+	userID, err := s.config.Authenticate(r)
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	sessionID := r.Header.Get("Mcp-Session-Id")
+	switch r.Method {
+	case http.MethodPost:
+	case http.MethodDelete:
+		s.mu.Lock()
+		delete(s.sessions, sessionID)
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		return
+	default:
+		w.Header().Set("Allow", "POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBytes))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+
+	var msg message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse(nil, codeParseError, "parse error"))
+		return
+	}
+
+	if msg.Method == methodInitialize {
+		sessionID = uuid.New().String()
+		s.mu.Lock()
+		s.sessions[sessionID] = userID
+		s.mu.Unlock()
+		w.Header().Set("Mcp-Session-Id", sessionID)
+	} else if sessionID != "" {
+		s.mu.Lock()
+		owner, ok := s.sessions[sessionID]
+		s.mu.Unlock()
+		if !ok || owner != userID {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	resp := s.handleMessage(r.Context(), userID, body)
+	if resp == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
+
+// handleMessage handles one JSON-RPC message and returns the reply, or nil
+// for notifications and responses.
+func (s *Server) handleMessage(ctx context.Context, userID string, data []byte) []byte {
+	var msg message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return errorResponse(nil, codeParseError, "parse error")
+	}
+	if len(msg.ID) == 0 || msg.Method == "" {
+		// Notifications need no reply, and the server sends no requests
+		return nil
+	}
+
+	result, rpcErr := s.dispatch(ctx, userID, &msg)
+	if rpcErr != nil {
+		return errorResponse(msg.ID, rpcErr.Code, rpcErr.Message)
+	}
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return errorResponse(msg.ID, codeInternalError, err.Error())
+	}
+	resp, _ := json.Marshal(message{JSONRPC: jsonrpcVersion, ID: msg.ID, Result: raw})
+	return resp
+}
+
+func (s *Server) dispatch(ctx context.Context, userID string, msg *message) (interface{}, *RPCError) {
+	switch msg.Method {
+	case methodInitialize:
+		return map[string]interface{}{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      Implementation{Name: s.config.Name, Version: s.config.Version},
+			"instructions":    s.config.Instructions,
+		}, nil
+
+	case methodPing:
+		return struct{}{}, nil
+
+	case methodToolsList:
+		return listToolsResult{Tools: s.listTools()}, nil
+
+	case methodToolsCall:
+		var params callToolParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil || params.Name == "" {
+			return nil, &RPCError{Code: codeInvalidParams, Message: "invalid tools/call params"}
+		}
+		tool, ok := s.config.Registry.Get(params.Name)
+		if !ok {
+			return nil, &RPCError{Code: codeInvalidParams, Message: "unknown tool: " + params.Name}
+		}
+		if len(params.Arguments) == 0 || string(params.Arguments) == "null" {
+			params.Arguments = json.RawMessage(`{}`)
+		}
+		if tool.RequiresConfirmation() {
+			return s.requestConfirmation(ctx, userID, tool, params.Arguments), nil
+		}
+		result, err := tool.Execute(ctx, &core.ToolParams{
+			UserID:    userID,
+			Input:     params.Arguments,
+			RequestID: string(msg.ID),
+		})
+		return toCallToolResult(result, err), nil
+
+	default:
+		return nil, &RPCError{Code: codeMethodNotFound, Message: "method not found: " + msg.Method}
+	}
+}
+
+// listTools describes the registry's tools, sorted by name.
+func (s *Server) listTools() []Tool {
+	names := s.config.Registry.List()
+	sort.Strings(names)
+
+	tools := make([]Tool, 0, len(names))
+	for _, name := range names {
+		tool, ok := s.config.Registry.Get(name)
+		if !ok {
+			continue
+		}
+		schema := tool.Schema()
+		if schema == nil {
+			schema = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+		}
+		tools = append(tools, Tool{Name: name, Description: tool.Description(), InputSchema: schema})
+	}
+	return tools
+}
+
+// requestConfirmation stores a pending action for tool, reusing an
+// identical one still awaiting approval, and describes it to the caller.
+func (s *Server) requestConfirmation(ctx context.Context, userID string, tool core.Tool, input json.RawMessage) *CallToolResult {
+	key := engine.GenerateIdempotencyKey(userID, tool.Name(), input)
+	action, err := s.config.Confirmations.GetByIdempotency(ctx, userID, key)
+	if err != nil {
+		return toCallToolResult(nil, err)
+	}
+	if action == nil {
+		now := time.Now()
+		action = &core.PendingAction{
+			ID:             uuid.New().String(),
+			IdempotencyKey: key,
+			SessionID:      "mcp",
+			UserID:         userID,
+			Tool:           tool.Name(),
+			Input:          input,
+			Summary:        tool.GetSummary(input),
+			CreatedAt:      now.Unix(),
+			ExpiresAt:      now.Add(s.config.ConfirmationTTL).Unix(),
+		}
+		if err := s.config.Confirmations.Store(ctx, action); err != nil {
+			return toCallToolResult(nil, fmt.Errorf("failed to store pending action: %w", err))
+		}
+	}
+
+	expires := time.Unix(action.ExpiresAt, 0).UTC().Format(time.RFC3339)
+	return &CallToolResult{
+		Content: []Content{TextContent(fmt.Sprintf(
+			"Awaiting user approval: %s (action %s, expires %s). The action has not been performed.",
+			action.Summary, action.ID, expires))},
+		StructuredContent: map[string]interface{}{
+			"status":     "pending_approval",
+			"action_id":  action.ID,
+			"summary":    action.Summary,
+			"expires_at": expires,
+		},
+	}
+}
+
+// Confirm approves a pending action and runs its tool. Failed tool runs are
+// reported in the result, as with engine.Engine.ExecuteTool.
+func (s *Server) Confirm(ctx context.Context, userID, actionID string) (*core.ToolResult, error) {
+	action, err := s.config.Confirmations.Confirm(ctx, userID, actionID)
+	if err != nil {
+		return nil, err
+	}
+	tool, ok := s.config.Registry.Get(action.Tool)
+	if !ok {
+		return nil, fmt.Errorf("unknown tool: %s", action.Tool)
+	}
+	return tool.Execute(ctx, &core.ToolParams{
+		UserID:         userID,
+		Input:          action.Input,
+		RequestID:      action.ID,
+		ConfirmationID: action.ID,
+		IdempotencyKey: action.IdempotencyKey,
+	})
+}
+
+// Cancel rejects a pending action.
+func (s *Server) Cancel(ctx context.Context, userID, actionID string) error {
+	return s.config.Confirmations.Cancel(ctx, userID, actionID)
+}
+
+// toCallToolResult converts a tool's outcome to MCP content. Object data
+// is also returned as structured content.
+func toCallToolResult(result *core.ToolResult, err error) *CallToolResult {
+	switch {
+	case err != nil:
+		return &CallToolResult{Content: []Content{TextContent(err.Error())}, IsError: true}
+	case result == nil:
+		return &CallToolResult{Content: []Content{TextContent("tool returned no result")}, IsError: true}
+	case !result.Success:
+		return &CallToolResult{Content: []Content{TextContent(result.Error)}, IsError: true}
+	}
+
+	if text, ok := result.Data.(string); ok {
+		return &CallToolResult{Content: []Content{TextContent(text)}}
+	}
+	data, err := json.Marshal(result.Data)
+	if err != nil {
+		return &CallToolResult{Content: []Content{TextContent(err.Error())}, IsError: true}
+	}
+	out := &CallToolResult{Content: []Content{TextContent(string(data))}}
+	var object map[string]interface{}
+	if json.Unmarshal(data, &object) == nil && object != nil {
+		out.StructuredContent = object
+	}
+	return out
+}
+
+func errorResponse(id json.RawMessage, code int, msg string) []byte {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	resp, _ := json.Marshal(message{JSONRPC: jsonrpcVersion, ID: id, Error: &RPCError{Code: code, Message: msg}})
+	return resp
+}
+
+func writeJSON(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
@@ -0,0 +1,213 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/engine"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+func newTestRegistry(sent *[]string) *engine.ToolRegistry {
+	registry := engine.NewToolRegistry()
+	registry.Register(tools.New("get_balance").
+		Description("Get the wallet balance").
+		Schema(tools.ObjectSchema(map[string]interface{}{})).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			return map[string]interface{}{"balance": "42.00", "currency": "USD"}, nil
+		}).
+		Build())
+	registry.Register(tools.New("send_money").
+		Description("Send money to a user").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"recipient": tools.StringProperty("Recipient"),
+			"amount":    tools.StringProperty("Amount"),
+		}, "recipient", "amount")).
+		RequiresConfirmation().
+		SummaryTemplate("Send money").
+		Handler(func(ctx context.Context, params *core.ToolParams) (*core.ToolResult, error) {
+			if params.ConfirmationID == "" || params.IdempotencyKey == "" {
+				return nil, errors.New("executed without confirmation")
+			}
+			*sent = append(*sent, params.UserID+":"+string(params.Input))
+			return &core.ToolResult{Success: true, Data: "sent"}, nil
+		}).
+		Build())
+	return registry
+}
+
+func TestServerHTTP(t *testing.T) {
+	var sent []string
+	server, err := NewServer(ServerConfig{
+		Registry: newTestRegistry(&sent),
+		Authenticate: func(r *http.Request) (string, error) {
+			user := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if user == "" {
+				return "", errors.New("missing token")
+			}
+			return user, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	resp, err := http.Post(httpServer.URL, "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unauthenticated status = %d, want 401", resp.StatusCode)
+	}
+
+	ctx := context.Background()
+	client, err := Connect(ctx, ClientConfig{
+		URL:     httpServer.URL,
+		Headers: map[string]string{"Authorization": "Bearer alice"},
+	})
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer client.Close()
+
+	listed, err := client.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	if len(listed) != 2 || listed[0].Name != "get_balance" || listed[1].Name != "send_money" {
+		t.Fatalf("ListTools = %+v", listed)
+	}
+	if required := fmt.Sprint(listed[1].InputSchema["required"]); required != "[recipient amount]" {
+		t.Errorf("send_money required = %s", required)
+	}
+
+	result, err := client.CallTool(ctx, "get_balance", nil)
+	if err != nil || result.IsError {
+		t.Fatalf("get_balance = %+v, %v", result, err)
+	}
+	if data, ok := result.StructuredContent.(map[string]interface{}); !ok || data["balance"] != "42.00" {
+		t.Errorf("structured content = %#v", result.StructuredContent)
+	}
+
+	args := json.RawMessage(`{"recipient":"@bob","amount":"10"}`)
+	pending, err := client.CallTool(ctx, "send_money", args)
+	if err != nil || pending.IsError {
+		t.Fatalf("send_money = %+v, %v", pending, err)
+	}
+	if len(sent) != 0 {
+		t.Fatalf("send_money ran before approval: %v", sent)
+	}
+	details := pending.StructuredContent.(map[string]interface{})
+	if details["status"] != "pending_approval" || details["summary"] != "Send money" {
+		t.Errorf("pending result = %v", details)
+	}
+	actionID := details["action_id"].(string)
+
+	// Repeating the call returns the same pending action
+	again, _ := client.CallTool(ctx, "send_money", args)
+	if id := again.StructuredContent.(map[string]interface{})["action_id"]; id != actionID {
+		t.Errorf("repeated call created action %v, want %s", id, actionID)
+	}
+
+	if _, err := server.Confirm(ctx, "mallory", actionID); err == nil {
+		t.Error("another user confirmed the action")
+	}
+	confirmed, err := server.Confirm(ctx, "alice", actionID)
+	if err != nil || !confirmed.Success {
+		t.Fatalf("Confirm = %+v, %v", confirmed, err)
+	}
+	if len(sent) != 1 || sent[0] != `alice:{"recipient":"@bob","amount":"10"}` {
+		t.Errorf("sent = %v", sent)
+	}
+	if _, err := server.Confirm(ctx, "alice", actionID); err == nil {
+		t.Error("action confirmed twice")
+	}
+
+	if _, err := client.CallTool(ctx, "missing", nil); err == nil {
+		t.Error("expected an error for an unknown tool")
+	}
+}
+
+func TestServerHTTPRequiresAuthenticate(t *testing.T) {
+	var sent []string
+	server, err := NewServer(ServerConfig{Registry: newTestRegistry(&sent), UserID: "local"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"send_money","arguments":{}}}`
+	resp, err := http.Post(httpServer.URL, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError || len(sent) != 0 {
+		t.Errorf("expected the request to be refused, got %d with %v", resp.StatusCode, sent)
+	}
+}
+
+func TestServerStdio(t *testing.T) {
+	var sent []string
+	server, err := NewServer(ServerConfig{Registry: newTestRegistry(&sent), UserID: "local"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- server.ServeStdio(context.Background(), inR, outW)
+		outW.Close()
+	}()
+	replies := bufio.NewScanner(outR)
+
+	roundTrip := func(request string) map[string]interface{} {
+		t.Helper()
+		fmt.Fprintln(inW, request)
+		if !replies.Scan() {
+			t.Fatalf("no reply to %s", request)
+		}
+		var reply map[string]interface{}
+		if err := json.Unmarshal(replies.Bytes(), &reply); err != nil {
+			t.Fatalf("invalid reply %s: %v", replies.Text(), err)
+		}
+		return reply
+	}
+
+	reply := roundTrip(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`)
+	if info := reply["result"].(map[string]interface{})["serverInfo"].(map[string]interface{}); info["name"] != "nim" {
+		t.Errorf("serverInfo = %v", info)
+	}
+	fmt.Fprintln(inW, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+
+	reply = roundTrip(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"send_money","arguments":{"recipient":"@bob","amount":"5"}}}`)
+	result := reply["result"].(map[string]interface{})
+	if result["structuredContent"].(map[string]interface{})["status"] != "pending_approval" || len(sent) != 0 {
+		t.Errorf("send_money over stdio = %v, sent %v", result, sent)
+	}
+
+	reply = roundTrip(`{"jsonrpc":"2.0","id":3,"method":"resources/list"}`)
+	if code := reply["error"].(map[string]interface{})["code"]; code != float64(codeMethodNotFound) {
+		t.Errorf("unknown method error code = %v", code)
+	}
+
+	inW.Close()
+	if err := <-done; err != nil {
+		t.Errorf("ServeStdio = %v", err)
+	}
+}
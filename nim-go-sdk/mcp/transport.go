@@ -0,0 +1,341 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Transport carries JSON-RPC messages between a Client and an MCP server.
+type Transport interface {
+	// Start begins delivering incoming messages to handle. handle may be
+	// called from several goroutines.
+	Start(ctx context.Context, handle func(msg json.RawMessage)) error
+
+	// Send delivers one message to the server.
+	Send(ctx context.Context, msg json.RawMessage) error
+
+	// Close shuts the transport down.
+	Close() error
+}
+
+// StdioTransport runs an MCP server as a subprocess and exchanges
+// newline-delimited JSON messages over its stdin and stdout.
+type StdioTransport struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+
+	mu     sync.Mutex // serialises writes
+	done   chan struct{}
+	closed sync.Once
+}
+
+// NewStdioTransport prepares a subprocess transport. env is added to the
+// current environment; the server's stderr is passed through to stderr,
+// or to os.Stderr if stderr is nil.
+func NewStdioTransport(command string, args []string, env []string, stderr io.Writer) *StdioTransport {
+	cmd := exec.Command(command, args...)
+	cmd.Env = append(os.Environ(), env...)
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+	cmd.Stderr = stderr
+	return &StdioTransport{cmd: cmd, done: make(chan struct{})}
+}
+
+// Start launches the subprocess and reads its stdout.
+func (t *StdioTransport) Start(ctx context.Context, handle func(msg json.RawMessage)) error {
+	var err error
+	if t.stdin, err = t.cmd.StdinPipe(); err != nil {
+		return err
+	}
+	if t.stdout, err = t.cmd.StdoutPipe(); err != nil {
+		return err
+	}
+	if err := t.cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start MCP server %s: %w", t.cmd.Path, err)
+	}
+
+	go func() {
+		defer close(t.done)
+		reader := bufio.NewReader(t.stdout)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				handle(line)
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Send writes one message line to the subprocess.
+func (t *StdioTransport) Send(ctx context.Context, msg json.RawMessage) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.done:
+		return errors.New("MCP server exited")
+	default:
+	}
+	_, err := t.stdin.Write(append(compact(msg), '\n'))
+	return err
+}
+
+// Close closes the subprocess's stdin and waits briefly for it to exit
+// before killing it.
+func (t *StdioTransport) Close() error {
+	t.closed.Do(func() {
+		if t.stdin != nil {
+			t.stdin.Close()
+		}
+		if t.cmd.Process == nil {
+			return
+		}
+		exited := make(chan struct{})
+		go func() {
+			t.cmd.Wait()
+			close(exited)
+		}()
+		select {
+		case <-exited:
+		case <-time.After(2 * time.Second):
+			t.cmd.Process.Kill()
+			<-exited
+		}
+	})
+	return nil
+}
+
+// HTTPTransport speaks the MCP streamable HTTP transport: each message is
+// POSTed to a single endpoint, which answers with JSON or an SSE stream.
+// Server-initiated notifications are read from a GET stream when the server
+// offers one.
+type HTTPTransport struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+
+	mu        sync.Mutex
+	sessionID string
+	handle    func(msg json.RawMessage)
+	ctx       context.Context
+	cancel    context.CancelFunc
+	listening bool
+}
+
+// NewHTTPTransport creates a streamable HTTP transport. headers are sent
+// with every request (e.g. Authorization). If client is nil,
+// http.DefaultClient is used.
+func NewHTTPTransport(url string, headers map[string]string, client *http.Client) *HTTPTransport {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPTransport{url: url, headers: headers, client: client}
+}
+
+// Start records the message handler.
+func (t *HTTPTransport) Start(ctx context.Context, handle func(msg json.RawMessage)) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handle = handle
+	t.ctx, t.cancel = context.WithCancel(context.Background())
+	return nil
+}
+
+// Send POSTs a message and delivers any messages in the reply.
+func (t *HTTPTransport) Send(ctx context.Context, msg json.RawMessage) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(msg))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	t.setHeaders(req)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if id := resp.Header.Get("Mcp-Session-Id"); id != "" {
+		t.mu.Lock()
+		first := t.sessionID == ""
+		t.sessionID = id
+		t.mu.Unlock()
+		if first {
+			t.listen()
+		}
+	}
+
+	if resp.StatusCode == http.StatusAccepted {
+		return nil
+	}
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("MCP server returned HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return readSSE(resp.Body, t.deliver)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	t.deliver(body)
+	return nil
+}
+
+// listen opens the optional GET stream for server-initiated messages.
+func (t *HTTPTransport) listen() {
+	t.mu.Lock()
+	if t.listening || t.ctx == nil {
+		t.mu.Unlock()
+		return
+	}
+	t.listening = true
+	ctx := t.ctx
+	t.mu.Unlock()
+
+	go func() {
+		for ctx.Err() == nil {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.url, nil)
+			if err != nil {
+				return
+			}
+			req.Header.Set("Accept", "text/event-stream")
+			t.setHeaders(req)
+
+			resp, err := t.client.Do(req)
+			if err == nil {
+				ok := resp.StatusCode == http.StatusOK
+				if ok {
+					readSSE(resp.Body, t.deliver)
+				}
+				resp.Body.Close()
+				if !ok {
+					// The server doesn't offer a stream
+					return
+				}
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}()
+}
+
+func (t *HTTPTransport) setHeaders(req *http.Request) {
+	req.Header.Set("MCP-Protocol-Version", ProtocolVersion)
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	t.mu.Lock()
+	if t.sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", t.sessionID)
+	}
+	t.mu.Unlock()
+}
+
+// deliver passes a message, or each message of a batch, to the handler.
+func (t *HTTPTransport) deliver(data []byte) {
+	data = append([]byte(nil), bytes.TrimSpace(data)...)
+	if len(data) == 0 {
+		return
+	}
+	t.mu.Lock()
+	handle := t.handle
+	t.mu.Unlock()
+	if handle == nil {
+		return
+	}
+	if data[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(data, &batch); err == nil {
+			for _, msg := range batch {
+				handle(msg)
+			}
+			return
+		}
+	}
+	handle(data)
+}
+
+// Close stops the notification stream and ends the session.
+func (t *HTTPTransport) Close() error {
+	t.mu.Lock()
+	if t.cancel != nil {
+		t.cancel()
+	}
+	sessionID := t.sessionID
+	t.mu.Unlock()
+
+	if sessionID == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, t.url, nil)
+	if err != nil {
+		return err
+	}
+	t.setHeaders(req)
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// readSSE calls fn with the data of each server-sent event until r ends.
+func readSSE(r io.Reader, fn func(data []byte)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var data bytes.Buffer
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data.Len() > 0 {
+				fn(data.Bytes())
+				data.Reset()
+			}
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if data.Len() > 0 {
+		fn(data.Bytes())
+	}
+	return scanner.Err()
+}
+
+// compact removes insignificant whitespace so a message fits on one line.
+func compact(msg json.RawMessage) []byte {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, msg); err != nil {
+		return msg
+	}
+	return buf.Bytes()
+}
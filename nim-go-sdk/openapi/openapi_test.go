@@ -0,0 +1,231 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+const gatewaySpec = `
+openapi: 3.0.3
+info:
+  title: Gateway
+  version: "1"
+servers:
+  - url: https://gateway.example.com/nim/v1
+security:
+  - bearerAuth: []
+paths:
+  /wallets/{walletId}/balance:
+    parameters:
+      - $ref: '#/components/parameters/WalletId'
+    get:
+      operationId: getWalletBalance
+      summary: Get a wallet's balance
+      parameters:
+        - name: currency
+          in: query
+          schema: {type: string, enum: [USD, EUR]}
+      responses:
+        "200": {description: OK}
+  /payments:
+    post:
+      operationId: sendMoney
+      summary: Send money
+      x-requires-confirmation: true
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Payment'}
+      responses:
+        200: {description: OK}
+  /notes:
+    put:
+      operationId: replace-notes
+      security:
+        - apiKey: []
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: array
+              items: {type: string}
+      responses:
+        204: {description: No content}
+components:
+  parameters:
+    WalletId:
+      name: walletId
+      in: path
+      required: true
+      description: The wallet ID
+      schema: {type: string}
+  schemas:
+    Payment:
+      type: object
+      required: [recipient, amount, id]
+      properties:
+        id: {type: string, readOnly: true}
+        recipient: {type: string}
+        amount: {$ref: '#/components/schemas/Amount'}
+        note: {type: string, nullable: true}
+    Amount:
+      type: string
+      pattern: '^\d+(\.\d{1,2})?$'
+  securitySchemes:
+    bearerAuth: {type: http, scheme: bearer}
+    apiKey: {type: apiKey, in: header, name: X-API-Key}
+`
+
+func loadTestSpec(t *testing.T) *Spec {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "gateway.yaml")
+	if err := os.WriteFile(path, []byte(gatewaySpec), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+	return spec
+}
+
+func TestToolsSchemas(t *testing.T) {
+	spec := loadTestSpec(t)
+	if spec.Title() != "Gateway" || spec.ServerURL() != "https://gateway.example.com/nim/v1" {
+		t.Errorf("Title/ServerURL = %q, %q", spec.Title(), spec.ServerURL())
+	}
+
+	tools, err := spec.Tools(Config{})
+	if err != nil {
+		t.Fatalf("Tools: %v", err)
+	}
+	byName := make(map[string]core.Tool)
+	for _, tool := range tools {
+		byName[tool.Name()] = tool
+	}
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if fmt.Sprint(names) != "[get_wallet_balance replace_notes send_money]" {
+		t.Fatalf("tool names = %v", names)
+	}
+
+	balance := byName["get_wallet_balance"].Schema()
+	props := balance["properties"].(map[string]interface{})
+	if props["walletId"].(map[string]interface{})["description"] != "The wallet ID" {
+		t.Errorf("walletId schema = %v", props["walletId"])
+	}
+	if fmt.Sprint(balance["required"]) != "[walletId]" {
+		t.Errorf("get_wallet_balance required = %v", balance["required"])
+	}
+	if byName["get_wallet_balance"].RequiresConfirmation() {
+		t.Error("get_wallet_balance should not require confirmation")
+	}
+
+	send := byName["send_money"]
+	if !send.RequiresConfirmation() {
+		t.Error("send_money should require confirmation")
+	}
+	props = send.Schema()["properties"].(map[string]interface{})
+	if _, ok := props["id"]; ok {
+		t.Error("read-only id should not be an input")
+	}
+	if props["amount"].(map[string]interface{})["pattern"] == nil {
+		t.Errorf("amount $ref not resolved: %v", props["amount"])
+	}
+	if fmt.Sprint(props["note"].(map[string]interface{})["type"]) != "[string null]" {
+		t.Errorf("nullable note type = %v", props["note"])
+	}
+	if fmt.Sprint(send.Schema()["required"]) != "[recipient amount]" {
+		t.Errorf("send_money required = %v", send.Schema()["required"])
+	}
+
+	notes := byName["replace_notes"].Schema()["properties"].(map[string]interface{})
+	if notes["body"].(map[string]interface{})["type"] != "array" {
+		t.Errorf("array body should be a body field: %v", notes)
+	}
+
+	if _, err := spec.Tools(Config{Operations: []string{"getWalletBalance", "missing"}}); err == nil {
+		t.Error("expected an error for a missing operation")
+	}
+	selected, err := spec.Tools(Config{Operations: []string{"send_money"}, NamePrefix: "gw_"})
+	if err != nil || len(selected) != 1 || selected[0].Name() != "gw_send_money" {
+		t.Errorf("selected tools = %v, %v", selected, err)
+	}
+}
+
+func TestToolsExecute(t *testing.T) {
+	type request struct {
+		method, path, query, auth, apiKey, idempotency, body string
+	}
+	var got request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = request{r.Method, r.URL.Path, r.URL.RawQuery, r.Header.Get("Authorization"), r.Header.Get("X-API-Key"), r.Header.Get("Idempotency-Key"), string(body)}
+		switch r.URL.Path {
+		case "/nim/v1/wallets/w 1/balance":
+			w.Write([]byte(`{"balance":"12.50"}`))
+		case "/nim/v1/notes":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, `{"message":"insufficient funds"}`, http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	tools, err := loadTestSpec(t).Tools(Config{
+		BaseURL:     server.URL + "/nim/v1/",
+		Credentials: map[string]string{"bearerAuth": "jwt-token", "apiKey": "secret"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]core.Tool)
+	for _, tool := range tools {
+		byName[tool.Name()] = tool
+	}
+	ctx := context.Background()
+
+	result, _ := byName["get_wallet_balance"].Execute(ctx, &core.ToolParams{Input: json.RawMessage(`{"walletId":"w 1","currency":"USD"}`)})
+	if !result.Success || result.Data.(map[string]interface{})["balance"] != "12.50" {
+		t.Errorf("get_wallet_balance = %+v", result)
+	}
+	if got.method != "GET" || got.query != "currency=USD" || got.auth != "Bearer jwt-token" || got.body != "" {
+		t.Errorf("balance request = %+v", got)
+	}
+
+	input := json.RawMessage(`{"recipient":"@bob","amount":"5.00"}`)
+	if summary := byName["send_money"].GetSummary(input); summary != `Send money: {"amount":"5.00","recipient":"@bob"}` {
+		t.Errorf("summary = %q", summary)
+	}
+	result, _ = byName["send_money"].Execute(ctx, &core.ToolParams{Input: input, ConfirmationID: "conf-1"})
+	if result.Success || result.Error != `HTTP 400: {"message":"insufficient funds"}` {
+		t.Errorf("send_money = %+v", result)
+	}
+	if got.method != "POST" || got.idempotency != "conf-1" || got.body != `{"amount":"5.00","recipient":"@bob"}` {
+		t.Errorf("payment request = %+v", got)
+	}
+
+	result, _ = byName["replace_notes"].Execute(ctx, &core.ToolParams{Input: json.RawMessage(`{"body":["a","b"]}`)})
+	if !result.Success || got.method != "PUT" || got.body != `["a","b"]` || got.apiKey != "secret" || got.auth != "" {
+		t.Errorf("replace_notes = %+v, request %+v", result, got)
+	}
+
+	result, _ = byName["get_wallet_balance"].Execute(ctx, &core.ToolParams{Input: json.RawMessage(`{}`)})
+	if result.Success || result.Error != "missing required parameter walletId" {
+		t.Errorf("missing path parameter = %+v", result)
+	}
+}
@@ -0,0 +1,341 @@
+// Package openapi turns OpenAPI 3 operations into tools.
+//
+// Load a spec from a local JSON or YAML file and register its operations:
+//
+//	spec, err := openapi.LoadFile("gateway.yaml")
+//	if err != nil { ... }
+//	tools, err := spec.Tools(openapi.Config{
+//		BaseURL:     "https://api.liminal.cash",
+//		Operations:  []string{"getBalance", "sendMoney"},
+//		Credentials: map[string]string{"bearerAuth": jwt},
+//	})
+//	if err != nil { ... }
+//	srv.AddTools(tools...)
+//
+// Path, query and header parameters and the JSON request body become the
+// tool's input schema. Operations marked with x-requires-confirmation: true
+// require user confirmation before running.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExtRequiresConfirmation marks an operation as a write that needs user
+// confirmation.
+const ExtRequiresConfirmation = "x-requires-confirmation"
+
+// maxRefDepth bounds nested $ref resolution.
+const maxRefDepth = 32
+
+// methods are the HTTP methods an OpenAPI path item can define, in the
+// order operations are listed.
+var methods = []string{
+	http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete,
+	http.MethodOptions, http.MethodHead, http.MethodPatch, http.MethodTrace,
+}
+
+// Spec is a parsed OpenAPI 3 document.
+type Spec struct {
+	doc map[string]interface{}
+}
+
+// Operation describes one operation in a spec.
+type Operation struct {
+	// ID is the operationId, or "<method>_<path>" if it has none.
+	ID string
+
+	// Method is the upper-case HTTP method.
+	Method string
+
+	// Path is the path template, e.g. "/wallet/{walletId}/balance".
+	Path string
+
+	Summary     string
+	Description string
+	Tags        []string
+
+	// RequiresConfirmation is set by the x-requires-confirmation extension.
+	RequiresConfirmation bool
+
+	op       map[string]interface{}
+	pathItem map[string]interface{}
+}
+
+// LoadFile reads a JSON or YAML spec from a local file.
+func LoadFile(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// Load parses a JSON or YAML spec.
+func Load(data []byte) (*Spec, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec: %w", err)
+	}
+	doc, ok := normalize(raw).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid OpenAPI spec: not an object")
+	}
+	version, _ := doc["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q; want 3.x", version)
+	}
+	return &Spec{doc: doc}, nil
+}
+
+// Title returns the spec's info.title.
+func (s *Spec) Title() string {
+	info, _ := s.doc["info"].(map[string]interface{})
+	title, _ := info["title"].(string)
+	return title
+}
+
+// ServerURL returns the first server URL, or "" if none is listed.
+func (s *Spec) ServerURL() string {
+	servers, _ := s.doc["servers"].([]interface{})
+	if len(servers) == 0 {
+		return ""
+	}
+	server, _ := servers[0].(map[string]interface{})
+	u, _ := server["url"].(string)
+	return u
+}
+
+// Operations lists the spec's operations, sorted by path then method.
+func (s *Spec) Operations() []Operation {
+	paths, _ := s.doc["paths"].(map[string]interface{})
+	keys := make([]string, 0, len(paths))
+	for path := range paths {
+		keys = append(keys, path)
+	}
+	sort.Strings(keys)
+
+	var ops []Operation
+	for _, path := range keys {
+		item, _ := s.resolve(paths[path]).(map[string]interface{})
+		for _, method := range methods {
+			op, ok := item[strings.ToLower(method)].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			operation := Operation{
+				Method:   method,
+				Path:     path,
+				op:       op,
+				pathItem: item,
+			}
+			operation.ID, _ = op["operationId"].(string)
+			if operation.ID == "" {
+				operation.ID = strings.ToLower(method) + "_" + path
+			}
+			operation.Summary, _ = op["summary"].(string)
+			operation.Description, _ = op["description"].(string)
+			operation.RequiresConfirmation, _ = op[ExtRequiresConfirmation].(bool)
+			for _, tag := range asSlice(op["tags"]) {
+				if t, ok := tag.(string); ok {
+					operation.Tags = append(operation.Tags, t)
+				}
+			}
+			ops = append(ops, operation)
+		}
+	}
+	return ops
+}
+
+// resolve follows a local $ref ("#/components/...") to its target. Other
+// values are returned unchanged.
+func (s *Spec) resolve(v interface{}) interface{} {
+	for i := 0; i < maxRefDepth; i++ {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return v
+		}
+		target, ok := s.lookup(ref)
+		if !ok {
+			return v
+		}
+		v = target
+	}
+	return v
+}
+
+// lookup finds the value at a local JSON pointer reference.
+func (s *Spec) lookup(ref string) (interface{}, bool) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, false
+	}
+	var v interface{} = s.doc
+	for _, part := range strings.Split(ref[2:], "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// schema returns a self-contained JSON Schema: local $refs are inlined,
+// read-only properties dropped, and OpenAPI's nullable folded into type.
+// Recursive references become open objects.
+func (s *Spec) schema(v interface{}, seen []string) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := val["$ref"].(string); ok {
+			for _, r := range seen {
+				if r == ref {
+					return map[string]interface{}{"type": "object"}
+				}
+			}
+			target, ok := s.lookup(ref)
+			if !ok || len(seen) >= maxRefDepth {
+				return map[string]interface{}{}
+			}
+			return s.schema(target, append(seen, ref))
+		}
+
+		out := make(map[string]interface{}, len(val))
+		for k, child := range val {
+			switch k {
+			case "properties":
+				props, _ := child.(map[string]interface{})
+				resolved := make(map[string]interface{}, len(props))
+				for name, prop := range props {
+					if readOnly, _ := s.resolve(prop).(map[string]interface{})["readOnly"].(bool); readOnly {
+						continue
+					}
+					resolved[name] = s.schema(prop, seen)
+				}
+				out[k] = resolved
+			case "nullable", "readOnly", "writeOnly", "xml", "externalDocs", "discriminator", "example":
+				// OpenAPI-only keywords
+			default:
+				out[k] = s.schema(child, seen)
+			}
+		}
+		if props, ok := out["properties"].(map[string]interface{}); ok {
+			// Drop required entries for removed read-only properties
+			var required []interface{}
+			for _, r := range asSlice(out["required"]) {
+				if name, _ := r.(string); props[name] != nil {
+					required = append(required, name)
+				}
+			}
+			if len(required) > 0 {
+				out["required"] = required
+			} else {
+				delete(out, "required")
+			}
+		}
+		if nullable, _ := val["nullable"].(bool); nullable {
+			if t, ok := out["type"].(string); ok {
+				out["type"] = []interface{}{t, "null"}
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, child := range val {
+			out[i] = s.schema(child, seen)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// normalize converts YAML-decoded maps with non-string keys (such as
+// response codes) into map[string]interface{}.
+func normalize(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			val[k] = normalize(child)
+		}
+		return val
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, child := range val {
+			out[fmt.Sprint(k)] = normalize(child)
+		}
+		return out
+	case []interface{}:
+		for i, child := range val {
+			val[i] = normalize(child)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+func asSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
+
+var (
+	// invalidToolChars matches characters the Claude API rejects in tool names.
+	invalidToolChars = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+	repeatedUnderscores = regexp.MustCompile(`_+`)
+)
+
+// toolName converts an operationId such as "getBalance" or "get-balance"
+// into a snake_case tool name, "get_balance".
+func toolName(id string) string {
+	var b strings.Builder
+	runes := []rune(id)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])
+			if prevLower || nextLower {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	name := invalidToolChars.ReplaceAllString(b.String(), "_")
+	name = strings.Trim(repeatedUnderscores.ReplaceAllString(name, "_"), "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// compact renders input on one line for summaries.
+func compact(input json.RawMessage) string {
+	var v interface{}
+	if err := json.Unmarshal(input, &v); err != nil {
+		return string(input)
+	}
+	out, _ := json.Marshal(v)
+	return string(out)
+}
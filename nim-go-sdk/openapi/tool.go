@@ -0,0 +1,469 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+// bodyField holds a request body that can't be merged into the input's
+// top-level properties.
+const bodyField = "body"
+
+// maxResponseBytes bounds the response body read from an operation.
+const maxResponseBytes = 4 << 20
+
+// Config selects operations and configures how their tools call the API.
+type Config struct {
+	// BaseURL is prepended to operation paths. If empty, the spec's first
+	// server URL is used.
+	BaseURL string
+
+	// Operations lists the operationIds (or generated tool names) to turn
+	// into tools. If empty, every operation is used.
+	Operations []string
+
+	// NamePrefix is prepended to tool names, e.g. "crm_".
+	NamePrefix string
+
+	// Credentials maps security scheme names from components.securitySchemes
+	// to secrets: a token for bearer and OAuth schemes, a key for apiKey
+	// schemes, or "user:password" for basic auth.
+	Credentials map[string]string
+
+	// Authorize, if set, is called for each request after credentials are
+	// applied, e.g. to add a per-user token.
+	Authorize func(ctx context.Context, req *http.Request, userID string) error
+
+	// HTTPClient sends requests. If nil, a client with Timeout is used.
+	HTTPClient *http.Client
+
+	// Timeout bounds each request when HTTPClient is nil. Defaults to 30s.
+	Timeout time.Duration
+}
+
+// Tools turns the selected operations into tools. It fails if an operation
+// named in cfg.Operations doesn't exist or two tools would share a name.
+func (s *Spec) Tools(cfg Config) ([]core.Tool, error) {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = s.ServerURL()
+	}
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("openapi: no BaseURL and the spec lists no servers")
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	if cfg.HTTPClient == nil {
+		timeout := cfg.Timeout
+		if timeout == 0 {
+			timeout = 30 * time.Second
+		}
+		cfg.HTTPClient = &http.Client{Timeout: timeout}
+	}
+
+	wanted := make(map[string]bool, len(cfg.Operations))
+	for _, id := range cfg.Operations {
+		wanted[id] = false
+	}
+
+	var tools []core.Tool
+	names := make(map[string]string) // tool name -> operation ID
+	for _, op := range s.Operations() {
+		name := toolName(op.ID)
+		if len(cfg.Operations) > 0 {
+			_, byID := wanted[op.ID]
+			_, byName := wanted[name]
+			if !byID && !byName {
+				continue
+			}
+			if byID {
+				wanted[op.ID] = true
+			} else {
+				wanted[name] = true
+			}
+		}
+
+		name = cfg.NamePrefix + name
+		if other, ok := names[name]; ok {
+			return nil, fmt.Errorf("openapi: operations %s and %s both map to tool %s", other, op.ID, name)
+		}
+		names[name] = op.ID
+
+		tool, err := s.newTool(name, op, &cfg)
+		if err != nil {
+			return nil, fmt.Errorf("openapi: operation %s: %w", op.ID, err)
+		}
+		tools = append(tools, tool)
+	}
+
+	for id, found := range wanted {
+		if !found {
+			return nil, fmt.Errorf("openapi: operation %s not found", id)
+		}
+	}
+	return tools, nil
+}
+
+// parameter is an operation parameter sent in the path, query or a header.
+type parameter struct {
+	name     string
+	in       string // "path", "query", "header" or "cookie"
+	required bool
+	schema   interface{}
+}
+
+// operationTool calls one OpenAPI operation.
+type operationTool struct {
+	name        string
+	description string
+	schema      map[string]interface{}
+	op          Operation
+	config      *Config
+
+	params   []parameter
+	hasBody  bool
+	wrapBody bool // body is sent from input["body"] rather than top-level fields
+	security []map[string]interface{}
+	schemes  map[string]interface{}
+}
+
+// Verify operationTool implements core.Tool.
+var _ core.Tool = (*operationTool)(nil)
+
+func (s *Spec) newTool(name string, op Operation, cfg *Config) (*operationTool, error) {
+	t := &operationTool{
+		name:   name,
+		op:     op,
+		config: cfg,
+	}
+
+	t.description = strings.TrimSpace(op.Summary)
+	if op.Description != "" {
+		if t.description != "" && !strings.HasSuffix(t.description, ".") {
+			t.description += "."
+		}
+		t.description = strings.TrimSpace(t.description + " " + op.Description)
+	}
+	if t.description == "" {
+		t.description = op.Method + " " + op.Path
+	}
+
+	// Path-level parameters apply unless the operation overrides them
+	seen := make(map[string]bool)
+	for _, list := range [][]interface{}{asSlice(op.op["parameters"]), asSlice(op.pathItem["parameters"])} {
+		for _, raw := range list {
+			p, ok := s.resolve(raw).(map[string]interface{})
+			if !ok {
+				continue
+			}
+			param := parameter{}
+			param.name, _ = p["name"].(string)
+			param.in, _ = p["in"].(string)
+			param.required, _ = p["required"].(bool)
+			if param.name == "" || seen[param.in+":"+param.name] {
+				continue
+			}
+			seen[param.in+":"+param.name] = true
+
+			schema, _ := s.schema(p["schema"], nil).(map[string]interface{})
+			if schema == nil {
+				schema = map[string]interface{}{"type": "string"}
+			}
+			if desc, ok := p["description"].(string); ok && schema["description"] == nil {
+				schema["description"] = desc
+			}
+			param.schema = schema
+			t.params = append(t.params, param)
+		}
+	}
+
+	properties := make(map[string]interface{})
+	var required []string
+	for _, p := range t.params {
+		if _, dup := properties[p.name]; dup {
+			return nil, fmt.Errorf("parameter %s is defined in more than one location", p.name)
+		}
+		properties[p.name] = p.schema
+		if p.required || p.in == "path" {
+			required = append(required, p.name)
+		}
+	}
+
+	if body, ok := s.resolve(op.op["requestBody"]).(map[string]interface{}); ok {
+		content, _ := body["content"].(map[string]interface{})
+		media, ok := content["application/json"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("only application/json request bodies are supported")
+		}
+		t.hasBody = true
+		bodyRequired, _ := body["required"].(bool)
+		bodySchema, _ := s.schema(media["schema"], nil).(map[string]interface{})
+		if bodySchema == nil {
+			bodySchema = map[string]interface{}{}
+		}
+
+		// Merge an object body's fields into the input when they don't
+		// clash with parameters; otherwise take the body as one field
+		bodyProps, isObject := bodySchema["properties"].(map[string]interface{})
+		for name := range bodyProps {
+			if _, clash := properties[name]; clash {
+				isObject = false
+			}
+		}
+		if isObject {
+			for name, prop := range bodyProps {
+				properties[name] = prop
+			}
+			if bodyRequired {
+				for _, r := range asSlice(bodySchema["required"]) {
+					if name, ok := r.(string); ok {
+						required = append(required, name)
+					}
+				}
+			}
+		} else {
+			if _, clash := properties[bodyField]; clash {
+				return nil, fmt.Errorf("parameter %q clashes with the request body", bodyField)
+			}
+			t.wrapBody = true
+			properties[bodyField] = bodySchema
+			if bodyRequired {
+				required = append(required, bodyField)
+			}
+		}
+	}
+
+	t.schema = map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		t.schema["required"] = required
+	}
+
+	// Operation-level security overrides the document's
+	security, ok := op.op["security"].([]interface{})
+	if !ok {
+		security, _ = s.doc["security"].([]interface{})
+	}
+	for _, req := range security {
+		if m, ok := req.(map[string]interface{}); ok {
+			t.security = append(t.security, m)
+		}
+	}
+	components, _ := s.doc["components"].(map[string]interface{})
+	schemes, _ := components["securitySchemes"].(map[string]interface{})
+	t.schemes = make(map[string]interface{}, len(schemes))
+	for name, scheme := range schemes {
+		t.schemes[name] = s.resolve(scheme)
+	}
+
+	return t, nil
+}
+
+func (t *operationTool) Name() string                   { return t.name }
+func (t *operationTool) Description() string            { return t.description }
+func (t *operationTool) Schema() map[string]interface{} { return t.schema }
+func (t *operationTool) RequiresConfirmation() bool     { return t.op.RequiresConfirmation }
+
+func (t *operationTool) GetSummary(input json.RawMessage) string {
+	action := t.op.Summary
+	if action == "" {
+		action = t.op.Method + " " + t.op.Path
+	}
+	return fmt.Sprintf("%s: %s", strings.TrimSuffix(action, "."), compact(input))
+}
+
+// Execute sends the request. 4xx and 5xx responses become failed results
+// carrying the status and response body.
+func (t *operationTool) Execute(ctx context.Context, params *core.ToolParams) (*core.ToolResult, error) {
+	input := make(map[string]interface{})
+	if len(params.Input) > 0 {
+		if err := json.Unmarshal(params.Input, &input); err != nil {
+			return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+		}
+	}
+
+	req, err := t.newRequest(ctx, input)
+	if err != nil {
+		return &core.ToolResult{Success: false, Error: err.Error()}, nil
+	}
+	if params.ConfirmationID != "" {
+		req.Header.Set("Idempotency-Key", params.ConfirmationID)
+	}
+	t.applyCredentials(req)
+	if t.config.Authorize != nil {
+		if err := t.config.Authorize(ctx, req, params.UserID); err != nil {
+			return &core.ToolResult{Success: false, Error: fmt.Sprintf("authorization failed: %v", err)}, nil
+		}
+	}
+
+	resp, err := t.config.HTTPClient.Do(req)
+	if err != nil {
+		return &core.ToolResult{Success: false, Error: fmt.Sprintf("request failed: %v", err)}, nil
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return &core.ToolResult{Success: false, Error: fmt.Sprintf("failed to read response: %v", err)}, nil
+	}
+
+	if resp.StatusCode >= 400 {
+		return &core.ToolResult{
+			Success: false,
+			Error:   fmt.Sprintf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body))),
+		}, nil
+	}
+
+	var data interface{}
+	if len(bytes.TrimSpace(body)) == 0 {
+		data = map[string]interface{}{"status": resp.StatusCode}
+	} else if err := json.Unmarshal(body, &data); err != nil {
+		data = string(body)
+	}
+	return &core.ToolResult{Success: true, Data: data}, nil
+}
+
+// newRequest builds the HTTP request from tool input.
+func (t *operationTool) newRequest(ctx context.Context, input map[string]interface{}) (*http.Request, error) {
+	path := t.op.Path
+	query := url.Values{}
+	headers := http.Header{}
+	var cookies []*http.Cookie
+
+	for _, p := range t.params {
+		v, ok := input[p.name]
+		if !ok || v == nil {
+			if p.in == "path" {
+				return nil, fmt.Errorf("missing required parameter %s", p.name)
+			}
+			continue
+		}
+		delete(input, p.name)
+
+		switch p.in {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.name+"}", url.PathEscape(formatValue(v)))
+		case "query":
+			if list, ok := v.([]interface{}); ok {
+				for _, item := range list {
+					query.Add(p.name, formatValue(item))
+				}
+			} else {
+				query.Add(p.name, formatValue(v))
+			}
+		case "header":
+			headers.Set(p.name, formatValue(v))
+		case "cookie":
+			cookies = append(cookies, &http.Cookie{Name: p.name, Value: formatValue(v)})
+		}
+	}
+
+	var body io.Reader
+	if t.hasBody {
+		var payload interface{} = input
+		if t.wrapBody {
+			payload = input[bodyField]
+		}
+		if payload != nil {
+			data, err := json.Marshal(payload)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode request body: %w", err)
+			}
+			body = bytes.NewReader(data)
+		}
+	}
+
+	u := t.config.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, t.op.Method, u, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, v := range headers {
+		req.Header[k] = v
+	}
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// applyCredentials satisfies the first security requirement for which every
+// scheme has credentials. Requests are sent unauthenticated otherwise.
+func (t *operationTool) applyCredentials(req *http.Request) {
+	for _, requirement := range t.security {
+		satisfied := true
+		for name := range requirement {
+			if _, ok := t.config.Credentials[name]; !ok {
+				satisfied = false
+				break
+			}
+		}
+		if !satisfied {
+			continue
+		}
+		for name := range requirement {
+			scheme, _ := t.schemes[name].(map[string]interface{})
+			applyScheme(req, scheme, t.config.Credentials[name])
+		}
+		return
+	}
+}
+
+func applyScheme(req *http.Request, scheme map[string]interface{}, secret string) {
+	kind, _ := scheme["type"].(string)
+	switch kind {
+	case "apiKey":
+		name, _ := scheme["name"].(string)
+		switch in, _ := scheme["in"].(string); in {
+		case "query":
+			q := req.URL.Query()
+			q.Set(name, secret)
+			req.URL.RawQuery = q.Encode()
+		case "cookie":
+			req.AddCookie(&http.Cookie{Name: name, Value: secret})
+		default:
+			req.Header.Set(name, secret)
+		}
+	case "http":
+		if s, _ := scheme["scheme"].(string); strings.EqualFold(s, "basic") {
+			user, pass, _ := strings.Cut(secret, ":")
+			req.SetBasicAuth(user, pass)
+			return
+		}
+		req.Header.Set("Authorization", "Bearer "+secret)
+	default:
+		// oauth2 and openIdConnect present an access token
+		req.Header.Set("Authorization", "Bearer "+secret)
+	}
+}
+
+// formatValue renders a JSON value as a parameter string.
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		data, _ := json.Marshal(val)
+		return string(data)
+	}
+}
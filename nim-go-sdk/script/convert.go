@@ -0,0 +1,121 @@
+package script
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// toStarlark converts decoded JSON into Starlark values.
+func toStarlark(v interface{}) starlark.Value {
+	switch val := v.(type) {
+	case nil:
+		return starlark.None
+	case bool:
+		return starlark.Bool(val)
+	case string:
+		return starlark.String(val)
+	case float64:
+		if val == math.Trunc(val) && math.Abs(val) < 1<<53 {
+			return starlark.MakeInt64(int64(val))
+		}
+		return starlark.Float(val)
+	case []interface{}:
+		list := make([]starlark.Value, len(val))
+		for i, child := range val {
+			list[i] = toStarlark(child)
+		}
+		return starlark.NewList(list)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		dict := starlark.NewDict(len(val))
+		for _, k := range keys {
+			dict.SetKey(starlark.String(k), toStarlark(val[k]))
+		}
+		return dict
+	default:
+		return starlark.String(fmt.Sprint(val))
+	}
+}
+
+// toGo converts a Starlark value into JSON-compatible Go values. Dict keys
+// must be strings.
+func toGo(v starlark.Value) (interface{}, error) {
+	switch val := v.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.Bool:
+		return bool(val), nil
+	case starlark.String:
+		return string(val), nil
+	case starlark.Int:
+		if i, ok := val.Int64(); ok {
+			return i, nil
+		}
+		// Too large for int64; keep full precision as a number
+		return json.Number(val.String()), nil
+	case starlark.Float:
+		f := float64(val)
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil, fmt.Errorf("cannot convert %s to JSON", val)
+		}
+		return f, nil
+	case *starlark.List:
+		return iterableToGo(val, val.Len())
+	case starlark.Tuple:
+		return iterableToGo(val, val.Len())
+	case *starlark.Dict:
+		out := make(map[string]interface{}, val.Len())
+		for _, item := range val.Items() {
+			k, ok := starlark.AsString(item[0])
+			if !ok {
+				return nil, fmt.Errorf("dict key %s is not a string", item[0])
+			}
+			child, err := toGo(item[1])
+			if err != nil {
+				return nil, err
+			}
+			out[k] = child
+		}
+		return out, nil
+	case *starlarkstruct.Struct:
+		out := make(map[string]interface{})
+		for _, name := range val.AttrNames() {
+			attr, err := val.Attr(name)
+			if err != nil {
+				return nil, err
+			}
+			child, err := toGo(attr)
+			if err != nil {
+				return nil, err
+			}
+			out[name] = child
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("cannot convert %s to JSON", v.Type())
+	}
+}
+
+func iterableToGo(iterable starlark.Iterable, n int) (interface{}, error) {
+	out := make([]interface{}, 0, n)
+	iter := iterable.Iterate()
+	defer iter.Done()
+	var item starlark.Value
+	for iter.Next(&item) {
+		child, err := toGo(item)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, child)
+	}
+	return out, nil
+}
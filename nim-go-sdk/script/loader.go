@@ -0,0 +1,144 @@
+package script
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/engine"
+)
+
+// Extension is the file extension of tool scripts.
+const Extension = ".star"
+
+// Loader keeps a ToolRegistry in sync with a directory of scripts.
+type Loader struct {
+	dir      string
+	registry *engine.ToolRegistry
+	config   Config
+
+	mu    sync.Mutex
+	files map[string]*scriptFile // path -> last successful load
+}
+
+type scriptFile struct {
+	modTime time.Time
+	size    int64
+	tool    string
+}
+
+// NewLoader creates a loader for the *.star files in dir. Call Load to
+// register them, and Watch to reload them as they change.
+func NewLoader(dir string, registry *engine.ToolRegistry, cfg Config) *Loader {
+	return &Loader{
+		dir:      dir,
+		registry: registry,
+		config:   cfg,
+		files:    make(map[string]*scriptFile),
+	}
+}
+
+// Load registers new and changed scripts and unregisters the tools of
+// deleted ones. A script that fails to compile keeps its previous version
+// registered, and a script named after a tool the loader didn't register is
+// refused; the errors of all failing scripts are returned together.
+func (l *Loader) Load() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(l.dir, "*"+Extension))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+
+	var errs []error
+	present := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		present[path] = true
+		info, err := os.Stat(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		prev := l.files[path]
+		if prev != nil && prev.modTime.Equal(info.ModTime()) && prev.size == info.Size() {
+			continue
+		}
+
+		tool, err := LoadFile(path, l.config)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		owner := l.owner(tool.Name())
+		if owner != "" && owner != path {
+			errs = append(errs, fmt.Errorf("%s: tool %s is already defined by %s", path, tool.Name(), owner))
+			continue
+		}
+		// Scripts may not replace tools registered by anything else, e.g.
+		// to drop a built-in's confirmation requirement
+		if _, ok := l.registry.Get(tool.Name()); ok && owner == "" {
+			errs = append(errs, fmt.Errorf("%s: tool %s is already registered", path, tool.Name()))
+			continue
+		}
+
+		if prev != nil && prev.tool != tool.Name() {
+			l.registry.Unregister(prev.tool)
+		}
+		l.registry.Register(tool)
+		l.files[path] = &scriptFile{modTime: info.ModTime(), size: info.Size(), tool: tool.Name()}
+	}
+
+	for path, file := range l.files {
+		if !present[path] {
+			l.registry.Unregister(file.tool)
+			delete(l.files, path)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// owner returns the script that registered a tool name, if any.
+func (l *Loader) owner(tool string) string {
+	for path, file := range l.files {
+		if file.tool == tool {
+			return path
+		}
+	}
+	return ""
+}
+
+// Watch polls the directory every interval and reloads changed scripts
+// until ctx is done. Load errors are passed to onError, which may be nil.
+func (l *Loader) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := l.Load(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Tools returns the names of the registered script tools.
+func (l *Loader) Tools() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	names := make([]string, 0, len(l.files))
+	for _, file := range l.files {
+		names = append(names, file.tool)
+	}
+	sort.Strings(names)
+	return names
+}
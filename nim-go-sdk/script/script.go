@@ -0,0 +1,432 @@
+// Package script runs tools written in Starlark, a sandboxed Python dialect.
+//
+// A script declares the tool and defines a handler:
+//
+//	description = "Calculate the fee for a transfer"
+//	schema = {
+//	    "type": "object",
+//	    "properties": {"amount": {"type": "number"}},
+//	    "required": ["amount"],
+//	}
+//
+//	def handler(input):
+//	    fee = max(0.25, input["amount"] * 0.01)
+//	    return {"fee": math.round(fee * 100) / 100}
+//
+// Scripts have no filesystem or network access. They can call the
+// whitelisted host functions described on Config, and run under step,
+// time and allocation limits. Load a directory of scripts with NewLoader,
+// which registers them in a ToolRegistry and reloads them when they change.
+package script
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime/metrics"
+	"strings"
+	"time"
+
+	starjson "go.starlark.net/lib/json"
+	"go.starlark.net/lib/math"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+const (
+	// DefaultMaxSteps bounds the Starlark computation steps of one call.
+	DefaultMaxSteps = 1_000_000
+
+	// DefaultTimeout bounds the wall time of one call.
+	DefaultTimeout = 2 * time.Second
+
+	// DefaultMaxAllocBytes bounds the bytes allocated during one call.
+	DefaultMaxAllocBytes = 64 << 20
+
+	// allocCheckInterval is how often allocation is sampled.
+	allocCheckInterval = 5 * time.Millisecond
+)
+
+// DefaultAllowedTools are the read-only Liminal tools scripts may call.
+var DefaultAllowedTools = tools.ReadOnlyLiminalTools()
+
+// Config configures the host functions and limits of scripted tools.
+//
+// Scripts can use the json and math modules, log(msg) to write to Logger,
+// and, when Executor is set, call_tool(name, args) to run one of
+// AllowedTools. call_tool returns the tool's data and fails on error.
+type Config struct {
+	// Executor runs call_tool requests. If nil, call_tool is unavailable.
+	Executor core.ToolExecutor
+
+	// AllowedTools lists the executor tools scripts may call. Defaults to
+	// DefaultAllowedTools. See tools.ReadOnlyLiminalTools for why these
+	// must be read-only.
+	AllowedTools []string
+
+	// MaxSteps bounds Starlark computation steps per call. Defaults to
+	// DefaultMaxSteps.
+	MaxSteps uint64
+
+	// Timeout bounds the wall time of a call, including host function
+	// calls. Defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// MaxAllocBytes bounds the bytes allocated while a call runs. It is
+	// measured process-wide, so it is approximate under concurrent load.
+	// Defaults to DefaultMaxAllocBytes.
+	MaxAllocBytes uint64
+
+	// Logger receives log() and print() output. If nil, the standard
+	// logger is used.
+	Logger *log.Logger
+}
+
+func (c Config) withDefaults() Config {
+	if c.AllowedTools == nil {
+		c.AllowedTools = DefaultAllowedTools
+	}
+	if c.MaxSteps == 0 {
+		c.MaxSteps = DefaultMaxSteps
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.MaxAllocBytes == 0 {
+		c.MaxAllocBytes = DefaultMaxAllocBytes
+	}
+	if c.Logger == nil {
+		c.Logger = log.Default()
+	}
+	return c
+}
+
+// Tool is a tool defined by a Starlark script.
+type Tool struct {
+	name                 string
+	description          string
+	schema               map[string]interface{}
+	requiresConfirmation bool
+	summary              string
+
+	filename string
+	handler  *starlark.Function
+	config   Config
+}
+
+// Verify Tool implements core.Tool.
+var _ core.Tool = (*Tool)(nil)
+
+// LoadFile compiles a script file. The tool is named by the script's name
+// global, or by the file name without its extension.
+func LoadFile(path string, cfg Config) (*Tool, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(path, src, cfg)
+}
+
+// New compiles a script. The script's top level runs once, under the same
+// limits as a call, and must define description, schema and handler.
+// name, requires_confirmation and summary are optional.
+func New(filename string, src []byte, cfg Config) (*Tool, error) {
+	cfg = cfg.withDefaults()
+	t := &Tool{filename: filename, config: cfg}
+
+	var globals starlark.StringDict
+	err := t.run(context.Background(), "", func(thread *starlark.Thread) error {
+		var err error
+		globals, err = starlark.ExecFileOptions(&syntax.FileOptions{}, thread, filename, src, t.predeclared())
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	globals.Freeze()
+
+	t.name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	if err := stringGlobal(globals, "name", &t.name); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	if err := stringGlobal(globals, "description", &t.description); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	if err := stringGlobal(globals, "summary", &t.summary); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	if t.description == "" {
+		return nil, fmt.Errorf("%s: description is required", filename)
+	}
+
+	if v, ok := globals["requires_confirmation"]; ok {
+		b, ok := v.(starlark.Bool)
+		if !ok {
+			return nil, fmt.Errorf("%s: requires_confirmation must be a bool", filename)
+		}
+		t.requiresConfirmation = bool(b)
+	}
+
+	schema, ok := globals["schema"]
+	if !ok {
+		return nil, fmt.Errorf("%s: schema is required", filename)
+	}
+	converted, err := toGo(schema)
+	if err != nil {
+		return nil, fmt.Errorf("%s: schema: %w", filename, err)
+	}
+	if t.schema, ok = converted.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("%s: schema must be a dict", filename)
+	}
+
+	fn, ok := globals["handler"].(*starlark.Function)
+	if !ok {
+		return nil, fmt.Errorf("%s: handler function is required", filename)
+	}
+	if fn.NumParams() < 1 || fn.NumParams() > 2 {
+		return nil, fmt.Errorf("%s: handler must take (input) or (input, context)", filename)
+	}
+	t.handler = fn
+	return t, nil
+}
+
+func (t *Tool) Name() string                   { return t.name }
+func (t *Tool) Description() string            { return t.description }
+func (t *Tool) Schema() map[string]interface{} { return t.schema }
+func (t *Tool) RequiresConfirmation() bool     { return t.requiresConfirmation }
+
+// GetSummary returns the script's summary global, with {field}
+// placeholders replaced by input values.
+func (t *Tool) GetSummary(input json.RawMessage) string {
+	if t.summary == "" {
+		return t.description
+	}
+	var fields map[string]interface{}
+	json.Unmarshal(input, &fields)
+	summary := t.summary
+	for k, v := range fields {
+		summary = strings.ReplaceAll(summary, "{"+k+"}", fmt.Sprint(v))
+	}
+	return summary
+}
+
+// Execute calls the script's handler with the decoded input and, if the
+// handler takes two parameters, a context with user_id and request_id.
+// fail() and limit violations become failed results.
+func (t *Tool) Execute(ctx context.Context, params *core.ToolParams) (*core.ToolResult, error) {
+	var input interface{} = map[string]interface{}{}
+	if len(params.Input) > 0 && string(params.Input) != "null" {
+		if err := json.Unmarshal(params.Input, &input); err != nil {
+			return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+		}
+	}
+	args := starlark.Tuple{toStarlark(input)}
+	if t.handler.NumParams() == 2 {
+		args = append(args, starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+			"user_id":    starlark.String(params.UserID),
+			"request_id": starlark.String(params.RequestID),
+		}))
+	}
+
+	var result starlark.Value
+	err := t.run(ctx, params.UserID, func(thread *starlark.Thread) error {
+		var err error
+		result, err = starlark.Call(thread, t.handler, args, nil)
+		return err
+	})
+	if err != nil {
+		return &core.ToolResult{Success: false, Error: err.Error()}, nil
+	}
+
+	data, err := toGo(result)
+	if err != nil {
+		return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid handler result: %v", err)}, nil
+	}
+	return &core.ToolResult{Success: true, Data: data}, nil
+}
+
+// Thread-local keys holding the call's context and user.
+const (
+	threadContext = "nim.context"
+	threadUserID  = "nim.user_id"
+)
+
+// run executes fn on a fresh thread under the configured limits.
+func (t *Tool) run(ctx context.Context, userID string, fn func(thread *starlark.Thread) error) error {
+	ctx, cancel := context.WithTimeout(ctx, t.config.Timeout)
+	defer cancel()
+
+	thread := &starlark.Thread{
+		Name: t.filename,
+		Print: func(_ *starlark.Thread, msg string) {
+			t.config.Logger.Printf("script %s: %s", t.filename, msg)
+		},
+		Load: func(*starlark.Thread, string) (starlark.StringDict, error) {
+			return nil, errors.New("load is not allowed")
+		},
+	}
+	thread.SetMaxExecutionSteps(t.config.MaxSteps)
+	thread.SetLocal(threadContext, ctx)
+	thread.SetLocal(threadUserID, userID)
+
+	// Watch for timeouts and excessive allocation while fn runs
+	reason := make(chan string, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		start := allocatedBytes()
+		ticker := time.NewTicker(allocCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				r := "cancelled"
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					r = fmt.Sprintf("exceeded the %s time limit", t.config.Timeout)
+				}
+				reason <- r
+				thread.Cancel(r)
+				return
+			case <-ticker.C:
+				if allocatedBytes()-start > t.config.MaxAllocBytes {
+					r := fmt.Sprintf("exceeded the %d byte memory limit", t.config.MaxAllocBytes)
+					reason <- r
+					thread.Cancel(r)
+					return
+				}
+			}
+		}
+	}()
+
+	err := fn(thread)
+	if err == nil {
+		return nil
+	}
+	select {
+	case r := <-reason:
+		return fmt.Errorf("script %s", r)
+	default:
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		// A host function noticed the deadline before the watchdog did
+		return fmt.Errorf("script exceeded the %s time limit", t.config.Timeout)
+	}
+	if thread.ExecutionSteps() >= t.config.MaxSteps {
+		return fmt.Errorf("script exceeded the %d step limit", t.config.MaxSteps)
+	}
+	var evalErr *starlark.EvalError
+	if errors.As(err, &evalErr) {
+		return errors.New(strings.TrimPrefix(evalErr.Msg, "fail: "))
+	}
+	return err
+}
+
+// allocMetric is the cumulative bytes allocated on the heap.
+const allocMetric = "/gc/heap/allocs:bytes"
+
+func allocatedBytes() uint64 {
+	sample := []metrics.Sample{{Name: allocMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+// predeclared returns the globals available to scripts.
+func (t *Tool) predeclared() starlark.StringDict {
+	globals := starlark.StringDict{
+		"json": starjson.Module,
+		"math": math.Module,
+		"log":  starlark.NewBuiltin("log", t.log),
+	}
+	if t.config.Executor != nil {
+		globals["call_tool"] = starlark.NewBuiltin("call_tool", t.callTool)
+	}
+	return globals
+}
+
+func (t *Tool) log(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var msg string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &msg); err != nil {
+		return nil, err
+	}
+	t.config.Logger.Printf("script %s: %s", t.filename, msg)
+	return starlark.None, nil
+}
+
+// callTool runs an allowed executor tool for the calling user.
+func (t *Tool) callTool(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var toolArgs starlark.Value = starlark.NewDict(0)
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name, "args?", &toolArgs); err != nil {
+		return nil, err
+	}
+	allowed := false
+	for _, tool := range t.config.AllowedTools {
+		allowed = allowed || tool == name
+	}
+	if !allowed {
+		return nil, fmt.Errorf("call_tool: %s is not allowed", name)
+	}
+
+	input, err := toGo(toolArgs)
+	if err != nil {
+		return nil, fmt.Errorf("call_tool: %w", err)
+	}
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("call_tool: %w", err)
+	}
+
+	ctx, _ := thread.Local(threadContext).(context.Context)
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	userID, _ := thread.Local(threadUserID).(string)
+	if userID == "" {
+		return nil, errors.New("call_tool: not available while loading")
+	}
+	resp, err := t.config.Executor.Execute(ctx, &core.ExecuteRequest{
+		UserID: userID,
+		Tool:   name,
+		Input:  inputJSON,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("call_tool %s: %w", name, err)
+	}
+	if !resp.Success {
+		return nil, fmt.Errorf("call_tool %s: %s", name, resp.Error)
+	}
+
+	var data interface{}
+	if len(resp.Data) > 0 {
+		if err := json.Unmarshal(resp.Data, &data); err != nil {
+			return nil, fmt.Errorf("call_tool %s: %w", name, err)
+		}
+	}
+	return toStarlark(data), nil
+}
+
+func stringGlobal(globals starlark.StringDict, name string, dst *string) error {
+	v, ok := globals[name]
+	if !ok {
+		return nil
+	}
+	s, ok := starlark.AsString(v)
+	if !ok {
+		return fmt.Errorf("%s must be a string", name)
+	}
+	*dst = s
+	return nil
+}
@@ -0,0 +1,172 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/store"
+)
+
+const (
+	// defaultConversationPageSize is used when list_conversations omits a limit.
+	defaultConversationPageSize = 20
+
+	// maxConversationPageSize caps the limit a client may request.
+	maxConversationPageSize = 100
+)
+
+// ownedConversation fetches a conversation and verifies it belongs to userID.
+// Conversations owned by other users are reported as not found so their
+// existence isn't leaked.
+func (s *Server) ownedConversation(ctx context.Context, userID, conversationID string) (*store.ConversationWithMessages, bool) {
+	if conversationID == "" {
+		return nil, false
+	}
+	conv, err := s.conversations.Get(ctx, conversationID)
+	if err != nil || conv.UserID != userID {
+		return nil, false
+	}
+	return conv, true
+}
+
+func (s *Server) handleListConversations(ctx context.Context, out eventSink, userID string, limit int, cursor string) {
+	if limit <= 0 {
+		limit = defaultConversationPageSize
+	}
+	if limit > maxConversationPageSize {
+		limit = maxConversationPageSize
+	}
+
+	offset := 0
+	if cursor != "" {
+		n, err := strconv.Atoi(cursor)
+		if err != nil || n < 0 {
+			s.sendError(out, "Invalid cursor")
+			return
+		}
+		offset = n
+	}
+
+	// The store returns the most recent conversations first, so a page is a
+	// window over List with one extra entry to detect whether more remain.
+	convs, err := s.conversations.List(ctx, userID, offset+limit+1)
+	if err != nil {
+		s.sendError(out, fmt.Sprintf("Failed to list conversations: %v", err))
+		return
+	}
+
+	var nextCursor string
+	if len(convs) > offset+limit {
+		nextCursor = strconv.Itoa(offset + limit)
+		convs = convs[:offset+limit]
+	}
+	if offset > len(convs) {
+		offset = len(convs)
+	}
+
+	summaries := make([]ConversationSummary, 0, len(convs)-offset)
+	for _, conv := range convs[offset:] {
+		summaries = append(summaries, ConversationSummary{
+			ID:        conv.ID,
+			Title:     conv.Title,
+			CreatedAt: conv.CreatedAt.Unix(),
+			UpdatedAt: conv.UpdatedAt.Unix(),
+		})
+	}
+
+	s.send(out, ServerMessage{
+		Type:          "conversations",
+		Conversations: summaries,
+		NextCursor:    nextCursor,
+	})
+}
+
+func (s *Server) handleGetConversation(ctx context.Context, out eventSink, userID, conversationID string) {
+	conv, ok := s.ownedConversation(ctx, userID, conversationID)
+	if !ok {
+		s.sendError(out, "Conversation not found")
+		return
+	}
+
+	s.send(out, ServerMessage{
+		Type:           "conversation",
+		ConversationID: conv.ID,
+		Title:          conv.Title,
+		Messages:       conv.Messages,
+	})
+}
+
+func (s *Server) handleRenameConversation(ctx context.Context, out eventSink, userID, conversationID, title string) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		s.sendError(out, "title is required")
+		return
+	}
+
+	if _, ok := s.ownedConversation(ctx, userID, conversationID); !ok {
+		s.sendError(out, "Conversation not found")
+		return
+	}
+
+	if err := s.conversations.SetTitle(ctx, conversationID, title); err != nil {
+		s.sendError(out, fmt.Sprintf("Failed to rename conversation: %v", err))
+		return
+	}
+
+	s.send(out, ServerMessage{
+		Type:           "conversation_renamed",
+		ConversationID: conversationID,
+		Title:          title,
+	})
+}
+
+// handleDeleteConversation deletes a conversation and reports whether it succeeded.
+func (s *Server) handleDeleteConversation(ctx context.Context, out eventSink, userID, conversationID string) bool {
+	if _, ok := s.ownedConversation(ctx, userID, conversationID); !ok {
+		s.sendError(out, "Conversation not found")
+		return false
+	}
+
+	if err := s.conversations.Delete(ctx, conversationID); err != nil {
+		s.sendError(out, fmt.Sprintf("Failed to delete conversation: %v", err))
+		return false
+	}
+	s.httpSessions.Delete(conversationID)
+
+	s.send(out, ServerMessage{
+		Type:           "conversation_deleted",
+		ConversationID: conversationID,
+	})
+	return true
+}
+
+func (s *Server) handleListPendingActions(ctx context.Context, out eventSink, userID string) {
+	lister, ok := s.confirmations.(store.PendingLister)
+	if !ok {
+		s.sendError(out, "Listing pending actions is not supported by the confirmation store")
+		return
+	}
+
+	actions, err := lister.ListPending(ctx, userID)
+	if err != nil {
+		s.sendError(out, fmt.Sprintf("Failed to list pending actions: %v", err))
+		return
+	}
+
+	pending := make([]Confirmation, 0, len(actions))
+	for _, action := range actions {
+		pending = append(pending, Confirmation{
+			ID:        action.ID,
+			Tool:      action.Tool,
+			Summary:   action.Summary,
+			ExpiresAt: action.ExpiresAt,
+		})
+	}
+
+	s.send(out, ServerMessage{
+		Type:           "pending_actions",
+		PendingActions: pending,
+	})
+}
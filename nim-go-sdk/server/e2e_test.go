@@ -0,0 +1,102 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/anthropictest"
+	"github.com/becomeliminal/nim-go-sdk/executor"
+	"github.com/becomeliminal/nim-go-sdk/simbank"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+func TestEndToEndToolUse(t *testing.T) {
+	bank, err := simbank.New(simbank.Config{})
+	if err != nil {
+		t.Fatalf("simbank.New failed: %v", err)
+	}
+	bankServer := simbank.NewServer(bank)
+	defer bankServer.Close()
+
+	fake := anthropictest.NewServer(
+		anthropictest.ToolUse("get_balance", map[string]interface{}{"currency": "USD"}).WithUsage(anthropictest.Usage{
+			InputTokens: 100, OutputTokens: 20, CacheCreationInputTokens: 50,
+		}),
+		anthropictest.Text("You have 1250.00 USD.").WithUsage(anthropictest.Usage{
+			InputTokens: 150, OutputTokens: 10, CacheReadInputTokens: 50,
+		}),
+		anthropictest.Text("USD balance check"), // title
+	)
+	defer fake.Close()
+
+	srv, err := New(Config{
+		AnthropicKey: "test-key",
+		BaseURL:      fake.URL,
+		AuthFunc: func(r *http.Request) (string, error) {
+			return "user_alice", nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	srv.AddTools(tools.LiminalTools(executor.NewHTTPExecutor(executor.HTTPExecutorConfig{
+		BaseURL:  bankServer.URL,
+		JWTToken: "user_alice",
+	}))...)
+
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+	conn := dial(t, ts, "user_alice")
+	newConversation(t, conn)
+
+	if err := conn.WriteJSON(ClientMessage{Type: "message", Content: "What's my balance?"}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	var chunks []string
+	var complete, title ServerMessage
+	for complete.Type == "" || title.Type == "" {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		var msg ServerMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		switch msg.Type {
+		case "text_chunk":
+			chunks = append(chunks, msg.Content)
+		case "complete":
+			complete = msg
+		case "title_updated":
+			title = msg
+		case "error":
+			t.Fatalf("server error: %s", msg.Content)
+		}
+	}
+
+	if got := strings.Join(chunks, ""); got != "You have 1250.00 USD." {
+		t.Errorf("unexpected streamed text: %q", got)
+	}
+	usage := complete.TokenUsage
+	if usage == nil || usage.InputTokens != 250 || usage.OutputTokens != 30 ||
+		usage.CacheCreationInputTokens != 50 || usage.CacheReadInputTokens != 50 {
+		t.Errorf("unexpected token usage: %+v", usage)
+	}
+	if title.Title != "USD balance check" {
+		t.Errorf("unexpected title: %q", title.Title)
+	}
+
+	reqs := fake.Requests()
+	if len(reqs) != 3 {
+		t.Fatalf("expected 3 Messages API requests, got %d", len(reqs))
+	}
+	if !reqs[0].Stream || len(reqs[0].Tools) == 0 {
+		t.Errorf("expected a streaming request with tools, got %s", reqs[0].Body)
+	}
+	// The second request carries the simulated bank's tool result
+	if !strings.Contains(string(reqs[1].Body), "tool_result") || !strings.Contains(string(reqs[1].Body), "1250.00") {
+		t.Errorf("expected tool result with balance in follow-up request, got %s", reqs[1].Body)
+	}
+}
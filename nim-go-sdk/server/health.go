@@ -0,0 +1,250 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/store"
+)
+
+// healthCheckTimeout bounds a single dependency probe in /readyz.
+const healthCheckTimeout = 5 * time.Second
+
+// sdkModulePath identifies the SDK in build info.
+const sdkModulePath = "github.com/becomeliminal/nim-go-sdk"
+
+// ReadyResponse is the body of /readyz.
+type ReadyResponse struct {
+	Status string                 `json:"status"` // "ok", "unavailable" or "shutting_down"
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// CheckResult is the outcome of probing one dependency.
+type CheckResult struct {
+	Status     string `json:"status"` // "ok" or "error"
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// StatusResponse is the body of /debug/status.
+type StatusResponse struct {
+	StartedAt            int64        `json:"startedAt"`
+	Draining             bool         `json:"draining"`
+	WebSocketClients     int          `json:"webSocketClients"`
+	WebSocketSessions    int          `json:"webSocketSessions"`
+	HTTPSessions         int          `json:"httpSessions"`
+	InFlightRuns         int          `json:"inFlightRuns"`
+	PendingConfirmations *int         `json:"pendingConfirmations,omitempty"` // nil if the store can't list them
+	Tools                []ToolStatus `json:"tools"`
+	Build                BuildStatus  `json:"build"`
+}
+
+// ToolStatus describes a registered tool.
+type ToolStatus struct {
+	Name                 string `json:"name"`
+	RequiresConfirmation bool   `json:"requiresConfirmation"`
+}
+
+// BuildStatus describes the running binary.
+type BuildStatus struct {
+	GoVersion   string `json:"goVersion"`
+	Module      string `json:"module,omitempty"`
+	Version     string `json:"version,omitempty"`
+	SDKVersion  string `json:"sdkVersion,omitempty"`
+	VCSRevision string `json:"vcsRevision,omitempty"`
+	VCSTime     string `json:"vcsTime,omitempty"`
+	VCSModified bool   `json:"vcsModified,omitempty"`
+}
+
+// handleReadyz probes every dependency that implements core.HealthChecker and
+// responds 200 if all are healthy, 503 otherwise. It also reports 503 once
+// shutdown has started so load balancers stop routing new traffic.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	checkers := s.healthCheckers()
+
+	resp := ReadyResponse{
+		Status: "ok",
+		Checks: make(map[string]CheckResult, len(checkers)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, checker := range checkers {
+		wg.Add(1)
+		go func(name string, checker core.HealthChecker) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := checker.CheckHealth(ctx)
+			result := CheckResult{
+				Status:     "ok",
+				DurationMs: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				result.Status = "error"
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			resp.Checks[name] = result
+			if err != nil {
+				resp.Status = "unavailable"
+			}
+			mu.Unlock()
+		}(name, checker)
+	}
+	wg.Wait()
+
+	if s.runs.isDraining() {
+		resp.Status = "shutting_down"
+	}
+
+	status := http.StatusOK
+	if resp.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, resp)
+}
+
+// healthCheckers collects the server's dependencies that can be probed.
+func (s *Server) healthCheckers() map[string]core.HealthChecker {
+	checkers := make(map[string]core.HealthChecker)
+
+	deps := map[string]interface{}{
+		"conversations": s.conversations,
+		"confirmations": s.confirmations,
+	}
+	if s.config.LiminalExecutor != nil {
+		deps["liminal"] = s.config.LiminalExecutor
+	}
+	for name, dep := range deps {
+		if checker, ok := dep.(core.HealthChecker); ok {
+			checkers[name] = checker
+		}
+	}
+
+	for name, checker := range s.config.HealthCheckers {
+		checkers[name] = checker
+	}
+	return checkers
+}
+
+// handleDebugStatus reports runtime state for operators. It is only
+// available when Config.AdminAuthFunc is set and accepts the request.
+func (s *Server) handleDebugStatus(w http.ResponseWriter, r *http.Request) {
+	if s.config.AdminAuthFunc == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if err := s.config.AdminAuthFunc(r); err != nil {
+		writeError(w, http.StatusForbidden, "Forbidden")
+		return
+	}
+
+	resp := StatusResponse{
+		StartedAt:            s.startedAt.Unix(),
+		Draining:             s.runs.isDraining(),
+		WebSocketClients:     countMap(&s.clients),
+		WebSocketSessions:    countMap(&s.sessions),
+		HTTPSessions:         countMap(&s.httpSessions),
+		InFlightRuns:         s.runs.active(),
+		PendingConfirmations: s.countPending(r.Context()),
+		Tools:                s.toolStatuses(),
+		Build:                buildStatus(),
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// countPending counts pending confirmations for users with an active
+// session. It returns nil if the confirmation store can't list actions.
+func (s *Server) countPending(ctx context.Context) *int {
+	lister, ok := s.confirmations.(store.PendingLister)
+	if !ok {
+		return nil
+	}
+
+	users := make(map[string]bool)
+	collect := func(key, value interface{}) bool {
+		users[value.(*session).UserID] = true
+		return true
+	}
+	s.sessions.Range(collect)
+	s.httpSessions.Range(collect)
+
+	total := 0
+	for userID := range users {
+		actions, err := lister.ListPending(ctx, userID)
+		if err != nil {
+			continue
+		}
+		total += len(actions)
+	}
+	return &total
+}
+
+func (s *Server) toolStatuses() []ToolStatus {
+	names := s.registry.List()
+	sort.Strings(names)
+
+	tools := make([]ToolStatus, 0, len(names))
+	for _, name := range names {
+		tool, ok := s.registry.Get(name)
+		if !ok {
+			continue
+		}
+		tools = append(tools, ToolStatus{
+			Name:                 name,
+			RequiresConfirmation: tool.RequiresConfirmation(),
+		})
+	}
+	return tools
+}
+
+func buildStatus() BuildStatus {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return BuildStatus{}
+	}
+
+	status := BuildStatus{
+		GoVersion: info.GoVersion,
+		Module:    info.Main.Path,
+		Version:   info.Main.Version,
+	}
+	if info.Main.Path == sdkModulePath {
+		status.SDKVersion = info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == sdkModulePath {
+			status.SDKVersion = dep.Version
+		}
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			status.VCSRevision = setting.Value
+		case "vcs.time":
+			status.VCSTime = setting.Value
+		case "vcs.modified":
+			status.VCSModified = setting.Value == "true"
+		}
+	}
+	return status
+}
+
+func countMap(m *sync.Map) int {
+	n := 0
+	m.Range(func(key, value interface{}) bool {
+		n++
+		return true
+	})
+	return n
+}
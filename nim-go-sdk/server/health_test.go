@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+type checkerFunc func(ctx context.Context) error
+
+func (f checkerFunc) CheckHealth(ctx context.Context) error { return f(ctx) }
+
+func TestReadyz(t *testing.T) {
+	healthy := true
+	srv, err := New(Config{
+		AnthropicKey: "test-key",
+		HealthCheckers: map[string]core.HealthChecker{
+			"db": checkerFunc(func(ctx context.Context) error {
+				if !healthy {
+					return errors.New("connection refused")
+				}
+				return nil
+			}),
+		},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ts := httptest.NewServer(srv.Mux())
+	t.Cleanup(ts.Close)
+
+	get := func() (int, ReadyResponse) {
+		resp, err := http.Get(ts.URL + "/readyz")
+		if err != nil {
+			t.Fatalf("GET /readyz failed: %v", err)
+		}
+		defer resp.Body.Close()
+		var body ReadyResponse
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+
+	if code, body := get(); code != http.StatusOK || body.Checks["db"].Status != "ok" {
+		t.Fatalf("expected ready, got %d %+v", code, body)
+	}
+
+	healthy = false
+	code, body := get()
+	if code != http.StatusServiceUnavailable || body.Status != "unavailable" {
+		t.Fatalf("expected unavailable, got %d %+v", code, body)
+	}
+	if body.Checks["db"].Error != "connection refused" {
+		t.Errorf("expected db error in checks, got %+v", body.Checks["db"])
+	}
+}
+
+func TestDebugStatusRequiresAdmin(t *testing.T) {
+	srv, err := New(Config{
+		AnthropicKey: "test-key",
+		AdminAuthFunc: func(r *http.Request) error {
+			if r.Header.Get("X-Admin-Token") != "secret" {
+				return errors.New("not an admin")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	srv.registry.Register(core.NewBaseTool(core.ToolDefinition{ToolName: "lookup"}, nil))
+	ts := httptest.NewServer(srv.Mux())
+	t.Cleanup(ts.Close)
+
+	resp, err := http.Get(ts.URL + "/debug/status")
+	if err != nil {
+		t.Fatalf("GET /debug/status failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403 without token, got %d", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest("GET", ts.URL+"/debug/status", nil)
+	req.Header.Set("X-Admin-Token", "secret")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /debug/status failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 with token, got %d", resp.StatusCode)
+	}
+
+	var status StatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if len(status.Tools) != 1 || status.Tools[0].Name != "lookup" {
+		t.Errorf("expected registered tool in status, got %+v", status.Tools)
+	}
+	if status.PendingConfirmations == nil || *status.PendingConfirmations != 0 {
+		t.Errorf("expected zero pending confirmations, got %v", status.PendingConfirmations)
+	}
+	if status.Build.GoVersion == "" {
+		t.Error("expected build info")
+	}
+}
@@ -0,0 +1,366 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/store"
+)
+
+// defaultHTTPSessionIdleTimeout is how long an unused HTTP session is kept.
+const defaultHTTPSessionIdleTimeout = 30 * time.Minute
+
+// HTTPHandler returns an HTTP handler for the plain HTTP API.
+//
+// The API mirrors the WebSocket protocol for clients that cannot hold a
+// WebSocket open (serverless frontends, curl, proxies that block upgrades):
+//
+//	POST /v1/conversations                 start a conversation
+//	GET  /v1/conversations/{id}            fetch a conversation with its messages
+//	POST /v1/conversations/{id}/messages   send a message
+//	POST /v1/actions/{id}/confirm          confirm a pending action
+//	POST /v1/actions/{id}/cancel           cancel a pending action
+//
+// Message, confirm and cancel respond with Server-Sent Events when the request
+// sends "Accept: text/event-stream", and with a JSON EventsResponse otherwise.
+// Each event carries the same ServerMessage the WebSocket handler would send.
+func (s *Server) HTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/conversations", s.handleHTTPCreateConversation)
+	mux.HandleFunc("GET /v1/conversations/{id}", s.handleHTTPGetConversation)
+	mux.HandleFunc("POST /v1/conversations/{id}/messages", s.handleHTTPMessage)
+	mux.HandleFunc("POST /v1/actions/{id}/confirm", s.handleHTTPConfirm)
+	mux.HandleFunc("POST /v1/actions/{id}/cancel", s.handleHTTPCancel)
+	return mux
+}
+
+func (s *Server) handleHTTPCreateConversation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.authenticateHTTP(w, r)
+	if !ok {
+		return
+	}
+
+	out := &jsonSink{}
+	sess := s.handleNewConversation(r.Context(), out, userID)
+	if sess == nil {
+		writeJSON(w, http.StatusInternalServerError, out.events[len(out.events)-1])
+		return
+	}
+	sess.lastUsed.Store(time.Now().UnixNano())
+	s.httpSessions.Store(sess.ConversationID, sess)
+
+	writeJSON(w, http.StatusCreated, out.events[0])
+}
+
+func (s *Server) handleHTTPGetConversation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.authenticateHTTP(w, r)
+	if !ok {
+		return
+	}
+
+	conv, found := s.ownedConversation(r.Context(), userID, r.PathValue("id"))
+	if !found {
+		writeError(w, http.StatusNotFound, "Conversation not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, conv)
+}
+
+func (s *Server) handleHTTPMessage(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.authenticateHTTP(w, r)
+	if !ok {
+		return
+	}
+
+	var msg ClientMessage
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid message format")
+		return
+	}
+	if msg.Content == "" {
+		writeError(w, http.StatusBadRequest, "content is required")
+		return
+	}
+
+	sess, err := s.httpSession(r, userID, r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Conversation not found")
+		return
+	}
+
+	if !s.runs.begin() {
+		writeError(w, http.StatusServiceUnavailable, "Server is shutting down")
+		return
+	}
+	defer s.runs.end()
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	s.respondWithEvents(w, r, sess.ConversationID, func(out eventSink) {
+		s.handleMessage(r.Context(), out, sess, msg.Content)
+	})
+}
+
+func (s *Server) handleHTTPConfirm(w http.ResponseWriter, r *http.Request) {
+	s.handleHTTPAction(w, r, s.handleConfirm)
+}
+
+func (s *Server) handleHTTPCancel(w http.ResponseWriter, r *http.Request) {
+	s.handleHTTPAction(w, r, s.handleCancel)
+}
+
+// handleHTTPAction resolves the conversation that owns a pending action and
+// runs the confirm or cancel handler against it.
+func (s *Server) handleHTTPAction(w http.ResponseWriter, r *http.Request, handle func(ctx context.Context, out eventSink, sess *session, userID, actionID string)) {
+	userID, ok := s.authenticateHTTP(w, r)
+	if !ok {
+		return
+	}
+
+	actionID := r.PathValue("id")
+	v, found := s.actionSessions.Load(actionID)
+	if !found {
+		writeError(w, http.StatusNotFound, "Action not found")
+		return
+	}
+	ref := v.(actionSession)
+	if ref.expired(time.Now()) {
+		// The handler still runs, to tell the client the action expired
+		s.actionSessions.Delete(actionID)
+	}
+
+	sess, err := s.httpSession(r, userID, ref.conversationID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Action not found")
+		return
+	}
+
+	if !s.runs.begin() {
+		writeError(w, http.StatusServiceUnavailable, "Server is shutting down")
+		return
+	}
+	defer s.runs.end()
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	s.respondWithEvents(w, r, sess.ConversationID, func(out eventSink) {
+		handle(r.Context(), out, sess, userID, actionID)
+	})
+}
+
+// httpSession returns the in-memory session for a conversation, restoring it
+// from the conversation store on first use. Sessions idle for longer than
+// Config.HTTPSessionIdleTimeout are evicted and restored again on demand.
+func (s *Server) httpSession(r *http.Request, userID, conversationID string) (*session, error) {
+	now := time.Now()
+	s.evictStaleHTTPState(now)
+
+	if v, ok := s.httpSessions.Load(conversationID); ok {
+		sess := v.(*session)
+		if sess.UserID != userID {
+			return nil, fmt.Errorf("conversation not found: %s", conversationID)
+		}
+		sess.lastUsed.Store(now.UnixNano())
+		return sess, nil
+	}
+
+	conv, err := s.conversations.Get(r.Context(), conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if conv.UserID != userID {
+		return nil, fmt.Errorf("conversation not found: %s", conversationID)
+	}
+
+	sess := &session{
+		ID:             conversationID,
+		UserID:         userID,
+		ConversationID: conversationID,
+		History:        historyFromStored(conv.Messages),
+	}
+	sess.lastUsed.Store(now.UnixNano())
+	actual, _ := s.httpSessions.LoadOrStore(conversationID, sess)
+	return actual.(*session), nil
+}
+
+// actionSession records the conversation that owns a pending action, so
+// HTTP confirm and cancel requests can find it.
+type actionSession struct {
+	conversationID string
+	expiresAt      int64 // unix seconds, as in core.PendingAction
+}
+
+func (a actionSession) expired(now time.Time) bool {
+	return a.expiresAt < now.Unix()
+}
+
+// evictStaleHTTPState removes HTTP sessions idle for longer than the idle
+// timeout and the owners of expired pending actions. It sweeps at most once
+// per minute, or per timeout if shorter. Sessions with a turn in progress
+// are kept.
+func (s *Server) evictStaleHTTPState(now time.Time) {
+	timeout := s.config.HTTPSessionIdleTimeout
+	if timeout <= 0 {
+		timeout = defaultHTTPSessionIdleTimeout
+	}
+	last := s.lastHTTPSweep.Load()
+	if now.Sub(time.Unix(0, last)) < min(timeout, time.Minute) || !s.lastHTTPSweep.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+
+	s.httpSessions.Range(func(key, value interface{}) bool {
+		sess := value.(*session)
+		if now.Sub(time.Unix(0, sess.lastUsed.Load())) < timeout || !sess.mu.TryLock() {
+			return true
+		}
+		s.httpSessions.CompareAndDelete(key, sess)
+		sess.mu.Unlock()
+		return true
+	})
+	s.actionSessions.Range(func(key, value interface{}) bool {
+		if value.(actionSession).expired(now) {
+			s.actionSessions.CompareAndDelete(key, value)
+		}
+		return true
+	})
+}
+
+// respondWithEvents runs fn with a sink matching the client's Accept header.
+func (s *Server) respondWithEvents(w http.ResponseWriter, r *http.Request, conversationID string, fn func(out eventSink)) {
+	if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		if flusher, ok := w.(http.Flusher); ok {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("Connection", "keep-alive")
+			w.WriteHeader(http.StatusOK)
+			flusher.Flush()
+
+			out := &sseSink{w: w, flusher: flusher}
+			defer out.close()
+			fn(out)
+			return
+		}
+	}
+
+	out := &jsonSink{}
+	fn(out)
+	writeJSON(w, http.StatusOK, EventsResponse{
+		ConversationID: conversationID,
+		Events:         out.close(),
+	})
+}
+
+// authenticateHTTP authenticates the request, writing a 401 on failure.
+func (s *Server) authenticateHTTP(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID, err := s.authenticate(r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return "", false
+	}
+	return userID, true
+}
+
+// historyFromStored converts stored messages to core.Message history.
+func historyFromStored(messages []store.StoredMessage) []core.Message {
+	history := make([]core.Message, 0, len(messages))
+	for _, m := range messages {
+		history = append(history, core.Message{
+			Role:    core.Role(m.Role),
+			Content: m.Content,
+		})
+	}
+	return history
+}
+
+// errSinkClosed is returned when a message is sent after the HTTP response
+// has completed, e.g. by background title generation.
+var errSinkClosed = errors.New("response already completed")
+
+// sseSink writes server messages as Server-Sent Events.
+type sseSink struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+	closed  bool
+}
+
+func (s *sseSink) Send(msg ServerMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errSinkClosed
+	}
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", msg.Type, data); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+func (s *sseSink) Streaming() bool {
+	return true
+}
+
+// close stops further writes once the handler has returned.
+func (s *sseSink) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+}
+
+// jsonSink buffers server messages for a single JSON response.
+type jsonSink struct {
+	mu     sync.Mutex
+	events []ServerMessage
+	closed bool
+}
+
+func (j *jsonSink) Send(msg ServerMessage) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.closed {
+		return errSinkClosed
+	}
+	j.events = append(j.events, msg)
+	return nil
+}
+
+func (j *jsonSink) Streaming() bool {
+	return false
+}
+
+// close stops further writes and returns the buffered events.
+func (j *jsonSink) close() []ServerMessage {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.closed = true
+	return j.events
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, content string) {
+	writeJSON(w, status, ServerMessage{Type: "error", Content: content})
+}
@@ -0,0 +1,273 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/anthropictest"
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/store"
+)
+
+// newHTTPTestServer serves the HTTP API backed by a fake Anthropic server.
+// The user is taken from the X-User header, and send_money needs
+// confirmation.
+func newHTTPTestServer(t *testing.T, cfg Config, responses ...anthropictest.Response) (*Server, *httptest.Server, *[]string) {
+	t.Helper()
+	fake := anthropictest.NewServer(responses...)
+	t.Cleanup(fake.Close)
+
+	cfg.AnthropicKey = "test-key"
+	cfg.BaseURL = fake.URL
+	cfg.AuthFunc = func(r *http.Request) (string, error) {
+		if user := r.Header.Get("X-User"); user != "" {
+			return user, nil
+		}
+		return "", fmt.Errorf("missing user")
+	}
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	var sent []string
+	srv.AddTool(core.NewBaseTool(core.ToolDefinition{
+		ToolName:                 "send_money",
+		ToolDescription:          "Send money",
+		RequiresUserConfirmation: true,
+		SummaryTemplate:          "Send {{.amount}} to {{.recipient}}",
+	}, func(ctx context.Context, p *core.ToolParams) (*core.ToolResult, error) {
+		if p.ConfirmationID == "" || p.IdempotencyKey == "" {
+			t.Errorf("expected the pending action's ID and idempotency key, got %+v", p)
+		}
+		sent = append(sent, string(p.Input))
+		return &core.ToolResult{Success: true, Data: map[string]interface{}{"message": "Sent!"}}, nil
+	}))
+
+	ts := httptest.NewServer(srv.Mux())
+	t.Cleanup(ts.Close)
+	return srv, ts, &sent
+}
+
+// call makes an API request and returns the status and body.
+func call(t *testing.T, ts *httptest.Server, method, path, user, accept, body string) (int, string) {
+	t.Helper()
+	req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	if user != "" {
+		req.Header.Set("X-User", user)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+// events decodes a JSON EventsResponse.
+func events(t *testing.T, body string) []ServerMessage {
+	t.Helper()
+	var resp EventsResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatalf("invalid events response %q: %v", body, err)
+	}
+	return resp.Events
+}
+
+// sseEvents decodes a Server-Sent Events stream.
+func sseEvents(t *testing.T, body string) []ServerMessage {
+	t.Helper()
+	var msgs []ServerMessage
+	var event string
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			var msg ServerMessage
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &msg); err != nil {
+				t.Fatalf("invalid event data %q: %v", line, err)
+			}
+			if msg.Type != event {
+				t.Errorf("event %q carries a %q message", event, msg.Type)
+			}
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
+
+func types(msgs []ServerMessage) string {
+	names := make([]string, len(msgs))
+	for i, msg := range msgs {
+		names[i] = msg.Type
+	}
+	return strings.Join(names, ",")
+}
+
+func createConversation(t *testing.T, ts *httptest.Server, user string) string {
+	t.Helper()
+	code, body := call(t, ts, "POST", "/v1/conversations", user, "", "")
+	var msg ServerMessage
+	json.Unmarshal([]byte(body), &msg)
+	if code != http.StatusCreated || msg.Type != "conversation_started" || msg.ConversationID == "" {
+		t.Fatalf("create = %d %s", code, body)
+	}
+	return msg.ConversationID
+}
+
+func TestHTTPConversation(t *testing.T) {
+	_, ts, sent := newHTTPTestServer(t, Config{DisableAutoTitle: true},
+		anthropictest.Text("Hi Alice."),
+		anthropictest.ToolUse("send_money", map[string]interface{}{"recipient": "@bob", "amount": "5"}),
+		anthropictest.ToolUse("send_money", map[string]interface{}{"recipient": "@carol", "amount": "7"}),
+	)
+
+	if code, _ := call(t, ts, "POST", "/v1/conversations", "", "", ""); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a user, got %d", code)
+	}
+	id := createConversation(t, ts, "alice")
+
+	// JSON response
+	code, body := call(t, ts, "POST", "/v1/conversations/"+id+"/messages", "alice", "", `{"content":"hello"}`)
+	if msgs := events(t, body); code != http.StatusOK || types(msgs) != "text,complete" || msgs[0].Content != "Hi Alice." {
+		t.Fatalf("message = %d %s", code, body)
+	}
+	if code, _ := call(t, ts, "POST", "/v1/conversations/"+id+"/messages", "alice", "", `{}`); code != http.StatusBadRequest {
+		t.Errorf("expected 400 without content, got %d", code)
+	}
+
+	// Server-Sent Events response
+	code, body = call(t, ts, "POST", "/v1/conversations/"+id+"/messages", "alice", "text/event-stream", `{"content":"pay bob 5"}`)
+	msgs := sseEvents(t, body)
+	if code != http.StatusOK || types(msgs) != "confirm_request" || msgs[0].Tool != "send_money" {
+		t.Fatalf("message = %d %s", code, body)
+	}
+	actionID := msgs[0].ActionID
+
+	// Other users can't see or act on the conversation
+	for _, req := range [][3]string{
+		{"GET", "/v1/conversations/" + id, ""},
+		{"POST", "/v1/conversations/" + id + "/messages", `{"content":"hi"}`},
+		{"POST", "/v1/actions/" + actionID + "/confirm", ""},
+		{"POST", "/v1/actions/" + actionID + "/cancel", ""},
+	} {
+		if code, body := call(t, ts, req[0], req[1], "mallory", "", req[2]); code != http.StatusNotFound {
+			t.Errorf("%s %s as another user = %d %s", req[0], req[1], code, body)
+		}
+	}
+	if code, _ := call(t, ts, "POST", "/v1/actions/"+actionID+"/confirm", "", "", ""); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 confirming without a user, got %d", code)
+	}
+
+	code, body = call(t, ts, "POST", "/v1/actions/"+actionID+"/confirm", "alice", "", "")
+	if msgs := events(t, body); code != http.StatusOK || types(msgs) != "text,complete" || msgs[0].Content != "Sent!" {
+		t.Fatalf("confirm = %d %s", code, body)
+	}
+	if len(*sent) != 1 || !strings.Contains((*sent)[0], "@bob") {
+		t.Errorf("sent = %v", *sent)
+	}
+	if code, _ := call(t, ts, "POST", "/v1/actions/"+actionID+"/confirm", "alice", "", ""); code != http.StatusNotFound {
+		t.Errorf("expected 404 confirming twice, got %d", code)
+	}
+
+	// Cancel over SSE
+	_, body = call(t, ts, "POST", "/v1/conversations/"+id+"/messages", "alice", "", `{"content":"pay carol 7"}`)
+	actionID = events(t, body)[0].ActionID
+	code, body = call(t, ts, "POST", "/v1/actions/"+actionID+"/cancel", "alice", "text/event-stream", "")
+	if msgs := sseEvents(t, body); code != http.StatusOK || types(msgs) != "text,complete" || msgs[0].Content != "Action cancelled." {
+		t.Fatalf("cancel = %d %s", code, body)
+	}
+	if len(*sent) != 1 {
+		t.Errorf("cancelled action was executed: %v", *sent)
+	}
+
+	code, body = call(t, ts, "GET", "/v1/conversations/"+id, "alice", "", "")
+	var conv store.ConversationWithMessages
+	json.Unmarshal([]byte(body), &conv)
+	if code != http.StatusOK || conv.ID != id || len(conv.Messages) != 5 || conv.Messages[1].Content != "Hi Alice." {
+		t.Errorf("get = %d %s", code, body)
+	}
+	if code, _ := call(t, ts, "GET", "/v1/conversations/missing", "alice", "", ""); code != http.StatusNotFound {
+		t.Errorf("expected 404 for a missing conversation, got %d", code)
+	}
+}
+
+func TestHTTPSessionEviction(t *testing.T) {
+	srv, ts, _ := newHTTPTestServer(t, Config{DisableAutoTitle: true, HTTPSessionIdleTimeout: 10 * time.Millisecond},
+		anthropictest.Text("First."),
+		anthropictest.Text("Second."),
+	)
+
+	id := createConversation(t, ts, "alice")
+	call(t, ts, "POST", "/v1/conversations/"+id+"/messages", "alice", "", `{"content":"one"}`)
+	if n := countMap(&srv.httpSessions); n != 1 {
+		t.Fatalf("expected 1 session, got %d", n)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	other := createConversation(t, ts, "bob")
+	srv.httpSession(httptest.NewRequest("GET", "/", nil), "bob", other)
+	if _, ok := srv.httpSessions.Load(id); ok {
+		t.Fatal("expected the idle session to be evicted")
+	}
+
+	// The evicted conversation is restored from the store
+	code, body := call(t, ts, "POST", "/v1/conversations/"+id+"/messages", "alice", "", `{"content":"two"}`)
+	if msgs := events(t, body); code != http.StatusOK || types(msgs) != "text,complete" || msgs[0].Content != "Second." {
+		t.Fatalf("message after eviction = %d %s", code, body)
+	}
+	v, ok := srv.httpSessions.Load(id)
+	if !ok || len(v.(*session).History) != 4 {
+		t.Errorf("expected the restored session to keep its history, got %+v", v)
+	}
+}
+
+func TestHTTPActionExpiry(t *testing.T) {
+	srv, ts, sent := newHTTPTestServer(t, Config{DisableAutoTitle: true},
+		anthropictest.ToolUse("send_money", map[string]interface{}{"recipient": "@bob", "amount": "5"}),
+	)
+
+	id := createConversation(t, ts, "alice")
+	_, body := call(t, ts, "POST", "/v1/conversations/"+id+"/messages", "alice", "", `{"content":"pay bob"}`)
+	msgs := events(t, body)
+	if types(msgs) != "confirm_request" {
+		t.Fatalf("expected a confirm_request, got %s", body)
+	}
+
+	// Expired actions are swept
+	srv.evictStaleHTTPState(time.Now().Add(11 * time.Minute))
+	if n := countMap(&srv.actionSessions); n != 0 {
+		t.Fatalf("expected the expired action to be evicted, got %d", n)
+	}
+	if code, _ := call(t, ts, "POST", "/v1/actions/"+msgs[0].ActionID+"/confirm", "alice", "", ""); code != http.StatusNotFound {
+		t.Errorf("expected 404 for an evicted action, got %d", code)
+	}
+
+	// An expired action found before a sweep is dropped on lookup
+	srv.actionSessions.Store("stale", actionSession{conversationID: id, expiresAt: time.Now().Add(-time.Minute).Unix()})
+	code, body := call(t, ts, "POST", "/v1/actions/stale/confirm", "alice", "", "")
+	if msgs := events(t, body); code != http.StatusOK || types(msgs) != "text,complete" || !strings.Contains(msgs[0].Content, "expired") {
+		t.Errorf("confirm = %d %s", code, body)
+	}
+	if _, ok := srv.actionSessions.Load("stale"); ok {
+		t.Error("expected the expired action to be dropped")
+	}
+	if len(*sent) != 0 {
+		t.Errorf("expected nothing to be sent, got %v", *sent)
+	}
+}
@@ -0,0 +1,286 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// defaultShutdownTimeout bounds how long Serve waits for in-flight work.
+const defaultShutdownTimeout = 30 * time.Second
+
+// Mux returns the server's HTTP routes: /ws, /v1/, /health, /readyz and
+// /debug/status.
+// Callers may register additional handlers on it before calling Serve or Run.
+func (s *Server) Mux() *http.ServeMux {
+	return s.mux
+}
+
+func (s *Server) newMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/ws", s.Handler())
+	mux.Handle("/v1/", s.HTTPHandler())
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/readyz", s.handleReadyz)
+	mux.HandleFunc("/debug/status", s.handleDebugStatus)
+	return mux
+}
+
+// Serve accepts connections on ln until ctx is cancelled, then shuts down
+// gracefully:
+//   - the listener is closed so no new connections are accepted;
+//   - connected WebSocket clients are sent a "reconnect" message and closed
+//     once their current turn finishes;
+//   - running agent turns, confirmed tool executions and background title
+//     generation are given up to Config.ShutdownTimeout to complete.
+//
+// Serve returns nil after a clean shutdown, or the error that stopped it.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	httpServer := &http.Server{
+		Handler:           s.mux,
+		TLSConfig:         s.config.TLSConfig,
+		ReadTimeout:       s.config.ReadTimeout,
+		ReadHeaderTimeout: s.config.ReadHeaderTimeout,
+		WriteTimeout:      s.config.WriteTimeout,
+		IdleTimeout:       s.config.IdleTimeout,
+		BaseContext: func(net.Listener) context.Context {
+			// Requests outlive ctx so in-flight turns can drain
+			return context.WithoutCancel(ctx)
+		},
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		var err error
+		if s.config.TLSConfig != nil || s.config.TLSCertFile != "" {
+			err = httpServer.ServeTLS(ln, s.config.TLSCertFile, s.config.TLSKeyFile)
+		} else {
+			err = httpServer.Serve(ln)
+		}
+		errCh <- err
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	timeout := s.config.ShutdownTimeout
+	if timeout == 0 {
+		timeout = defaultShutdownTimeout
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	log.Printf("Shutting down: draining in-flight work (timeout %s)", timeout)
+	s.Shutdown(shutdownCtx, httpServer)
+
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown asks WebSocket clients to reconnect, stops httpServer from
+// accepting connections and waits for in-flight work until ctx expires.
+// httpServer may be nil when the server's handlers are mounted elsewhere.
+func (s *Server) Shutdown(ctx context.Context, httpServer *http.Server) error {
+	s.runs.drain()
+
+	// Hijacked WebSocket connections are not tracked by http.Server, so tell
+	// them first rather than after it has waited out in-flight HTTP requests
+	s.clients.Range(func(key, value interface{}) bool {
+		value.(*wsClient).requestReconnect()
+		return true
+	})
+
+	var err error
+	if httpServer != nil {
+		err = httpServer.Shutdown(ctx)
+	}
+
+	if waitErr := s.runs.wait(ctx); waitErr != nil {
+		log.Printf("Shutdown deadline reached with %d runs in flight", s.runs.active())
+		err = waitErr
+	}
+
+	// Close anything still connected now that turns have finished
+	s.clients.Range(func(key, value interface{}) bool {
+		value.(*wsClient).close()
+		return true
+	})
+	return err
+}
+
+// checkOrigin enforces Config.AllowedOrigins for WebSocket upgrades.
+// Requests without an Origin header come from non-browser clients and are allowed.
+func (s *Server) checkOrigin(r *http.Request) bool {
+	if len(s.config.AllowedOrigins) == 0 {
+		return true // Allow all origins in development
+	}
+
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	for _, allowed := range s.config.AllowedOrigins {
+		switch {
+		case allowed == "*":
+			return true
+		case strings.EqualFold(allowed, origin):
+			return true
+		case strings.HasPrefix(allowed, "*."):
+			// Wildcard subdomain, e.g. "*.liminal.cash"
+			if strings.HasSuffix(strings.ToLower(u.Hostname()), strings.ToLower(allowed[1:])) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// runTracker counts in-flight work and supports draining.
+// Unlike sync.WaitGroup, begin may race with wait: once draining starts,
+// begin refuses new work instead of panicking.
+type runTracker struct {
+	mu       sync.Mutex
+	count    int
+	draining bool
+	idle     chan struct{} // closed when count reaches zero while draining
+}
+
+// begin registers a unit of work. It returns false once draining has started.
+func (t *runTracker) begin() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.draining {
+		return false
+	}
+	t.count++
+	return true
+}
+
+// end marks a unit of work as finished.
+func (t *runTracker) end() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.count--
+	if t.count == 0 && t.idle != nil {
+		close(t.idle)
+		t.idle = nil
+	}
+}
+
+// drain stops new work from starting.
+func (t *runTracker) drain() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.draining = true
+}
+
+// isDraining reports whether drain has been called.
+func (t *runTracker) isDraining() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.draining
+}
+
+// active returns the number of in-flight units of work.
+func (t *runTracker) active() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.count
+}
+
+// wait blocks until all work has finished or ctx is done.
+func (t *runTracker) wait(ctx context.Context) error {
+	t.mu.Lock()
+	if t.count == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	if t.idle == nil {
+		t.idle = make(chan struct{})
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// wsClient is a connected WebSocket client.
+type wsClient struct {
+	sink *wsSink
+
+	mu   sync.Mutex
+	busy bool // a turn is running on this connection
+	bye  bool // shutdown requested; close once idle
+}
+
+// setBusy records whether a turn is running. When a turn ends after shutdown
+// was requested, the connection is closed.
+func (c *wsClient) setBusy(busy bool) {
+	c.mu.Lock()
+	c.busy = busy
+	closeNow := !busy && c.bye
+	c.mu.Unlock()
+
+	if closeNow {
+		c.close()
+	}
+}
+
+// requestReconnect tells the client to reconnect elsewhere. Idle connections
+// are closed immediately; busy ones after their turn completes.
+func (c *wsClient) requestReconnect() {
+	c.mu.Lock()
+	if c.bye {
+		c.mu.Unlock()
+		return
+	}
+	c.bye = true
+	busy := c.busy
+	c.mu.Unlock()
+
+	c.sink.Send(ServerMessage{
+		Type:    "reconnect",
+		Content: "Server is shutting down. Please reconnect.",
+	})
+	if !busy {
+		c.close()
+	}
+}
+
+// close sends a "service restart" close frame and closes the connection.
+func (c *wsClient) close() {
+	c.sink.mu.Lock()
+	defer c.sink.mu.Unlock()
+	c.sink.conn.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseServiceRestart, "server shutting down"),
+		time.Now().Add(time.Second),
+	)
+	c.sink.conn.Close()
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/becomeliminal/nim-go-sdk/anthropictest"
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+func TestCheckOrigin(t *testing.T) {
+	srv, err := New(Config{
+		AnthropicKey:   "test-key",
+		AllowedOrigins: []string{"https://app.example.com", "*.example.org"},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	for origin, want := range map[string]bool{
+		"":                        true, // non-browser client
+		"https://app.example.com": true,
+		"HTTPS://APP.EXAMPLE.COM": true,
+		"https://example.com":     false,
+		"https://a.example.org":   true,
+		"https://a.b.Example.org": true,
+		"https://example.org":     false,
+		"https://evilexample.org": false,
+		"https://evil.com":        false,
+		"http://%zz":              false,
+	} {
+		r := httptest.NewRequest("GET", "/ws", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		if got := srv.checkOrigin(r); got != want {
+			t.Errorf("checkOrigin(%q) = %v, want %v", origin, got, want)
+		}
+	}
+
+	r := httptest.NewRequest("GET", "/ws", nil)
+	r.Header.Set("Origin", "https://evil.com")
+	srv.config.AllowedOrigins = nil
+	if !srv.checkOrigin(r) {
+		t.Error("expected all origins to be allowed without AllowedOrigins")
+	}
+	srv.config.AllowedOrigins = []string{"*"}
+	if !srv.checkOrigin(r) {
+		t.Error("expected * to allow all origins")
+	}
+}
+
+var registerDefaultHandler sync.Once
+
+func TestServeShutdown(t *testing.T) {
+	srv, _, _ := newHTTPTestServer(t, Config{DisableAutoTitle: true, ShutdownTimeout: 5 * time.Second},
+		anthropictest.ToolUse("slow", map[string]interface{}{}),
+		anthropictest.Text("Done."),
+	)
+	started, release := make(chan struct{}), make(chan struct{})
+	srv.AddTool(core.NewBaseTool(core.ToolDefinition{ToolName: "slow"}, func(ctx context.Context, p *core.ToolParams) (*core.ToolResult, error) {
+		close(started)
+		<-release
+		return &core.ToolResult{Success: true}, nil
+	}))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.Serve(ctx, ln) }()
+
+	ts := &httptest.Server{URL: "http://" + ln.Addr().String()}
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	// Handlers on http.DefaultServeMux aren't exposed
+	registerDefaultHandler.Do(func() {
+		http.HandleFunc("/lifecycle-test", func(w http.ResponseWriter, r *http.Request) {})
+	})
+	if code, _ := call(t, ts, "GET", "/lifecycle-test", "alice", "", ""); code != http.StatusNotFound {
+		t.Errorf("expected 404 for a DefaultServeMux route, got %d", code)
+	}
+
+	header := http.Header{"X-User": {"alice"}}
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+ln.Addr().String()+"/ws", header)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	// Start a turn that blocks in a tool
+	id := createConversation(t, ts, "alice")
+	turn := make(chan string, 1)
+	go func() {
+		_, body := call(t, ts, "POST", "/v1/conversations/"+id+"/messages", "alice", "", `{"content":"go"}`)
+		turn <- body
+	}()
+	<-started
+
+	cancel()
+
+	// WebSocket clients hear about the shutdown while the HTTP turn runs
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg ServerMessage
+	if err := conn.ReadJSON(&msg); err != nil || msg.Type != "reconnect" {
+		t.Fatalf("expected a reconnect message, got %+v, %v", msg, err)
+	}
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseServiceRestart) {
+		t.Errorf("expected the idle connection to be closed, got %v", err)
+	}
+
+	// New connections are refused while the turn drains
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		resp, err := client.Get(ts.URL + "/health")
+		if err != nil {
+			break
+		}
+		resp.Body.Close()
+		if time.Now().After(deadline) {
+			t.Fatal("expected the listener to close")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case err := <-serveErr:
+		t.Fatalf("Serve returned before the turn finished: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if msgs := events(t, <-turn); types(msgs) != "text,complete" || msgs[0].Content != "Done." {
+		t.Errorf("expected the running turn to complete, got %+v", msgs)
+	}
+	select {
+	case err := <-serveErr:
+		if err != nil {
+			t.Errorf("Serve returned %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve didn't return after the drain")
+	}
+}
@@ -1,25 +1,40 @@
-// Package server provides a ready-to-run WebSocket server for the Nim agent.
+// Package server provides a ready-to-run WebSocket and HTTP server for the Nim agent.
 package server
 
 // ClientMessage is a message from the client.
 type ClientMessage struct {
-	Type           string `json:"type"` // "new_conversation", "resume_conversation", "message", "confirm", "cancel"
+	Type           string `json:"type"` // "new_conversation", "resume_conversation", "message", "confirm", "cancel", "list_conversations", "get_conversation", "rename_conversation", "delete_conversation", "list_pending_actions"
 	Content        string `json:"content,omitempty"`
 	ActionID       string `json:"actionId,omitempty"`
 	ConversationID string `json:"conversationId,omitempty"`
+	Title          string `json:"title,omitempty"`  // rename_conversation
+	Limit          int    `json:"limit,omitempty"`  // list_conversations page size
+	Cursor         string `json:"cursor,omitempty"` // list_conversations page token
 }
 
 // ServerMessage is a message to the client.
 type ServerMessage struct {
-	Type           string      `json:"type"` // "conversation_started", "conversation_resumed", "text", "text_chunk", "confirm_request", "complete", "error"
-	Content        string      `json:"content,omitempty"`
-	ActionID       string      `json:"actionId,omitempty"`
-	Tool           string      `json:"tool,omitempty"`
-	Summary        string      `json:"summary,omitempty"`
-	ExpiresAt      string      `json:"expiresAt,omitempty"`
-	ConversationID string      `json:"conversationId,omitempty"`
-	Messages       interface{} `json:"messages,omitempty"`
-	TokenUsage     *TokenUsage `json:"tokenUsage,omitempty"`
+	Type           string                `json:"type"` // "conversation_started", "conversation_resumed", "text", "text_chunk", "confirm_request", "complete", "error", "conversations", "conversation", "conversation_renamed", "conversation_deleted", "pending_actions", "title_updated", "reconnect"
+	Content        string                `json:"content,omitempty"`
+	ActionID       string                `json:"actionId,omitempty"`
+	Tool           string                `json:"tool,omitempty"`
+	Summary        string                `json:"summary,omitempty"`
+	ExpiresAt      string                `json:"expiresAt,omitempty"`
+	ConversationID string                `json:"conversationId,omitempty"`
+	Title          string                `json:"title,omitempty"`
+	Messages       interface{}           `json:"messages,omitempty"`
+	Conversations  []ConversationSummary `json:"conversations,omitempty"`
+	NextCursor     string                `json:"nextCursor,omitempty"`
+	PendingActions []Confirmation        `json:"pendingActions,omitempty"`
+	TokenUsage     *TokenUsage           `json:"tokenUsage,omitempty"`
+}
+
+// EventsResponse is the JSON body returned by the HTTP API when the client
+// does not ask for Server-Sent Events. Events holds the messages the
+// WebSocket handler would have sent, in order.
+type EventsResponse struct {
+	ConversationID string          `json:"conversationId,omitempty"`
+	Events         []ServerMessage `json:"events"`
 }
 
 // TokenUsage tracks Claude API token consumption.
@@ -31,6 +46,14 @@ type TokenUsage struct {
 	TotalTokens              int `json:"totalTokens"`
 }
 
+// ConversationSummary describes a conversation in a list_conversations response.
+type ConversationSummary struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	CreatedAt int64  `json:"createdAt"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
 // Confirmation contains details about a pending action.
 type Confirmation struct {
 	ID        string `json:"id"`
@@ -0,0 +1,205 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+// newTestServer starts a server whose AuthFunc takes the user ID from the
+// "user" query parameter.
+func newTestServer(t *testing.T) (*Server, *httptest.Server) {
+	t.Helper()
+	srv, err := New(Config{
+		AnthropicKey: "test-key",
+		AuthFunc: func(r *http.Request) (string, error) {
+			user := r.URL.Query().Get("user")
+			if user == "" {
+				return "", fmt.Errorf("missing user")
+			}
+			return user, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+	return srv, ts
+}
+
+func dial(t *testing.T, ts *httptest.Server, user string) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "?user=" + user
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func roundTrip(t *testing.T, conn *websocket.Conn, msg ClientMessage) ServerMessage {
+	t.Helper()
+	if err := conn.WriteJSON(msg); err != nil {
+		t.Fatalf("write %s failed: %v", msg.Type, err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var resp ServerMessage
+	if err := conn.ReadJSON(&resp); err != nil {
+		t.Fatalf("read response to %s failed: %v", msg.Type, err)
+	}
+	return resp
+}
+
+func newConversation(t *testing.T, conn *websocket.Conn) string {
+	t.Helper()
+	resp := roundTrip(t, conn, ClientMessage{Type: "new_conversation"})
+	if resp.Type != "conversation_started" || resp.ConversationID == "" {
+		t.Fatalf("unexpected new_conversation response: %+v", resp)
+	}
+	return resp.ConversationID
+}
+
+func TestListConversationsPaging(t *testing.T) {
+	_, ts := newTestServer(t)
+	conn := dial(t, ts, "alice")
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		ids = append(ids, newConversation(t, conn))
+	}
+
+	resp := roundTrip(t, conn, ClientMessage{Type: "list_conversations", Limit: 2})
+	if resp.Type != "conversations" {
+		t.Fatalf("expected conversations, got %+v", resp)
+	}
+	if len(resp.Conversations) != 2 || resp.Conversations[0].ID != ids[4] || resp.Conversations[1].ID != ids[3] {
+		t.Fatalf("unexpected first page: %+v", resp.Conversations)
+	}
+	if resp.NextCursor == "" {
+		t.Fatalf("expected a next cursor on the first page")
+	}
+
+	var seen []string
+	cursor := ""
+	for {
+		page := roundTrip(t, conn, ClientMessage{Type: "list_conversations", Limit: 2, Cursor: cursor})
+		for _, c := range page.Conversations {
+			seen = append(seen, c.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if len(seen) != len(ids) {
+		t.Fatalf("paging returned %d conversations, want %d", len(seen), len(ids))
+	}
+
+	resp = roundTrip(t, conn, ClientMessage{Type: "list_conversations", Cursor: "bogus"})
+	if resp.Type != "error" {
+		t.Fatalf("expected error for invalid cursor, got %+v", resp)
+	}
+}
+
+func TestConversationOwnership(t *testing.T) {
+	_, ts := newTestServer(t)
+	alice := dial(t, ts, "alice")
+	mallory := dial(t, ts, "mallory")
+
+	id := newConversation(t, alice)
+
+	for _, msg := range []ClientMessage{
+		{Type: "get_conversation", ConversationID: id},
+		{Type: "rename_conversation", ConversationID: id, Title: "pwned"},
+		{Type: "delete_conversation", ConversationID: id},
+		{Type: "resume_conversation", ConversationID: id},
+	} {
+		resp := roundTrip(t, mallory, msg)
+		if resp.Type != "error" || resp.Content != "Conversation not found" {
+			t.Errorf("%s by another user: expected not found, got %+v", msg.Type, resp)
+		}
+	}
+
+	resp := roundTrip(t, mallory, ClientMessage{Type: "list_conversations"})
+	if len(resp.Conversations) != 0 {
+		t.Errorf("expected mallory to see no conversations, got %+v", resp.Conversations)
+	}
+
+	resp = roundTrip(t, alice, ClientMessage{Type: "get_conversation", ConversationID: id})
+	if resp.Type != "conversation" || resp.ConversationID != id || resp.Title != "New conversation" {
+		t.Errorf("unexpected get_conversation response: %+v", resp)
+	}
+}
+
+func TestRenameAndDeleteConversation(t *testing.T) {
+	_, ts := newTestServer(t)
+	conn := dial(t, ts, "alice")
+	id := newConversation(t, conn)
+
+	resp := roundTrip(t, conn, ClientMessage{Type: "rename_conversation", ConversationID: id, Title: "  "})
+	if resp.Type != "error" {
+		t.Fatalf("expected error for blank title, got %+v", resp)
+	}
+
+	resp = roundTrip(t, conn, ClientMessage{Type: "rename_conversation", ConversationID: id, Title: "Rent payments"})
+	if resp.Type != "conversation_renamed" || resp.Title != "Rent payments" {
+		t.Fatalf("unexpected rename response: %+v", resp)
+	}
+
+	resp = roundTrip(t, conn, ClientMessage{Type: "get_conversation", ConversationID: id})
+	if resp.Title != "Rent payments" {
+		t.Fatalf("rename not persisted: %+v", resp)
+	}
+
+	resp = roundTrip(t, conn, ClientMessage{Type: "delete_conversation", ConversationID: id})
+	if resp.Type != "conversation_deleted" || resp.ConversationID != id {
+		t.Fatalf("unexpected delete response: %+v", resp)
+	}
+
+	// The deleted conversation was active, so messages need a new one.
+	resp = roundTrip(t, conn, ClientMessage{Type: "message", Content: "hello"})
+	if resp.Type != "error" || !strings.Contains(resp.Content, "No active conversation") {
+		t.Fatalf("expected no active conversation, got %+v", resp)
+	}
+
+	resp = roundTrip(t, conn, ClientMessage{Type: "get_conversation", ConversationID: id})
+	if resp.Type != "error" {
+		t.Fatalf("expected deleted conversation to be gone, got %+v", resp)
+	}
+}
+
+func TestListPendingActions(t *testing.T) {
+	srv, ts := newTestServer(t)
+	conn := dial(t, ts, "alice")
+
+	now := time.Now()
+	actions := []*core.PendingAction{
+		{ID: "a1", UserID: "alice", Tool: "send_money", Summary: "Send 5 USD to @bob", CreatedAt: now.Unix(), ExpiresAt: now.Add(time.Minute).Unix()},
+		{ID: "a2", UserID: "alice", Tool: "deposit_savings", Summary: "Deposit 10 USD", CreatedAt: now.Unix() + 1, ExpiresAt: now.Add(time.Minute).Unix()},
+		{ID: "b1", UserID: "bob", Tool: "send_money", Summary: "Send 1 USD to @alice", CreatedAt: now.Unix(), ExpiresAt: now.Add(time.Minute).Unix()},
+		{ID: "a3", UserID: "alice", Tool: "send_money", Summary: "expired", CreatedAt: now.Unix(), ExpiresAt: now.Add(-time.Minute).Unix()},
+	}
+	for _, a := range actions {
+		if err := srv.confirmations.Store(context.Background(), a); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+
+	resp := roundTrip(t, conn, ClientMessage{Type: "list_pending_actions"})
+	if resp.Type != "pending_actions" {
+		t.Fatalf("expected pending_actions, got %+v", resp)
+	}
+	if len(resp.PendingActions) != 2 || resp.PendingActions[0].ID != "a1" || resp.PendingActions[1].ID != "a2" {
+		t.Fatalf("unexpected pending actions: %+v", resp.PendingActions)
+	}
+}
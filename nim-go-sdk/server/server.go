@@ -2,11 +2,17 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/anthropics/anthropic-sdk-go"
@@ -72,6 +78,59 @@ type Config struct {
 	// When true, uses the non-streaming Messages.New() API instead of NewStreaming().
 	// Useful for testing with mock servers that don't support SSE.
 	DisableStreaming bool
+
+	// DisableAutoTitle disables automatic conversation titling.
+	// By default, the server generates a title in the background after the
+	// first complete exchange, saves it, and sends a "title_updated" message
+	// to WebSocket clients. HTTP clients read it from GET /v1/conversations/{id}.
+	DisableAutoTitle bool
+
+	// TitleModel is the Claude model used for title generation.
+	// If empty, a small, fast model is used.
+	TitleModel string
+
+	// TitleRateLimit is the maximum number of titles generated per user per hour.
+	// If zero, defaults to 30. A negative value disables the limit.
+	TitleRateLimit int
+
+	// AllowedOrigins restricts which browser origins may open WebSocket connections.
+	// Entries are exact origins ("https://app.liminal.cash"), wildcard subdomains
+	// ("*.liminal.cash") or "*". If empty, all origins are allowed (development only).
+	AllowedOrigins []string
+
+	// TLSConfig enables TLS in Run and Serve when set.
+	TLSConfig *tls.Config
+
+	// TLSCertFile and TLSKeyFile enable TLS in Run and Serve using the given
+	// certificate and key files. Ignored if TLSConfig already has certificates.
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// ReadTimeout, ReadHeaderTimeout, WriteTimeout and IdleTimeout configure
+	// the underlying http.Server used by Run and Serve. Zero means no timeout.
+	// WebSocket connections are not subject to these once upgraded.
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+
+	// HTTPSessionIdleTimeout evicts HTTP conversation sessions that have not
+	// been used for this long. An evicted conversation is restored from the
+	// conversation store on its next request. Defaults to 30 minutes.
+	HTTPSessionIdleTimeout time.Duration
+
+	// ShutdownTimeout bounds how long Serve waits for in-flight agent runs
+	// and tool executions during shutdown. Defaults to 30 seconds.
+	ShutdownTimeout time.Duration
+
+	// HealthCheckers are additional dependencies probed by /readyz, keyed by name
+	// (e.g. a database). The stores and LiminalExecutor are probed automatically
+	// when they implement core.HealthChecker.
+	HealthCheckers map[string]core.HealthChecker
+
+	// AdminAuthFunc authorizes requests to /debug/status.
+	// If nil, /debug/status is disabled.
+	AdminAuthFunc func(r *http.Request) error
 }
 
 // Server is a WebSocket server for the Nim agent.
@@ -84,14 +143,29 @@ type Server struct {
 	conversations store.Conversations
 	confirmations store.Confirmations
 	sessions      sync.Map // *websocket.Conn -> *session
+	titles        *titleLimiter
+	mux           *http.ServeMux
+	startedAt     time.Time
+
+	// Lifecycle state (see lifecycle.go)
+	runs    runTracker // in-flight turns, tool executions and title generation
+	clients sync.Map   // *websocket.Conn -> *wsClient
+
+	// HTTP transport state (see http.go)
+	httpSessions   sync.Map // conversationID -> *session
+	actionSessions sync.Map // actionID -> actionSession
+	lastHTTPSweep  atomic.Int64
 }
 
 type session struct {
+	mu             sync.Mutex // serialises turns from concurrent HTTP requests
 	ID             string
 	UserID         string
 	ConversationID string
 	History        []core.Message
 	TurnCount      int
+	titleRequested bool
+	lastUsed       atomic.Int64 // unix nanoseconds; HTTP sessions only
 }
 
 // New creates a new server with the given configuration.
@@ -125,6 +199,9 @@ func New(cfg Config) (*Server, error) {
 	if cfg.AuditLogger != nil {
 		engineOpts = append(engineOpts, engine.WithAudit(cfg.AuditLogger))
 	}
+	if cfg.TitleModel != "" {
+		engineOpts = append(engineOpts, engine.WithTitleModel(cfg.TitleModel))
+	}
 
 	// Create engine
 	eng := engine.NewEngine(&client, registry, engineOpts...)
@@ -140,18 +217,26 @@ func New(cfg Config) (*Server, error) {
 		confirmations = store.NewMemoryConfirmations()
 	}
 
-	return &Server{
+	titleRateLimit := cfg.TitleRateLimit
+	if titleRateLimit == 0 {
+		titleRateLimit = defaultTitleRateLimit
+	}
+
+	s := &Server{
 		config:        cfg,
 		engine:        eng,
 		registry:      registry,
 		conversations: conversations,
 		confirmations: confirmations,
-		upgrader: websocket.Upgrader{
-			CheckOrigin: func(r *http.Request) bool {
-				return true // Allow all origins in development
-			},
-		},
-	}, nil
+		titles:        newTitleLimiter(titleRateLimit, titleRateWindow),
+		startedAt:     time.Now(),
+	}
+	s.upgrader = websocket.Upgrader{
+		CheckOrigin: s.checkOrigin,
+	}
+	s.mux = s.newMux()
+
+	return s, nil
 }
 
 // AddTool registers a custom tool with the server.
@@ -164,6 +249,25 @@ func (s *Server) AddTools(tools ...core.Tool) {
 	s.registry.RegisterAll(tools...)
 }
 
+// UseToolMiddleware wraps every tool's execution, e.g.
+//
+//	srv.UseToolMiddleware(engine.Recover(), engine.Logging(nil), engine.TruncateResults(16<<10))
+func (s *Server) UseToolMiddleware(middleware ...engine.ToolMiddleware) {
+	s.registry.Use(middleware...)
+}
+
+// UseToolMiddlewareFor wraps one tool's execution, e.g.
+//
+//	srv.UseToolMiddlewareFor("get_transactions", engine.Timeout(5*time.Second))
+func (s *Server) UseToolMiddlewareFor(name string, middleware ...engine.ToolMiddleware) {
+	s.registry.UseFor(name, middleware...)
+}
+
+// Registry returns the server's tool registry, e.g. for mcp.Client.Sync.
+func (s *Server) Registry() *engine.ToolRegistry {
+	return s.registry
+}
+
 // ToolCount returns the number of registered tools.
 func (s *Server) ToolCount() int {
 	return s.registry.Count()
@@ -174,16 +278,19 @@ func (s *Server) Handler() http.Handler {
 	return http.HandlerFunc(s.HandleWebSocket)
 }
 
-// Run starts the server on the given address.
+// Run starts the server on the given address and shuts down gracefully on
+// SIGINT or SIGTERM. See Serve for shutdown behaviour.
 func (s *Server) Run(addr string) error {
-	http.Handle("/ws", s.Handler())
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("ok"))
-	})
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
 
 	log.Printf("Starting Nim agent server on %s", addr)
-	return http.ListenAndServe(addr, nil)
+	return s.Serve(ctx, ln)
 }
 
 // defaultLiminalAuthFunc returns a default authentication function for Liminal.
@@ -209,9 +316,9 @@ func (s *Server) defaultLiminalAuthFunc() func(r *http.Request) (string, error)
 	}
 }
 
-func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
-	// Authenticate
-	userID := "default-user"
+// authenticate resolves the user ID for a request.
+// Both the WebSocket and HTTP transports share this logic.
+func (s *Server) authenticate(r *http.Request) (string, error) {
 	authFunc := s.config.AuthFunc
 
 	// Use default Liminal JWT handler if no custom auth provided
@@ -219,13 +326,18 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		authFunc = s.defaultLiminalAuthFunc()
 	}
 
-	if authFunc != nil {
-		var err error
-		userID, err = authFunc(r)
-		if err != nil {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
-		}
+	if authFunc == nil {
+		return "default-user", nil
+	}
+	return authFunc(r)
+}
+
+func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	// Authenticate
+	userID, err := s.authenticate(r)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
 	}
 
 	// Upgrade connection
@@ -235,9 +347,15 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	defer conn.Close()
+	defer s.sessions.Delete(conn)
 
 	log.Printf("WebSocket connected for user %s", userID)
 
+	out := &wsSink{conn: conn}
+	client := &wsClient{sink: out}
+	s.clients.Store(conn, client)
+	defer s.clients.Delete(conn)
+
 	var currentSession *session
 
 	for {
@@ -251,7 +369,7 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 
 		var msg ClientMessage
 		if err := json.Unmarshal(msgBytes, &msg); err != nil {
-			s.sendError(conn, "Invalid message format")
+			s.sendError(out, "Invalid message format")
 			continue
 		}
 
@@ -259,42 +377,88 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 
 		switch msg.Type {
 		case "new_conversation":
-			currentSession = s.handleNewConversation(r.Context(), conn, userID)
+			currentSession = s.handleNewConversation(r.Context(), out, userID)
+			if currentSession != nil {
+				s.sessions.Store(conn, currentSession)
+			}
 
 		case "resume_conversation":
-			currentSession = s.handleResumeConversation(r.Context(), conn, userID, msg.ConversationID)
+			currentSession = s.handleResumeConversation(r.Context(), out, userID, msg.ConversationID)
+			if currentSession != nil {
+				s.sessions.Store(conn, currentSession)
+			}
 
 		case "message":
 			if currentSession == nil {
-				s.sendError(conn, "No active conversation. Send 'new_conversation' first.")
+				s.sendError(out, "No active conversation. Send 'new_conversation' first.")
 				continue
 			}
-			s.handleMessage(r.Context(), conn, currentSession, msg.Content)
+			s.runTurn(out, client, func() {
+				s.handleMessage(r.Context(), out, currentSession, msg.Content)
+			})
 
 		case "confirm":
 			if currentSession == nil {
-				s.sendError(conn, "No active conversation")
+				s.sendError(out, "No active conversation")
 				continue
 			}
-			s.handleConfirm(r.Context(), conn, currentSession, userID, msg.ActionID)
+			s.runTurn(out, client, func() {
+				s.handleConfirm(r.Context(), out, currentSession, userID, msg.ActionID)
+			})
 
 		case "cancel":
 			if currentSession == nil {
-				s.sendError(conn, "No active conversation")
+				s.sendError(out, "No active conversation")
 				continue
 			}
-			s.handleCancel(r.Context(), conn, currentSession, userID, msg.ActionID)
+			s.runTurn(out, client, func() {
+				s.handleCancel(r.Context(), out, currentSession, userID, msg.ActionID)
+			})
+
+		case "list_conversations":
+			s.handleListConversations(r.Context(), out, userID, msg.Limit, msg.Cursor)
+
+		case "get_conversation":
+			s.handleGetConversation(r.Context(), out, userID, msg.ConversationID)
+
+		case "rename_conversation":
+			s.handleRenameConversation(r.Context(), out, userID, msg.ConversationID, msg.Title)
+
+		case "delete_conversation":
+			deleted := s.handleDeleteConversation(r.Context(), out, userID, msg.ConversationID)
+			if deleted && currentSession != nil && currentSession.ConversationID == msg.ConversationID {
+				currentSession = nil
+				s.sessions.Delete(conn)
+			}
+
+		case "list_pending_actions":
+			s.handleListPendingActions(r.Context(), out, userID)
 
 		default:
-			s.sendError(conn, fmt.Sprintf("Unknown message type: %s", msg.Type))
+			s.sendError(out, fmt.Sprintf("Unknown message type: %s", msg.Type))
 		}
 	}
 }
 
-func (s *Server) handleNewConversation(ctx context.Context, conn *websocket.Conn, userID string) *session {
+// runTurn runs a turn on a WebSocket connection, tracking it so shutdown can
+// wait for it. Once shutdown has started, new turns are refused.
+func (s *Server) runTurn(out eventSink, client *wsClient, fn func()) {
+	if !s.runs.begin() {
+		s.sendError(out, "Server is shutting down. Please reconnect.")
+		return
+	}
+	client.setBusy(true)
+	defer func() {
+		s.runs.end()
+		client.setBusy(false)
+	}()
+	fn()
+}
+
+func (s *Server) handleNewConversation(ctx context.Context, out eventSink, userID string) *session {
 	conv, err := s.conversations.Create(ctx, userID)
 	if err != nil {
-		s.sendError(conn, fmt.Sprintf("Failed to create conversation: %v", err))
+		s.sendError(out, fmt.Sprintf("Failed to create conversation: %v", err))
 		return nil
 	}
 
@@ -304,9 +468,7 @@ func (s *Server) handleNewConversation(ctx context.Context, conn *websocket.Conn
 		ConversationID: conv.ID,
 		History:        []core.Message{},
 	}
-	s.sessions.Store(conn, sess)
-
-	s.send(conn, ServerMessage{
+	s.send(out, ServerMessage{
 		Type:           "conversation_started",
 		ConversationID: conv.ID,
 	})
@@ -315,31 +477,20 @@ func (s *Server) handleNewConversation(ctx context.Context, conn *websocket.Conn
 	return sess
 }
 
-func (s *Server) handleResumeConversation(ctx context.Context, conn *websocket.Conn, userID, conversationID string) *session {
-	conv, err := s.conversations.Get(ctx, conversationID)
-	if err != nil {
-		s.sendError(conn, "Conversation not found")
+func (s *Server) handleResumeConversation(ctx context.Context, out eventSink, userID, conversationID string) *session {
+	conv, ok := s.ownedConversation(ctx, userID, conversationID)
+	if !ok {
+		s.sendError(out, "Conversation not found")
 		return nil
 	}
 
-	// Convert stored messages to core.Message
-	history := make([]core.Message, 0, len(conv.Messages))
-	for _, m := range conv.Messages {
-		history = append(history, core.Message{
-			Role:    core.Role(m.Role),
-			Content: m.Content,
-		})
-	}
-
 	sess := &session{
 		ID:             conversationID,
 		UserID:         userID,
 		ConversationID: conversationID,
-		History:        history,
+		History:        historyFromStored(conv.Messages),
 	}
-	s.sessions.Store(conn, sess)
-
-	s.send(conn, ServerMessage{
+	s.send(out, ServerMessage{
 		Type:           "conversation_resumed",
 		ConversationID: conversationID,
 		Messages:       conv.Messages,
@@ -349,7 +500,7 @@ func (s *Server) handleResumeConversation(ctx context.Context, conn *websocket.C
 	return sess
 }
 
-func (s *Server) handleMessage(ctx context.Context, conn *websocket.Conn, sess *session, content string) {
+func (s *Server) handleMessage(ctx context.Context, out eventSink, sess *session, content string) {
 	if content == "" {
 		return
 	}
@@ -376,10 +527,11 @@ func (s *Server) handleMessage(ctx context.Context, conn *websocket.Conn, sess *
 	}
 
 	// Only enable streaming if not disabled (streaming requires SSE-compatible server)
-	if !s.config.DisableStreaming {
+	// and the transport can deliver partial text
+	if !s.config.DisableStreaming && out.Streaming() {
 		input.StreamCallback = func(chunk string, done bool) {
 			if !done && chunk != "" {
-				s.send(conn, ServerMessage{Type: "text_chunk", Content: chunk})
+				s.send(out, ServerMessage{Type: "text_chunk", Content: chunk})
 			}
 		}
 	}
@@ -388,14 +540,14 @@ func (s *Server) handleMessage(ctx context.Context, conn *websocket.Conn, sess *
 	output, err := s.engine.Run(ctx, input)
 	if err != nil {
 		log.Printf("Agent error: %v", err)
-		s.sendError(conn, fmt.Sprintf("Agent error: %v", err))
+		s.sendError(out, fmt.Sprintf("Agent error: %v", err))
 		return
 	}
 
-	s.handleOutput(ctx, conn, sess, output)
+	s.handleOutput(ctx, out, sess, output)
 }
 
-func (s *Server) handleOutput(ctx context.Context, conn *websocket.Conn, sess *session, output *engine.Output) {
+func (s *Server) handleOutput(ctx context.Context, out eventSink, sess *session, output *engine.Output) {
 	switch output.Type {
 	case engine.OutputComplete:
 		log.Printf("[CONVERSATION %s] ASSISTANT: %s", sess.ConversationID, truncate(output.Text, 200))
@@ -404,16 +556,20 @@ func (s *Server) handleOutput(ctx context.Context, conn *websocket.Conn, sess *s
 
 		s.persistMessage(ctx, sess.ConversationID, "assistant", output.Text)
 
-		s.send(conn, ServerMessage{Type: "text", Content: output.Text})
-		s.send(conn, ServerMessage{
+		s.send(out, ServerMessage{Type: "text", Content: output.Text})
+		s.send(out, ServerMessage{
 			Type: "complete",
 			TokenUsage: &TokenUsage{
-				InputTokens:  output.TokensUsed.InputTokens,
-				OutputTokens: output.TokensUsed.OutputTokens,
-				TotalTokens:  output.TokensUsed.TotalTokens(),
+				InputTokens:              output.TokensUsed.InputTokens,
+				OutputTokens:             output.TokensUsed.OutputTokens,
+				CacheCreationInputTokens: output.TokensUsed.CacheCreationInputTokens,
+				CacheReadInputTokens:     output.TokensUsed.CacheReadInputTokens,
+				TotalTokens:              output.TokensUsed.TotalTokens(),
 			},
 		})
 
+		s.maybeGenerateTitle(out, sess)
+
 	case engine.OutputConfirmationNeeded:
 		pending := output.PendingAction
 
@@ -421,10 +577,12 @@ func (s *Server) handleOutput(ctx context.Context, conn *websocket.Conn, sess *s
 		if err := s.confirmations.Store(ctx, pending); err != nil {
 			log.Printf("Failed to store confirmation: %v", err)
 		}
+		s.actionSessions.Store(pending.ID, actionSession{conversationID: sess.ConversationID, expiresAt: pending.ExpiresAt})
+		s.evictStaleHTTPState(time.Now())
 
 		sess.History = append(sess.History, core.NewAssistantMessageWithBlocks(output.ResponseBlocks))
 
-		s.send(conn, ServerMessage{
+		s.send(out, ServerMessage{
 			Type:      "confirm_request",
 			ActionID:  pending.ID,
 			Tool:      pending.Tool,
@@ -435,26 +593,27 @@ func (s *Server) handleOutput(ctx context.Context, conn *websocket.Conn, sess *s
 
 	case engine.OutputError:
 		log.Printf("Agent error: %v", output.Error)
-		s.sendError(conn, output.Error.Error())
+		s.sendError(out, output.Error.Error())
 	}
 }
 
-func (s *Server) handleConfirm(ctx context.Context, conn *websocket.Conn, sess *session, userID, actionID string) {
+func (s *Server) handleConfirm(ctx context.Context, out eventSink, sess *session, userID, actionID string) {
 	log.Printf("Processing confirmation for action=%s, user=%s", actionID, userID)
 
 	// Get and remove confirmation
 	action, err := s.confirmations.Confirm(ctx, userID, actionID)
+	s.actionSessions.Delete(actionID)
 	if err != nil {
-		s.send(conn, ServerMessage{
+		s.send(out, ServerMessage{
 			Type:    "text",
 			Content: "That action expired. Would you like me to set it up again?",
 		})
-		s.send(conn, ServerMessage{Type: "complete"})
+		s.send(out, ServerMessage{Type: "complete"})
 		return
 	}
 
 	// Execute the confirmed tool
-	result, err := s.engine.ExecuteTool(ctx, userID, action.Tool, action.Input, action.ID)
+	result, err := s.engine.ExecuteAction(ctx, action)
 
 	var resultContent string
 	var isError bool
@@ -475,11 +634,11 @@ func (s *Server) handleConfirm(ctx context.Context, conn *websocket.Conn, sess *
 	}))
 
 	if isError {
-		s.send(conn, ServerMessage{
+		s.send(out, ServerMessage{
 			Type:    "text",
 			Content: fmt.Sprintf("Sorry, that action failed: %s", resultContent),
 		})
-		s.send(conn, ServerMessage{Type: "complete"})
+		s.send(out, ServerMessage{Type: "complete"})
 		return
 	}
 
@@ -489,31 +648,32 @@ func (s *Server) handleConfirm(ctx context.Context, conn *websocket.Conn, sess *
 
 	s.persistMessage(ctx, sess.ConversationID, "assistant", resultMsg)
 
-	s.send(conn, ServerMessage{Type: "text", Content: resultMsg})
-	s.send(conn, ServerMessage{Type: "complete"})
+	s.send(out, ServerMessage{Type: "text", Content: resultMsg})
+	s.send(out, ServerMessage{Type: "complete"})
 }
 
-func (s *Server) handleCancel(ctx context.Context, conn *websocket.Conn, sess *session, userID, actionID string) {
+func (s *Server) handleCancel(ctx context.Context, out eventSink, sess *session, userID, actionID string) {
 	// Get action first to have the BlockID for history
 	action, err := s.confirmations.Get(ctx, userID, actionID)
 	if err != nil {
-		s.sendError(conn, "Action not found")
+		s.sendError(out, "Action not found")
 		return
 	}
 
 	// Cancel the action
 	if err := s.confirmations.Cancel(ctx, userID, actionID); err != nil {
-		s.sendError(conn, "Failed to cancel action")
+		s.sendError(out, "Failed to cancel action")
 		return
 	}
+	s.actionSessions.Delete(actionID)
 
 	// Add cancelled tool result to history
 	sess.History = append(sess.History, core.NewToolResultMessage([]core.ToolResultContent{
 		{ToolUseID: action.BlockID, Content: "Cancelled by user", IsError: true},
 	}))
 
-	s.send(conn, ServerMessage{Type: "text", Content: "Action cancelled."})
-	s.send(conn, ServerMessage{Type: "complete"})
+	s.send(out, ServerMessage{Type: "text", Content: "Action cancelled."})
+	s.send(out, ServerMessage{Type: "complete"})
 }
 
 func (s *Server) persistMessage(ctx context.Context, conversationID string, role, content string) {
@@ -527,15 +687,40 @@ func (s *Server) persistMessage(ctx context.Context, conversationID string, role
 	}
 }
 
-func (s *Server) send(conn *websocket.Conn, msg ServerMessage) {
-	if err := conn.WriteJSON(msg); err != nil {
+// eventSink delivers server messages to a client over a specific transport.
+type eventSink interface {
+	// Send writes a single message to the client.
+	Send(msg ServerMessage) error
+
+	// Streaming reports whether the transport can deliver text_chunk events.
+	Streaming() bool
+}
+
+// wsSink writes server messages to a WebSocket connection.
+type wsSink struct {
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+func (w *wsSink) Send(msg ServerMessage) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.conn.WriteJSON(msg)
+}
+
+func (w *wsSink) Streaming() bool {
+	return true
+}
+
+func (s *Server) send(out eventSink, msg ServerMessage) {
+	if err := out.Send(msg); err != nil {
 		log.Printf("Failed to send message: %v", err)
 	}
 }
 
-func (s *Server) sendError(conn *websocket.Conn, content string) {
+func (s *Server) sendError(out eventSink, content string) {
 	log.Printf("Sending error: %s", content)
-	s.send(conn, ServerMessage{Type: "error", Content: content})
+	s.send(out, ServerMessage{Type: "error", Content: content})
 }
 
 func truncate(s string, maxLen int) string {
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/store"
+)
+
+const (
+	// defaultTitleRateLimit is the default number of titles generated per user per window.
+	defaultTitleRateLimit = 30
+
+	// titleRateWindow is the window TitleRateLimit applies to.
+	titleRateWindow = time.Hour
+
+	// titleTimeout bounds a single background title generation.
+	titleTimeout = 30 * time.Second
+)
+
+// titleLimiter is a per-user sliding window rate limiter for title generation.
+type titleLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	calls  map[string][]time.Time // userID -> call times within the window
+}
+
+func newTitleLimiter(limit int, window time.Duration) *titleLimiter {
+	return &titleLimiter{
+		limit:  limit,
+		window: window,
+		calls:  make(map[string][]time.Time),
+	}
+}
+
+// Allow records a call for userID and reports whether it is within the limit.
+// A negative limit disables rate limiting.
+func (l *titleLimiter) Allow(userID string) bool {
+	if l.limit < 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-l.window)
+	recent := l.calls[userID][:0]
+	for _, t := range l.calls[userID] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+
+	if len(recent) >= l.limit {
+		l.calls[userID] = recent
+		return false
+	}
+	l.calls[userID] = append(recent, now)
+	return true
+}
+
+// maybeGenerateTitle titles the session's conversation in the background after
+// its first complete exchange. Conversations that already have a title (from
+// an earlier session or a rename) are left alone.
+//
+// title_updated is only delivered over WebSocket: an HTTP response has
+// completed by the time the title is ready, so HTTP clients read the title
+// from GET /v1/conversations/{id} instead.
+func (s *Server) maybeGenerateTitle(out eventSink, sess *session) {
+	if s.config.DisableAutoTitle || sess.titleRequested {
+		return
+	}
+	sess.titleRequested = true
+
+	if !s.titles.Allow(sess.UserID) {
+		log.Printf("Title generation rate limited for user %s", sess.UserID)
+		return
+	}
+
+	history := make([]core.Message, len(sess.History))
+	copy(history, sess.History)
+	conversationID := sess.ConversationID
+
+	if !s.runs.begin() {
+		return
+	}
+	go func() {
+		defer s.runs.end()
+
+		ctx, cancel := context.WithTimeout(context.Background(), titleTimeout)
+		defer cancel()
+
+		if !s.hasDefaultTitle(ctx, conversationID) {
+			return
+		}
+
+		title, err := s.engine.GenerateTitle(ctx, history)
+		if err != nil {
+			log.Printf("Failed to generate title for conversation %s: %v", conversationID, err)
+			return
+		}
+		if title == "" || title == store.DefaultConversationTitle {
+			return
+		}
+
+		// Re-check in case the user renamed the conversation meanwhile
+		if !s.hasDefaultTitle(ctx, conversationID) {
+			return
+		}
+
+		if err := s.conversations.SetTitle(ctx, conversationID, title); err != nil {
+			log.Printf("Failed to save title for conversation %s: %v", conversationID, err)
+			return
+		}
+
+		err = out.Send(ServerMessage{
+			Type:           "title_updated",
+			ConversationID: conversationID,
+			Title:          title,
+		})
+		if err != nil && !errors.Is(err, errSinkClosed) {
+			log.Printf("Failed to send message: %v", err)
+		}
+	}()
+}
+
+func (s *Server) hasDefaultTitle(ctx context.Context, conversationID string) bool {
+	conv, err := s.conversations.Get(ctx, conversationID)
+	if err != nil {
+		return false
+	}
+	return conv.Title == "" || conv.Title == store.DefaultConversationTitle
+}
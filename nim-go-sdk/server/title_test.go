@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/anthropictest"
+	"github.com/becomeliminal/nim-go-sdk/store"
+)
+
+func TestTitleLimiter(t *testing.T) {
+	l := newTitleLimiter(2, 20*time.Millisecond)
+	if !l.Allow("alice") || !l.Allow("alice") {
+		t.Fatal("expected calls within the limit to be allowed")
+	}
+	if l.Allow("alice") {
+		t.Error("expected the third call to be limited")
+	}
+	if !l.Allow("bob") {
+		t.Error("expected the limit to be per user")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if !l.Allow("alice") {
+		t.Error("expected the window to slide")
+	}
+
+	unlimited := newTitleLimiter(-1, time.Hour)
+	for i := 0; i < 100; i++ {
+		if !unlimited.Allow("alice") {
+			t.Fatal("expected a negative limit to disable rate limiting")
+		}
+	}
+}
+
+// titleOf sends a message on a new conversation, waits for background work
+// and returns the conversation's stored title.
+func titleOf(t *testing.T, srv *Server, ts *httptest.Server) string {
+	t.Helper()
+	id := createConversation(t, ts, "alice")
+	call(t, ts, "POST", "/v1/conversations/"+id+"/messages", "alice", "", `{"content":"What's my balance?"}`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.runs.wait(ctx); err != nil {
+		t.Fatalf("title generation didn't finish: %v", err)
+	}
+
+	_, body := call(t, ts, "GET", "/v1/conversations/"+id, "alice", "", "")
+	var conv store.ConversationWithMessages
+	if err := json.Unmarshal([]byte(body), &conv); err != nil {
+		t.Fatalf("invalid conversation %q: %v", body, err)
+	}
+	return conv.Title
+}
+
+func TestAutoTitle(t *testing.T) {
+	srv, ts, _ := newHTTPTestServer(t, Config{TitleRateLimit: 1},
+		anthropictest.Text("You have 42.00 USD."),
+		anthropictest.Text("Balance check"),
+		anthropictest.Text("You still have 42.00 USD."),
+		anthropictest.Text("Rate limited title"),
+	)
+
+	// HTTP clients see the title through GET
+	if title := titleOf(t, srv, ts); title != "Balance check" {
+		t.Errorf("expected the generated title, got %q", title)
+	}
+	if title := titleOf(t, srv, ts); title != store.DefaultConversationTitle {
+		t.Errorf("expected the second title to be rate limited, got %q", title)
+	}
+}
+
+func TestDisableAutoTitle(t *testing.T) {
+	srv, ts, _ := newHTTPTestServer(t, Config{DisableAutoTitle: true},
+		anthropictest.Text("You have 42.00 USD."),
+		anthropictest.Text("Unwanted title"),
+	)
+
+	if title := titleOf(t, srv, ts); title != store.DefaultConversationTitle {
+		t.Errorf("expected no title to be generated, got %q", title)
+	}
+}
@@ -0,0 +1,605 @@
+// Package simbank provides an offline, in-memory simulation of the Liminal bank.
+//
+// A Bank keeps wallet balances, a transaction ledger, savings positions that
+// accrue interest, and a set of seeded users. It can be exercised two ways:
+//   - over HTTP, via Handler or NewServer, which serve the agent_gateway
+//     endpoints used by executor.HTTPExecutor;
+//   - in-process, via ExecutorConfig, which implements the service
+//     interfaces used by executor.GRPCExecutor.
+//
+// Responses use the exact shapes in the executor package, so both executors
+// can be tested against the same fake without network access.
+package simbank
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core/money"
+	"github.com/becomeliminal/nim-go-sdk/executor"
+)
+
+// Errors returned by bank operations.
+var (
+	ErrUserNotFound      = errors.New("user not found")
+	ErrInvalidAmount     = errors.New("amount must be a positive decimal with at most 2 decimal places")
+	ErrUnknownCurrency   = errors.New("unsupported currency")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrSelfTransfer      = errors.New("cannot send money to yourself")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrInvalidFilter     = errors.New("invalid transaction filter")
+)
+
+// Config configures a simulated bank.
+type Config struct {
+	// Users are the accounts the bank starts with.
+	// If nil, DefaultUsers is used.
+	Users []User
+
+	// Vaults are the savings vaults and their APYs.
+	// If nil, DefaultVaults is used.
+	Vaults []Vault
+
+	// USDRates converts each currency to USD for usdValue fields.
+	// If nil, USD and LIL are 1:1 and EUR is 1.08.
+	USDRates map[string]float64
+
+	// Now returns the current time. Override it to control interest accrual
+	// and transaction timestamps in tests. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+// User is a seeded bank account.
+type User struct {
+	ID         string
+	DisplayTag string // without the leading "@"
+	FirstName  string
+	LastName   string
+	Email      string
+	Phone      string
+
+	// Balances are opening wallet balances by currency, e.g. {"USD": "1000.00"}.
+	// Each is recorded in the ledger as a receipt from @liminal.
+	Balances map[string]string
+
+	// Savings are opening savings deposits by currency.
+	Savings map[string]string
+}
+
+// Vault is a savings vault for one currency.
+type Vault struct {
+	Currency string
+	APY      float64 // annual percentage yield, e.g. 4.5 for 4.5%
+}
+
+// DefaultUsers returns the accounts seeded when Config.Users is nil.
+func DefaultUsers() []User {
+	return []User{
+		{
+			ID: "user_alice", DisplayTag: "alice", FirstName: "Alice", LastName: "Smith",
+			Email: "alice@example.com", Phone: "+15550000001",
+			Balances: map[string]string{"USD": "1250.00", "EUR": "300.00"},
+			Savings:  map[string]string{"USD": "500.00"},
+		},
+		{
+			ID: "user_bob", DisplayTag: "bob", FirstName: "Bob", LastName: "Jones",
+			Email: "bob@example.com", Phone: "+15550000002",
+			Balances: map[string]string{"USD": "80.00"},
+		},
+		{
+			ID: "user_carol", DisplayTag: "carol", FirstName: "Carol", LastName: "Nguyen",
+			Email: "carol@example.com", Phone: "+15550000003",
+			Balances: map[string]string{"USD": "4000.00", "LIL": "150.00"},
+			Savings:  map[string]string{"LIL": "1000.00"},
+		},
+	}
+}
+
+// DefaultVaults returns the vaults available when Config.Vaults is nil.
+func DefaultVaults() []Vault {
+	return []Vault{
+		{Currency: "USD", APY: 4.5},
+		{Currency: "EUR", APY: 3.2},
+		{Currency: "LIL", APY: 6.0},
+	}
+}
+
+// Bank is an in-memory simulated Liminal bank. It is safe for concurrent use.
+type Bank struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	usdRates map[string]float64
+	vaults   map[string]float64 // currency -> APY percent
+	users    map[string]*account
+	order    []string // user IDs in seed order, for deterministic search results
+	txSeq    int
+	staged   map[string]*stagedOp
+}
+
+type account struct {
+	profile  User
+	balances map[string]int64 // currency -> minor units
+	savings  map[string]*position
+	ledger   []executor.Transaction // newest first
+}
+
+type position struct {
+	deposited int64   // minor units
+	value     float64 // minor units, including accrued interest
+	accruedAt time.Time
+}
+
+// New creates a bank seeded from cfg.
+func New(cfg Config) (*Bank, error) {
+	users := cfg.Users
+	if users == nil {
+		users = DefaultUsers()
+	}
+	vaults := cfg.Vaults
+	if vaults == nil {
+		vaults = DefaultVaults()
+	}
+	rates := cfg.USDRates
+	if rates == nil {
+		rates = map[string]float64{"USD": 1, "EUR": 1.08, "LIL": 1}
+	}
+	now := cfg.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	b := &Bank{
+		now:      now,
+		usdRates: rates,
+		vaults:   make(map[string]float64, len(vaults)),
+		users:    make(map[string]*account, len(users)),
+		staged:   make(map[string]*stagedOp),
+	}
+	for _, v := range vaults {
+		b.vaults[strings.ToUpper(v.Currency)] = v.APY
+	}
+
+	seededAt := now()
+	for _, u := range users {
+		if _, exists := b.users[u.ID]; exists {
+			return nil, fmt.Errorf("duplicate user ID: %s", u.ID)
+		}
+		acct := &account{
+			profile:  u,
+			balances: make(map[string]int64),
+			savings:  make(map[string]*position),
+		}
+		b.users[u.ID] = acct
+		b.order = append(b.order, u.ID)
+
+		for _, currency := range sortedKeys(u.Balances) {
+			amount, err := parseAmount(u.Balances[currency])
+			if err != nil {
+				return nil, fmt.Errorf("user %s balance %s: %w", u.ID, currency, err)
+			}
+			currency = strings.ToUpper(currency)
+			acct.balances[currency] += amount
+			b.record(acct, "receive", "incoming", currency, amount, "@liminal", "Opening balance", seededAt)
+		}
+		for _, currency := range sortedKeys(u.Savings) {
+			amount, err := parseAmount(u.Savings[currency])
+			if err != nil {
+				return nil, fmt.Errorf("user %s savings %s: %w", u.ID, currency, err)
+			}
+			currency = strings.ToUpper(currency)
+			if _, ok := b.vaults[currency]; !ok {
+				return nil, fmt.Errorf("user %s savings: %w: %s", u.ID, ErrUnknownCurrency, currency)
+			}
+			acct.savings[currency] = &position{deposited: amount, value: float64(amount), accruedAt: seededAt}
+			b.record(acct, "deposit", "outgoing", currency, amount, "savings", "Opening savings", seededAt)
+		}
+	}
+	return b, nil
+}
+
+// Balance returns a user's wallet balance in a currency as a decimal string.
+// It is intended for test assertions.
+func (b *Bank) Balance(userID, currency string) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	acct, ok := b.users[userID]
+	if !ok {
+		return "", ErrUserNotFound
+	}
+	return formatAmount(acct.balances[strings.ToUpper(currency)]).String(), nil
+}
+
+// GetBalance returns a user's wallet balances, optionally for one currency.
+func (b *Bank) GetBalance(userID, currency string) (*executor.GetBalanceResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	acct, ok := b.users[userID]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+
+	resp := &executor.GetBalanceResponse{Balances: []executor.WalletBalance{}}
+	var totalUSD int64
+	for _, cur := range sortedKeys(acct.balances) {
+		if currency != "" && !strings.EqualFold(cur, currency) {
+			continue
+		}
+		amount := acct.balances[cur]
+		usd := b.toUSD(cur, float64(amount))
+		totalUSD += usd
+		resp.Balances = append(resp.Balances, executor.WalletBalance{
+			Currency: cur,
+			Amount:   formatAmount(amount),
+			USDValue: formatAmount(usd),
+		})
+	}
+	resp.TotalUSD = formatAmount(totalUSD)
+	return resp, nil
+}
+
+// GetSavingsBalance returns a user's savings positions with interest accrued
+// to now, optionally for one vault (identified by currency).
+func (b *Bank) GetSavingsBalance(userID, vault string) (*executor.GetSavingsBalanceResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	acct, ok := b.users[userID]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+
+	now := b.now()
+	resp := &executor.GetSavingsBalanceResponse{Positions: []executor.SavingsPosition{}}
+	var totalUSD int64
+	for _, cur := range sortedKeys(acct.savings) {
+		if vault != "" && !strings.EqualFold(cur, vault) {
+			continue
+		}
+		pos := acct.savings[cur]
+		b.accrue(cur, pos, now)
+		value := int64(math.Round(pos.value))
+		totalUSD += b.toUSD(cur, pos.value)
+		resp.Positions = append(resp.Positions, executor.SavingsPosition{
+			Currency:     cur,
+			Deposited:    formatAmount(pos.deposited),
+			CurrentValue: formatAmount(value),
+			APY:          formatAPY(b.vaults[cur]),
+			Earnings:     formatAmount(value - pos.deposited),
+		})
+	}
+	resp.TotalUSD = formatAmount(totalUSD)
+	return resp, nil
+}
+
+// GetVaultRates returns every vault's APY and total value locked.
+func (b *Bank) GetVaultRates() *executor.GetVaultRatesResponse {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	tvl := make(map[string]float64)
+	for _, acct := range b.users {
+		for cur, pos := range acct.savings {
+			b.accrue(cur, pos, now)
+			tvl[cur] += pos.value
+		}
+	}
+
+	resp := &executor.GetVaultRatesResponse{Vaults: []executor.VaultRate{}}
+	for _, cur := range sortedKeys(b.vaults) {
+		resp.Vaults = append(resp.Vaults, executor.VaultRate{
+			Currency: cur,
+			APY:      formatAPY(b.vaults[cur]),
+			TVL:      formatAmount(int64(math.Round(tvl[cur]))),
+		})
+	}
+	return resp
+}
+
+// TransactionQuery filters a ledger page. It is the get_transactions tool input.
+type TransactionQuery = executor.TransactionQuery
+
+// GetTransactions returns a page of a user's ledger, newest first.
+func (b *Bank) GetTransactions(userID string, q TransactionQuery) (*executor.GetTransactionsResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	acct, ok := b.users[userID]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	if err := q.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	start := 0
+	if q.Cursor != "" {
+		start = -1
+		for i, tx := range acct.ledger {
+			if tx.ID == q.Cursor {
+				start = i + 1
+				break
+			}
+		}
+		if start < 0 {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	resp := &executor.GetTransactionsResponse{Transactions: []executor.Transaction{}}
+	for i := start; i < len(acct.ledger); i++ {
+		tx := acct.ledger[i]
+		if !q.Match(tx) {
+			continue
+		}
+		if len(resp.Transactions) == limit {
+			// Cursors point at the last returned transaction
+			resp.NextCursor = resp.Transactions[limit-1].ID
+			break
+		}
+		resp.Transactions = append(resp.Transactions, tx)
+	}
+	return resp, nil
+}
+
+// GetProfile returns a user's profile.
+func (b *Bank) GetProfile(userID string) (*executor.GetProfileResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	acct, ok := b.users[userID]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	p := acct.profile
+	return &executor.GetProfileResponse{
+		UserID:     p.ID,
+		DisplayTag: "@" + p.DisplayTag,
+		FirstName:  p.FirstName,
+		LastName:   p.LastName,
+		Email:      p.Email,
+		Phone:      p.Phone,
+	}, nil
+}
+
+// SearchUsers finds users whose display tag or name contains query.
+func (b *Bank) SearchUsers(query string) *executor.SearchUsersResponse {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(query), "@"))
+	resp := &executor.SearchUsersResponse{Users: []executor.UserResult{}}
+	for _, id := range b.order {
+		p := b.users[id].profile
+		name := p.FirstName + " " + p.LastName
+		if q != "" && !strings.Contains(strings.ToLower(p.DisplayTag), q) && !strings.Contains(strings.ToLower(name), q) {
+			continue
+		}
+		resp.Users = append(resp.Users, executor.UserResult{
+			UserID:     p.ID,
+			DisplayTag: "@" + p.DisplayTag,
+			Name:       name,
+		})
+	}
+	return resp
+}
+
+// SendMoney moves amount of currency from userID's wallet to recipient,
+// identified by display tag (with or without "@") or user ID.
+func (b *Bank) SendMoney(userID, recipient, amount, currency, note string) (*executor.SendMoneyResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	from, ok := b.users[userID]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	to := b.findUser(recipient)
+	if to == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, recipient)
+	}
+	if to == from {
+		return nil, ErrSelfTransfer
+	}
+	minor, cur, err := b.parseMoney(amount, currency)
+	if err != nil {
+		return nil, err
+	}
+	if from.balances[cur] < minor {
+		return nil, ErrInsufficientFunds
+	}
+
+	now := b.now()
+	from.balances[cur] -= minor
+	to.balances[cur] += minor
+	tx := b.record(from, "send", "outgoing", cur, minor, "@"+to.profile.DisplayTag, note, now)
+	b.record(to, "receive", "incoming", cur, minor, "@"+from.profile.DisplayTag, note, now)
+
+	return &executor.SendMoneyResponse{Success: true, TransactionID: tx.ID, TxHash: tx.TxHash}, nil
+}
+
+// DepositSavings moves amount from the wallet into the currency's vault.
+func (b *Bank) DepositSavings(userID, amount, currency string) (*executor.DepositResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	acct, ok := b.users[userID]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	minor, cur, err := b.parseMoney(amount, currency)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := b.vaults[cur]; !ok {
+		return nil, fmt.Errorf("%w: no %s vault", ErrUnknownCurrency, cur)
+	}
+	if acct.balances[cur] < minor {
+		return nil, ErrInsufficientFunds
+	}
+
+	now := b.now()
+	pos := acct.savings[cur]
+	if pos == nil {
+		pos = &position{accruedAt: now}
+		acct.savings[cur] = pos
+	}
+	b.accrue(cur, pos, now)
+	acct.balances[cur] -= minor
+	pos.deposited += minor
+	pos.value += float64(minor)
+	tx := b.record(acct, "deposit", "outgoing", cur, minor, "savings", "", now)
+
+	return &executor.DepositResponse{Success: true, TransactionID: tx.ID, TxHash: tx.TxHash}, nil
+}
+
+// WithdrawSavings moves amount from the currency's vault back to the wallet.
+// Interest is withdrawn before principal.
+func (b *Bank) WithdrawSavings(userID, amount, currency string) (*executor.WithdrawResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	acct, ok := b.users[userID]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	minor, cur, err := b.parseMoney(amount, currency)
+	if err != nil {
+		return nil, err
+	}
+	pos := acct.savings[cur]
+	if pos == nil {
+		return nil, ErrInsufficientFunds
+	}
+
+	now := b.now()
+	b.accrue(cur, pos, now)
+	if int64(math.Round(pos.value)) < minor {
+		return nil, ErrInsufficientFunds
+	}
+
+	pos.value -= float64(minor)
+	if earnings := int64(math.Round(pos.value)) - pos.deposited; earnings < 0 {
+		pos.deposited += earnings
+	}
+	if pos.value < 0.5 {
+		delete(acct.savings, cur)
+	}
+	acct.balances[cur] += minor
+	tx := b.record(acct, "withdraw", "incoming", cur, minor, "savings", "", now)
+
+	return &executor.WithdrawResponse{Success: true, TransactionID: tx.ID, TxHash: tx.TxHash}, nil
+}
+
+// findUser resolves a display tag (with or without "@") or user ID.
+// The caller must hold b.mu.
+func (b *Bank) findUser(ref string) *account {
+	ref = strings.TrimSpace(ref)
+	if acct, ok := b.users[ref]; ok {
+		return acct
+	}
+	tag := strings.TrimPrefix(ref, "@")
+	for _, id := range b.order {
+		if strings.EqualFold(b.users[id].profile.DisplayTag, tag) {
+			return b.users[id]
+		}
+	}
+	return nil
+}
+
+// parseMoney validates an amount and currency. The caller must hold b.mu.
+func (b *Bank) parseMoney(amount, currency string) (int64, string, error) {
+	minor, err := parseAmount(amount)
+	if err != nil {
+		return 0, "", err
+	}
+	cur := strings.ToUpper(strings.TrimSpace(currency))
+	if _, ok := b.usdRates[cur]; !ok {
+		return 0, "", fmt.Errorf("%w: %q", ErrUnknownCurrency, currency)
+	}
+	return minor, cur, nil
+}
+
+// accrue compounds interest on pos up to now. The caller must hold b.mu.
+func (b *Bank) accrue(currency string, pos *position, now time.Time) {
+	if !now.After(pos.accruedAt) {
+		return
+	}
+	years := now.Sub(pos.accruedAt).Hours() / (24 * 365)
+	pos.value *= math.Pow(1+b.vaults[currency]/100, years)
+	pos.accruedAt = now
+}
+
+// record prepends a transaction to acct's ledger. The caller must hold b.mu.
+func (b *Bank) record(acct *account, txType, direction, currency string, amount int64, counterparty, note string, at time.Time) executor.Transaction {
+	b.txSeq++
+	tx := executor.Transaction{
+		ID:           fmt.Sprintf("tx_%06d", b.txSeq),
+		Type:         txType,
+		Amount:       formatAmount(amount),
+		Currency:     currency,
+		USDValue:     formatAmount(b.toUSD(currency, float64(amount))),
+		Counterparty: counterparty,
+		Note:         note,
+		Status:       "completed",
+		Direction:    direction,
+		CreatedAt:    at.UTC().Format(time.RFC3339),
+		TxHash:       fmt.Sprintf("0x%064x", b.txSeq),
+	}
+	acct.ledger = append([]executor.Transaction{tx}, acct.ledger...)
+	return tx
+}
+
+// toUSD converts minor units of currency to USD minor units.
+func (b *Bank) toUSD(currency string, minor float64) int64 {
+	return int64(math.Round(minor * b.usdRates[currency]))
+}
+
+// parseAmount parses a positive decimal string with at most two decimal
+// places into minor units.
+func parseAmount(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" || len(frac) > 2 || strings.HasPrefix(whole, "-") || strings.HasPrefix(whole, "+") {
+		return 0, ErrInvalidAmount
+	}
+	for len(frac) < 2 {
+		frac += "0"
+	}
+	n, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, ErrInvalidAmount
+	}
+	return n, nil
+}
+
+// formatAmount converts minor units to a decimal with two places.
+func formatAmount(minor int64) money.Decimal {
+	return money.NewDecimal(minor, 2)
+}
+
+func formatAPY(apy float64) money.Decimal {
+	return money.NewDecimal(int64(math.Round(apy*100)), 2)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,270 @@
+package simbank
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/executor"
+)
+
+func newTestBank(t *testing.T, now *time.Time) *Bank {
+	t.Helper()
+	b, err := New(Config{Now: func() time.Time { return *now }})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return b
+}
+
+func execute(t *testing.T, exec core.ToolExecutor, userID, tool, input string) json.RawMessage {
+	t.Helper()
+	resp, err := exec.Execute(context.Background(), &core.ExecuteRequest{
+		UserID: userID,
+		Tool:   tool,
+		Input:  json.RawMessage(input),
+	})
+	if err != nil {
+		t.Fatalf("%s failed: %v", tool, err)
+	}
+	if !resp.Success {
+		t.Fatalf("%s failed: %s", tool, resp.Error)
+	}
+	return resp.Data
+}
+
+func TestHTTPExecutor(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newTestBank(t, &now)
+	ts := NewServer(b)
+	defer ts.Close()
+
+	exec := executor.NewHTTPExecutor(executor.HTTPExecutorConfig{
+		BaseURL:  ts.URL,
+		JWTToken: "user_alice",
+	})
+
+	var balance executor.GetBalanceResponse
+	json.Unmarshal(execute(t, exec, "user_alice", "get_balance", `{"currency":"USD"}`), &balance)
+	if len(balance.Balances) != 1 || balance.Balances[0].Amount.String() != "1250.00" {
+		t.Fatalf("unexpected balance: %+v", balance)
+	}
+
+	resp, err := exec.ExecuteWrite(context.Background(), &core.ExecuteRequest{
+		UserID: "user_alice",
+		Tool:   "send_money",
+		Input:  json.RawMessage(`{"recipient":"@bob","amount":"50.25","currency":"USD","note":"lunch"}`),
+	})
+	if err != nil || !resp.Success {
+		t.Fatalf("send_money failed: %v %+v", err, resp)
+	}
+	if got, _ := b.Balance("user_bob", "USD"); got != "130.25" {
+		t.Errorf("expected bob to have 130.25 USD, got %s", got)
+	}
+
+	_, err = exec.ExecuteWrite(context.Background(), &core.ExecuteRequest{
+		UserID: "user_alice",
+		Tool:   "send_money",
+		Input:  json.RawMessage(`{"recipient":"@bob","amount":"99999","currency":"USD"}`),
+	})
+	if !errors.Is(err, executor.ErrValidation) || !strings.Contains(err.Error(), "insufficient funds") {
+		t.Fatalf("expected insufficient funds validation error, got %v", err)
+	}
+
+	// Staged writes run through the confirmation routes
+	details, err := b.Stage("user_alice", "deposit_savings", json.RawMessage(`{"amount":"100","currency":"USD"}`))
+	if err != nil {
+		t.Fatalf("Stage failed: %v", err)
+	}
+	resp, err = exec.Confirm(context.Background(), "user_alice", details.ID)
+	if err != nil || !resp.Success {
+		t.Fatalf("confirm failed: %v %+v", err, resp)
+	}
+	if got, _ := b.Balance("user_alice", "USD"); got != "1099.75" {
+		t.Errorf("expected 1099.75 USD after send and deposit, got %s", got)
+	}
+}
+
+func TestTransactionCursor(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newTestBank(t, &now)
+	for i := 0; i < 5; i++ {
+		if _, err := b.SendMoney("user_carol", "bob", "1.00", "USD", ""); err != nil {
+			t.Fatalf("SendMoney failed: %v", err)
+		}
+	}
+
+	seen := map[string]bool{}
+	cursor := ""
+	pages := 0
+	for {
+		page, err := b.GetTransactions("user_carol", TransactionQuery{Limit: 3, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("GetTransactions failed: %v", err)
+		}
+		pages++
+		for _, tx := range page.Transactions {
+			if seen[tx.ID] {
+				t.Fatalf("transaction %s returned twice", tx.ID)
+			}
+			seen[tx.ID] = true
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	// 2 opening entries (USD, LIL), 1 opening savings deposit, 5 sends
+	if len(seen) != 8 || pages != 3 {
+		t.Errorf("expected 8 transactions over 3 pages, got %d over %d", len(seen), pages)
+	}
+}
+
+func TestGRPCExecutorSavingsAccrual(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newTestBank(t, &now)
+	exec := executor.NewGRPCExecutor(b.ExecutorConfig(nil))
+
+	now = now.AddDate(1, 0, 0)
+
+	var savings executor.GetSavingsBalanceResponse
+	json.Unmarshal(execute(t, exec, "user_alice", "get_savings_balance", `{}`), &savings)
+	if len(savings.Positions) != 1 {
+		t.Fatalf("unexpected positions: %+v", savings)
+	}
+	// 500.00 at 4.5% APY for one year
+	if pos := savings.Positions[0]; pos.CurrentValue.String() != "522.50" || pos.Earnings.String() != "22.50" {
+		t.Errorf("unexpected accrual: %+v", pos)
+	}
+
+	resp, err := exec.ExecuteWrite(context.Background(), &core.ExecuteRequest{
+		UserID: "user_alice",
+		Tool:   "withdraw_savings",
+		Input:  json.RawMessage(`{"amount":"22.50","currency":"USD"}`),
+	})
+	if err != nil || !resp.RequiresConfirmation {
+		t.Fatalf("expected confirmation, got %v %+v", err, resp)
+	}
+	resp, err = exec.Confirm(context.Background(), "user_alice", resp.Confirmation.ID)
+	if err != nil || !resp.Success {
+		t.Fatalf("confirm failed: %v %+v", err, resp)
+	}
+
+	json.Unmarshal(execute(t, exec, "user_alice", "get_savings_balance", `{}`), &savings)
+	if pos := savings.Positions[0]; pos.CurrentValue.String() != "500.00" || pos.Deposited.String() != "500.00" {
+		t.Errorf("expected interest withdrawn first, got %+v", pos)
+	}
+	if got, _ := b.Balance("user_alice", "USD"); got != "1272.50" {
+		t.Errorf("expected 1272.50 USD, got %s", got)
+	}
+}
+
+func TestTransactionPager(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newTestBank(t, &now)
+	// One payment to bob per day, Jan 1-10, amounts 1.00-10.00
+	for i := 1; i <= 10; i++ {
+		if _, err := b.SendMoney("user_carol", "bob", fmt.Sprintf("%d.00", i), "USD", ""); err != nil {
+			t.Fatalf("SendMoney failed: %v", err)
+		}
+		now = now.AddDate(0, 0, 1)
+	}
+	ts := NewServer(b)
+	defer ts.Close()
+
+	executors := map[string]core.ToolExecutor{
+		"http": executor.NewHTTPExecutor(executor.HTTPExecutorConfig{BaseURL: ts.URL, JWTToken: "user_carol"}),
+		"grpc": executor.NewGRPCExecutor(b.ExecutorConfig(nil)),
+	}
+	for name, exec := range executors {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			query := executor.TransactionQuery{
+				Limit:        2,
+				StartDate:    "2026-01-03",
+				EndDate:      "2026-01-08",
+				Counterparty: "BOB",
+				MinAmount:    "4",
+			}
+			txs, err := executor.ListTransactions(ctx, exec, "user_carol", query, 0)
+			if err != nil {
+				t.Fatalf("ListTransactions failed: %v", err)
+			}
+			var amounts []string
+			for _, tx := range txs {
+				amounts = append(amounts, tx.Amount.String())
+			}
+			if got := strings.Join(amounts, ","); got != "8.00,7.00,6.00,5.00,4.00" {
+				t.Errorf("unexpected transactions: %s", got)
+			}
+
+			txs, err = executor.ListTransactions(ctx, exec, "user_carol", executor.TransactionQuery{Limit: 3}, 2)
+			if !errors.Is(err, executor.ErrMaxPages) || len(txs) != 6 {
+				t.Errorf("expected 6 transactions and ErrMaxPages, got %d, %v", len(txs), err)
+			}
+
+			cancelled, cancel := context.WithCancel(ctx)
+			cancel()
+			pager := executor.NewTransactionPager(exec, "user_carol", executor.TransactionQuery{}, 0)
+			if pager.Next(cancelled) || !errors.Is(pager.Err(), context.Canceled) || pager.Pages() != 0 {
+				t.Errorf("expected cancelled pager to stop before fetching, got %v", pager.Err())
+			}
+
+			_, err = executor.ListTransactions(ctx, exec, "user_carol", executor.TransactionQuery{StartDate: "last week"}, 0)
+			if err == nil || !strings.Contains(err.Error(), "start_date") {
+				t.Errorf("expected start_date error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTransactionFiltersMatchAcrossExecutors(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newTestBank(t, &now)
+	for i := 1; i <= 10; i++ {
+		if _, err := b.SendMoney("user_carol", "bob", fmt.Sprintf("%d.00", i), "USD", ""); err != nil {
+			t.Fatalf("SendMoney failed: %v", err)
+		}
+		now = now.AddDate(0, 0, 1)
+	}
+	ts := NewServer(b)
+	defer ts.Close()
+
+	// A gateway that only pages, ignoring the filters
+	pagingOnly := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		for _, filter := range []string{"type", "start_date", "end_date", "counterparty", "min_amount"} {
+			q.Del(filter)
+		}
+		r.URL.RawQuery = q.Encode()
+		b.Handler().ServeHTTP(w, r)
+	}))
+	defer pagingOnly.Close()
+
+	input := `{"limit":10,"start_date":"2026-01-03","end_date":"2026-01-08","counterparty":"BOB","min_amount":"4","type":"send"}`
+	for name, exec := range map[string]core.ToolExecutor{
+		"grpc":        executor.NewGRPCExecutor(b.ExecutorConfig(nil)),
+		"http":        executor.NewHTTPExecutor(executor.HTTPExecutorConfig{BaseURL: ts.URL, JWTToken: "user_carol"}),
+		"paging-only": executor.NewHTTPExecutor(executor.HTTPExecutorConfig{BaseURL: pagingOnly.URL, JWTToken: "user_carol"}),
+	} {
+		var page executor.GetTransactionsResponse
+		if err := json.Unmarshal(execute(t, exec, "user_carol", "get_transactions", input), &page); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var amounts []string
+		for _, tx := range page.Transactions {
+			amounts = append(amounts, tx.Amount.String())
+		}
+		if got := strings.Join(amounts, ","); got != "8.00,7.00,6.00,5.00,4.00" {
+			t.Errorf("%s: unexpected transactions: %s", name, got)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package simbank
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+// stagedTTL is how long a staged write waits for confirmation.
+const stagedTTL = 10 * time.Minute
+
+// gRPC status codes used in grpc-gateway style error bodies.
+const (
+	codeInvalidArgument    = 3
+	codeNotFound           = 5
+	codeFailedPrecondition = 9
+	codeUnauthenticated    = 16
+)
+
+// stagedOp is a write awaiting confirmation through the confirmation routes.
+type stagedOp struct {
+	userID    string
+	tool      string
+	input     json.RawMessage
+	summary   string
+	expiresAt time.Time
+}
+
+// Stage records a write operation that will run when its confirmation ID is
+// confirmed through POST /nim/v1/agent/confirmations/{id}/confirm.
+func (b *Bank) Stage(userID, tool string, input json.RawMessage) (*core.ConfirmationDetails, error) {
+	var params struct {
+		Recipient string `json:"recipient"`
+		Amount    string `json:"amount"`
+		Currency  string `json:"currency"`
+	}
+	json.Unmarshal(input, &params)
+
+	var summary string
+	switch tool {
+	case "send_money":
+		summary = fmt.Sprintf("Send %s %s to %s", params.Amount, params.Currency, params.Recipient)
+	case "deposit_savings":
+		summary = fmt.Sprintf("Deposit %s %s into savings", params.Amount, params.Currency)
+	case "withdraw_savings":
+		summary = fmt.Sprintf("Withdraw %s %s from savings", params.Amount, params.Currency)
+	default:
+		return nil, fmt.Errorf("unknown write tool: %s", tool)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.users[userID]; !ok {
+		return nil, ErrUserNotFound
+	}
+	op := &stagedOp{
+		userID:    userID,
+		tool:      tool,
+		input:     input,
+		summary:   summary,
+		expiresAt: b.now().Add(stagedTTL),
+	}
+	id := uuid.New().String()
+	b.staged[id] = op
+
+	return &core.ConfirmationDetails{ID: id, Summary: summary, ExpiresAt: op.expiresAt.Unix()}, nil
+}
+
+// ConfirmStaged runs a staged write and returns its response.
+func (b *Bank) ConfirmStaged(userID, confirmationID string) (interface{}, error) {
+	op, err := b.takeStaged(userID, confirmationID)
+	if err != nil {
+		return nil, err
+	}
+	return b.executeWrite(op.userID, op.tool, op.input)
+}
+
+// CancelStaged discards a staged write.
+func (b *Bank) CancelStaged(userID, confirmationID string) error {
+	_, err := b.takeStaged(userID, confirmationID)
+	return err
+}
+
+func (b *Bank) takeStaged(userID, confirmationID string) (*stagedOp, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	op, ok := b.staged[confirmationID]
+	if !ok || op.userID != userID {
+		return nil, errConfirmationNotFound
+	}
+	delete(b.staged, confirmationID)
+	if b.now().After(op.expiresAt) {
+		return nil, errConfirmationNotFound
+	}
+	return op, nil
+}
+
+var errConfirmationNotFound = errors.New("confirmation not found or expired")
+
+// executeWrite runs a write tool with its JSON input.
+func (b *Bank) executeWrite(userID, tool string, input json.RawMessage) (interface{}, error) {
+	var params struct {
+		Recipient string `json:"recipient"`
+		Amount    string `json:"amount"`
+		Currency  string `json:"currency"`
+		Note      string `json:"note"`
+	}
+	if len(input) > 0 {
+		if err := json.Unmarshal(input, &params); err != nil {
+			return nil, fmt.Errorf("%w: %v", errInvalidInput, err)
+		}
+	}
+
+	switch tool {
+	case "send_money":
+		return b.SendMoney(userID, params.Recipient, params.Amount, params.Currency, params.Note)
+	case "deposit_savings":
+		return b.DepositSavings(userID, params.Amount, params.Currency)
+	case "withdraw_savings":
+		return b.WithdrawSavings(userID, params.Amount, params.Currency)
+	default:
+		return nil, fmt.Errorf("unknown write tool: %s", tool)
+	}
+}
+
+var errInvalidInput = errors.New("invalid input")
+
+// NewServer starts an httptest.Server serving b's gateway API.
+// The caller should Close it when done.
+func NewServer(b *Bank) *httptest.Server {
+	return httptest.NewServer(b.Handler())
+}
+
+// Handler returns an http.Handler serving the agent_gateway endpoints used by
+// executor.HTTPExecutor:
+//
+//	GET  /nim/v1/agent/wallet/balance
+//	GET  /nim/v1/agent/savings/balance
+//	GET  /nim/v1/agent/savings/vaults
+//	GET  /nim/v1/agent/transactions
+//	GET  /nim/v1/agent/profile
+//	GET  /nim/v1/agent/users/search
+//	POST /nim/v1/agent/payments/send
+//	POST /nim/v1/agent/savings/deposit
+//	POST /nim/v1/agent/savings/withdraw
+//	POST /nim/v1/agent/confirmations/{id}/confirm
+//	POST /nim/v1/agent/confirmations/{id}/cancel
+//	GET  /health
+//
+// Requests authenticate with "Authorization: Bearer <user ID>" or
+// "X-API-Key: <user ID>". Write endpoints accept either a core.ExecuteRequest
+// envelope or the bare tool input, and execute immediately; use Stage with the
+// confirmation routes to exercise the confirm flow. Errors use the
+// grpc-gateway shape {"code": ..., "message": ...}.
+func (b *Bank) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /nim/v1/agent/wallet/balance", b.authed(b.handleBalance))
+	mux.HandleFunc("GET /nim/v1/agent/savings/balance", b.authed(b.handleSavingsBalance))
+	mux.HandleFunc("GET /nim/v1/agent/savings/vaults", b.authed(b.handleVaultRates))
+	mux.HandleFunc("GET /nim/v1/agent/transactions", b.authed(b.handleTransactions))
+	mux.HandleFunc("GET /nim/v1/agent/profile", b.authed(b.handleProfile))
+	mux.HandleFunc("GET /nim/v1/agent/users/search", b.authed(b.handleSearchUsers))
+	mux.HandleFunc("POST /nim/v1/agent/payments/send", b.authed(b.writeHandler("send_money")))
+	mux.HandleFunc("POST /nim/v1/agent/savings/deposit", b.authed(b.writeHandler("deposit_savings")))
+	mux.HandleFunc("POST /nim/v1/agent/savings/withdraw", b.authed(b.writeHandler("withdraw_savings")))
+	mux.HandleFunc("POST /nim/v1/agent/confirmations/{id}/confirm", b.authed(b.handleConfirm))
+	mux.HandleFunc("POST /nim/v1/agent/confirmations/{id}/cancel", b.authed(b.handleCancel))
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	return mux
+}
+
+// authed resolves the calling user before invoking h.
+func (b *Bank) authed(h func(w http.ResponseWriter, r *http.Request, userID string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" {
+			token = r.Header.Get("X-API-Key")
+		}
+
+		b.mu.Lock()
+		_, ok := b.users[token]
+		b.mu.Unlock()
+		if !ok {
+			writeStatus(w, http.StatusUnauthorized, codeUnauthenticated, "unauthenticated")
+			return
+		}
+		h(w, r, token)
+	}
+}
+
+func (b *Bank) handleBalance(w http.ResponseWriter, r *http.Request, userID string) {
+	resp, err := b.GetBalance(userID, r.URL.Query().Get("currency"))
+	respond(w, resp, err)
+}
+
+func (b *Bank) handleSavingsBalance(w http.ResponseWriter, r *http.Request, userID string) {
+	resp, err := b.GetSavingsBalance(userID, r.URL.Query().Get("vault"))
+	respond(w, resp, err)
+}
+
+func (b *Bank) handleVaultRates(w http.ResponseWriter, r *http.Request, userID string) {
+	respond(w, b.GetVaultRates(), nil)
+}
+
+func (b *Bank) handleTransactions(w http.ResponseWriter, r *http.Request, userID string) {
+	q := r.URL.Query()
+	query := TransactionQuery{
+		Type:         q.Get("type"),
+		Cursor:       q.Get("cursor"),
+		StartDate:    q.Get("start_date"),
+		EndDate:      q.Get("end_date"),
+		Counterparty: q.Get("counterparty"),
+		MinAmount:    q.Get("min_amount"),
+	}
+	if limit := q.Get("limit"); limit != "" {
+		n, err := strconv.ParseFloat(limit, 64)
+		if err != nil || n < 0 {
+			writeStatus(w, http.StatusBadRequest, codeInvalidArgument, "invalid limit")
+			return
+		}
+		query.Limit = int(n)
+	}
+	resp, err := b.GetTransactions(userID, query)
+	respond(w, resp, err)
+}
+
+func (b *Bank) handleProfile(w http.ResponseWriter, r *http.Request, userID string) {
+	resp, err := b.GetProfile(userID)
+	respond(w, resp, err)
+}
+
+func (b *Bank) handleSearchUsers(w http.ResponseWriter, r *http.Request, userID string) {
+	respond(w, b.SearchUsers(r.URL.Query().Get("query")), nil)
+}
+
+func (b *Bank) writeHandler(tool string) func(w http.ResponseWriter, r *http.Request, userID string) {
+	return func(w http.ResponseWriter, r *http.Request, userID string) {
+		var body json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeStatus(w, http.StatusBadRequest, codeInvalidArgument, "invalid request body")
+			return
+		}
+
+		// Unwrap a core.ExecuteRequest envelope if present
+		input := body
+		var envelope core.ExecuteRequest
+		if err := json.Unmarshal(body, &envelope); err == nil && len(envelope.Input) > 0 {
+			input = envelope.Input
+		}
+
+		resp, err := b.executeWrite(userID, tool, input)
+		respond(w, resp, err)
+	}
+}
+
+func (b *Bank) handleConfirm(w http.ResponseWriter, r *http.Request, userID string) {
+	resp, err := b.ConfirmStaged(userID, r.PathValue("id"))
+	respond(w, resp, err)
+}
+
+func (b *Bank) handleCancel(w http.ResponseWriter, r *http.Request, userID string) {
+	err := b.CancelStaged(userID, r.PathValue("id"))
+	respond(w, map[string]bool{"success": true}, err)
+}
+
+// respond writes resp as JSON, or err in grpc-gateway form.
+func respond(w http.ResponseWriter, resp interface{}, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUserNotFound), errors.Is(err, errConfirmationNotFound):
+			writeStatus(w, http.StatusNotFound, codeNotFound, err.Error())
+		case errors.Is(err, ErrInsufficientFunds), errors.Is(err, ErrSelfTransfer):
+			writeStatus(w, http.StatusBadRequest, codeFailedPrecondition, err.Error())
+		default:
+			writeStatus(w, http.StatusBadRequest, codeInvalidArgument, err.Error())
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func writeStatus(w http.ResponseWriter, status, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":    code,
+		"message": message,
+	})
+}
@@ -0,0 +1,99 @@
+package simbank
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/becomeliminal/nim-go-sdk/executor"
+	"github.com/becomeliminal/nim-go-sdk/store"
+)
+
+// ExecutorConfig returns a GRPCExecutorConfig whose services are backed by b.
+// confirmations stores pending writes; if nil, an in-memory store is used.
+func (b *Bank) ExecutorConfig(confirmations store.Confirmations) executor.GRPCExecutorConfig {
+	if confirmations == nil {
+		confirmations = store.NewMemoryConfirmations()
+	}
+	return executor.GRPCExecutorConfig{
+		Wallets:       walletService{b},
+		Payments:      paymentService{b},
+		Savings:       savingsService{b},
+		Users:         userService{b},
+		Ledger:        ledgerService{b},
+		Confirmations: confirmations,
+	}
+}
+
+// Verify the simulated services implement the executor service interfaces.
+var (
+	_ executor.WalletService      = walletService{}
+	_ executor.PaymentService     = paymentService{}
+	_ executor.SavingsService     = savingsService{}
+	_ executor.UserService        = userService{}
+	_ executor.LedgerService      = ledgerService{}
+	_ executor.TransactionQuerier = ledgerService{}
+)
+
+type walletService struct{ b *Bank }
+
+func (s walletService) GetBalance(ctx context.Context, userID string, currency *string) (json.RawMessage, error) {
+	return marshal(s.b.GetBalance(userID, deref(currency)))
+}
+
+type paymentService struct{ b *Bank }
+
+func (s paymentService) Send(ctx context.Context, userID, recipient, amount, currency string, note *string) (json.RawMessage, error) {
+	return marshal(s.b.SendMoney(userID, recipient, amount, currency, deref(note)))
+}
+
+type savingsService struct{ b *Bank }
+
+func (s savingsService) GetBalance(ctx context.Context, userID string, vault *string) (json.RawMessage, error) {
+	return marshal(s.b.GetSavingsBalance(userID, deref(vault)))
+}
+
+func (s savingsService) GetVaultRates(ctx context.Context) (json.RawMessage, error) {
+	return marshal(s.b.GetVaultRates(), nil)
+}
+
+func (s savingsService) Deposit(ctx context.Context, userID, amount, currency string) (json.RawMessage, error) {
+	return marshal(s.b.DepositSavings(userID, amount, currency))
+}
+
+func (s savingsService) Withdraw(ctx context.Context, userID, amount, currency string) (json.RawMessage, error) {
+	return marshal(s.b.WithdrawSavings(userID, amount, currency))
+}
+
+type userService struct{ b *Bank }
+
+func (s userService) GetProfile(ctx context.Context, userID string) (json.RawMessage, error) {
+	return marshal(s.b.GetProfile(userID))
+}
+
+func (s userService) Search(ctx context.Context, query string) (json.RawMessage, error) {
+	return marshal(s.b.SearchUsers(query), nil)
+}
+
+type ledgerService struct{ b *Bank }
+
+func (s ledgerService) GetTransactions(ctx context.Context, userID string, limit int, txType *string) (json.RawMessage, error) {
+	return marshal(s.b.GetTransactions(userID, TransactionQuery{Limit: limit, Type: deref(txType)}))
+}
+
+func (s ledgerService) QueryTransactions(ctx context.Context, userID string, query executor.TransactionQuery) (json.RawMessage, error) {
+	return marshal(s.b.GetTransactions(userID, query))
+}
+
+func marshal(v interface{}, err error) (json.RawMessage, error) {
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -127,6 +128,21 @@ func (m *MemoryConfirmations) Cleanup(ctx context.Context) (int, error) {
 	return count, nil
 }
 
+func (m *MemoryConfirmations) ListPending(ctx context.Context, userID string) ([]*core.PendingAction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now().Unix()
+	result := make([]*core.PendingAction, 0)
+	for _, action := range m.actions {
+		if action.UserID == userID && action.ExpiresAt >= now {
+			result = append(result, action)
+		}
+	}
+	sortPending(result)
+	return result, nil
+}
+
 func (m *MemoryConfirmations) deleteUnlocked(action *core.PendingAction) {
 	delete(m.actions, action.ID)
 	if action.IdempotencyKey != "" {
@@ -134,5 +150,16 @@ func (m *MemoryConfirmations) deleteUnlocked(action *core.PendingAction) {
 	}
 }
 
+// sortPending orders actions by creation time, oldest first.
+func sortPending(actions []*core.PendingAction) {
+	sort.Slice(actions, func(i, j int) bool {
+		if actions[i].CreatedAt != actions[j].CreatedAt {
+			return actions[i].CreatedAt < actions[j].CreatedAt
+		}
+		return actions[i].ID < actions[j].ID
+	})
+}
+
 // Verify MemoryConfirmations implements Confirmations.
 var _ Confirmations = (*MemoryConfirmations)(nil)
+var _ PendingLister = (*MemoryConfirmations)(nil)
@@ -35,7 +35,7 @@ func (m *MemoryConversations) Create(ctx context.Context, userID string) (*Conve
 		Conversation: Conversation{
 			ID:        uuid.New().String(),
 			UserID:    userID,
-			Title:     "New conversation",
+			Title:     DefaultConversationTitle,
 			CreatedAt: now,
 			UpdatedAt: now,
 		},
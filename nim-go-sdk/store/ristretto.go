@@ -195,6 +195,30 @@ func (r *RistrettoConfirmations) Cleanup(ctx context.Context) (int, error) {
 	return count, nil
 }
 
+func (r *RistrettoConfirmations) ListPending(ctx context.Context, userID string) ([]*core.PendingAction, error) {
+	r.mu.RLock()
+	actionIDs := make([]string, 0, len(r.actionsByUser[userID]))
+	for actionID := range r.actionsByUser[userID] {
+		actionIDs = append(actionIDs, actionID)
+	}
+	r.mu.RUnlock()
+
+	now := time.Now().Unix()
+	result := make([]*core.PendingAction, 0, len(actionIDs))
+	for _, actionID := range actionIDs {
+		val, found := r.cache.Get(r.actionKey(userID, actionID))
+		if !found {
+			continue
+		}
+		action := val.(*core.PendingAction)
+		if action.ExpiresAt >= now {
+			result = append(result, action)
+		}
+	}
+	sortPending(result)
+	return result, nil
+}
+
 // Close releases resources used by the cache.
 func (r *RistrettoConfirmations) Close() {
 	r.cache.Close()
@@ -239,3 +263,4 @@ func (r *RistrettoConfirmations) ttlFor(action *core.PendingAction) time.Duratio
 
 // Verify RistrettoConfirmations implements Confirmations.
 var _ Confirmations = (*RistrettoConfirmations)(nil)
+var _ PendingLister = (*RistrettoConfirmations)(nil)
@@ -33,6 +33,14 @@ type Confirmations interface {
 	Cleanup(ctx context.Context) (int, error)
 }
 
+// PendingLister is an optional interface for Confirmations stores that can
+// enumerate a user's pending actions. Both SDK stores implement it; callers
+// should type-assert and degrade gracefully for stores that don't.
+type PendingLister interface {
+	// ListPending returns the user's unexpired pending actions, oldest first.
+	ListPending(ctx context.Context, userID string) ([]*core.PendingAction, error)
+}
+
 // Conversations stores conversation history.
 // The SDK provides MemoryConversations for development.
 // Production deployments should implement with PostgreSQL or similar.
@@ -2,6 +2,10 @@ package store
 
 import "time"
 
+// DefaultConversationTitle is the title given to new conversations until
+// one is generated or set by the user.
+const DefaultConversationTitle = "New conversation"
+
 // Conversation represents conversation metadata.
 type Conversation struct {
 	ID        string    `json:"id"`
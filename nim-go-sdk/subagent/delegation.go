@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/becomeliminal/nim-go-sdk/core"
 	"github.com/becomeliminal/nim-go-sdk/tools"
@@ -111,20 +112,9 @@ func (d *DelegationTool) Execute(ctx context.Context, params *core.ToolParams) (
 		task = d.taskFormatter(input.Query)
 	}
 
-	// Create a minimal context for the sub-agent
-	// The sub-agent context should be created from the parent context
-	// but we only have userID here, so we create a basic one
-	subCtx := &core.Context{
-		UserID:    params.UserID,
-		RequestID: params.RequestID,
-		Limits:    core.SubAgentLimits(),
-	}
-
-	// Run sub-agent
-	output, err := d.subagent.Run(ctx, &core.Input{
-		UserMessage: task,
-		Context:     subCtx,
-	})
+	// Run sub-agent in a context derived from the parent's, so it keeps the
+	// session, preferences, user limits and audit chain
+	output, err := d.subagent.RunWithTask(ctx, parentContext(ctx, params), task)
 	if err != nil {
 		return &core.ToolResult{
 			Success: false,
@@ -137,14 +127,16 @@ func (d *DelegationTool) Execute(ctx context.Context, params *core.ToolParams) (
 
 	if !result.Success {
 		return &core.ToolResult{
-			Success: false,
-			Error:   result.Error,
+			Success:    false,
+			Error:      result.Error,
+			TokensUsed: &result.TokensUsed,
 		}, nil
 	}
 
 	return &core.ToolResult{
-		Success: true,
-		Data:    result.Response,
+		Success:    true,
+		Data:       result.Response,
+		TokensUsed: &result.TokensUsed,
 		Metadata: map[string]interface{}{
 			"agent":       result.AgentName,
 			"tools_used":  len(result.ToolsUsed),
@@ -153,6 +145,19 @@ func (d *DelegationTool) Execute(ctx context.Context, params *core.ToolParams) (
 	}, nil
 }
 
+// parentContext returns the calling agent's context, or one built from the
+// tool params if the tool is called outside the engine.
+func parentContext(ctx context.Context, params *core.ToolParams) *core.Context {
+	if parentCtx, ok := core.FromContext(ctx); ok {
+		return parentCtx
+	}
+	return &core.Context{
+		UserID:    params.UserID,
+		RequestID: params.RequestID,
+		StartTime: time.Now(),
+	}
+}
+
 // GetSummary returns a summary of the delegation.
 func (d *DelegationTool) GetSummary(input json.RawMessage) string {
 	return fmt.Sprintf("Delegate to %s specialist", d.subagent.Name())
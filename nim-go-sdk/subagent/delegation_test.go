@@ -0,0 +1,89 @@
+package subagent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/becomeliminal/nim-go-sdk/anthropictest"
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/engine"
+)
+
+func TestDelegationInheritsContext(t *testing.T) {
+	fake := anthropictest.NewServer(
+		anthropictest.ToolUse("delegate_to_analyst", map[string]interface{}{"query": "check my spending"}),
+		anthropictest.ToolUse("whoami", map[string]interface{}{}),
+		anthropictest.Text("Spending is on track."),
+		anthropictest.Text("All good."),
+	)
+	defer fake.Close()
+	client := anthropic.NewClient(option.WithAPIKey("test-key"), option.WithBaseURL(fake.URL))
+
+	var seen *core.Context
+	registry := engine.NewToolRegistry()
+	registry.Register(core.NewBaseTool(core.ToolDefinition{ToolName: "whoami"}, func(ctx context.Context, p *core.ToolParams) (*core.ToolResult, error) {
+		seen, _ = core.FromContext(ctx)
+		return &core.ToolResult{Success: true}, nil
+	}))
+	audit := engine.NewMemoryAuditLogger()
+	eng := engine.NewEngine(&client, registry, engine.WithAudit(audit))
+	analyst := NewSubAgent(eng, SubAgentConfig{Name: "analyst", AvailableTools: []string{"whoami"}})
+	registry.Register(DelegationToolFromAgent(analyst))
+
+	parent := core.NewContext("user_1", "session_1", "conv_1", "req_1")
+	parent.Preferences.Currency = "EUR"
+	parent.UserLimits = core.DefaultUserLimits()
+	parent.Limits.Timeout = 30 * time.Second
+	out, err := eng.Run(context.Background(), &engine.Input{UserMessage: "how am I doing?", Context: parent, Model: "claude-test"})
+	if err != nil || out.Type != engine.OutputComplete || out.Text != "All good." {
+		t.Fatalf("Run = %+v, %v", out, err)
+	}
+
+	if seen == nil {
+		t.Fatal("sub-agent tool saw no context")
+	}
+	if seen.UserID != "user_1" || seen.SessionID != "session_1" || seen.ConversationID != "conv_1" || seen.RequestID != "req_1-analyst" {
+		t.Errorf("sub-agent context = %+v", seen)
+	}
+	if seen.AuditParentID == nil || *seen.AuditParentID != "req_1" {
+		t.Errorf("AuditParentID = %v", seen.AuditParentID)
+	}
+	if seen.Preferences != parent.Preferences || seen.UserLimits != parent.UserLimits {
+		t.Error("sub-agent did not inherit preferences and user limits")
+	}
+	if seen.Limits.CanConfirm || seen.Limits.Timeout > 30*time.Second {
+		t.Errorf("sub-agent limits = %+v", seen.Limits)
+	}
+
+	entries := audit.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d", len(entries))
+	}
+	sub, top := entries[0], entries[1]
+	if sub.ToolName != "whoami" || sub.AgentName != "analyst" || sub.ParentID == nil || *sub.ParentID != top.RequestID {
+		t.Errorf("sub-agent entry %+v does not chain to %+v", sub, top)
+	}
+	if top.ToolName != "delegate_to_analyst" || top.RequestID != "req_1" || top.ParentID != nil {
+		t.Errorf("parent entry = %+v", top)
+	}
+}
+
+func TestForSubAgentDeadline(t *testing.T) {
+	parent := core.NewContext("user_1", "session_1", "conv_1", "req_1")
+	if got := parent.ForSubAgent("sub").Limits.Timeout; got != core.SubAgentLimits().Timeout {
+		t.Errorf("timeout = %v, want the sub-agent default", got)
+	}
+
+	parent.Limits.Timeout = 10 * time.Second
+	if got := parent.ForSubAgent("sub").Limits.Timeout; got > 10*time.Second || got < 9*time.Second {
+		t.Errorf("timeout = %v, want the parent's remaining time", got)
+	}
+
+	parent.StartTime = time.Now().Add(-time.Minute)
+	if sub := parent.ForSubAgent("sub"); sub.Limits.Timeout <= 0 || !sub.IsTimedOut() {
+		t.Errorf("expected an expired parent to give an expired sub-agent, got %v", sub.Limits.Timeout)
+	}
+}
@@ -0,0 +1,229 @@
+package subagent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+// DefaultFanOutConcurrency caps how many sub-agents a fan-out runs at once
+// when FanOutConfig.MaxConcurrency is zero.
+const DefaultFanOutConcurrency = 4
+
+// Task is a sub-task for one sub-agent in a fan-out.
+type Task struct {
+	// Agent is the sub-agent to run.
+	Agent *SubAgent
+
+	// Task is the message sent to the sub-agent.
+	Task string
+
+	// Timeout overrides the fan-out's per-agent timeout for this task.
+	Timeout time.Duration
+}
+
+// FanOutConfig configures a fan-out.
+type FanOutConfig struct {
+	// Timeout is the time each sub-agent gets. Defaults to the sub-agent
+	// limits' timeout, and never outlasts the parent context.
+	Timeout time.Duration
+
+	// MaxConcurrency caps how many sub-agents run at once. Defaults to
+	// DefaultFanOutConcurrency.
+	MaxConcurrency int
+
+	// Synthesizer, if set, combines the sub-agents' results into one
+	// response. It runs only if at least one sub-agent succeeded.
+	Synthesizer *SubAgent
+
+	// SynthesisFormatter formats the goal and results into the
+	// synthesizer's task. Defaults to FormatSynthesisTask.
+	SynthesisFormatter func(goal string, results []*SubAgentResult) string
+}
+
+// FanOut runs several sub-agents concurrently and collects their results.
+type FanOut struct {
+	timeout        time.Duration
+	maxConcurrency int
+	synthesizer    *SubAgent
+	formatter      func(goal string, results []*SubAgentResult) string
+}
+
+// FanOutResult is the outcome of a fan-out.
+type FanOutResult struct {
+	// Results holds one result per task, in task order. Failed and timed
+	// out sub-agents have Success false.
+	Results []*SubAgentResult `json:"results"`
+
+	// Synthesis is the synthesizer's result, or nil if none ran.
+	Synthesis *SubAgentResult `json:"synthesis,omitempty"`
+
+	// TokensUsed is the token usage of every sub-agent and the synthesizer.
+	TokensUsed core.TokenUsage `json:"tokens_used"`
+}
+
+// NewFanOut creates a fan-out with the given configuration.
+func NewFanOut(cfg FanOutConfig) *FanOut {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = core.SubAgentLimits().Timeout
+	}
+	maxConcurrency := cfg.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = DefaultFanOutConcurrency
+	}
+	formatter := cfg.SynthesisFormatter
+	if formatter == nil {
+		formatter = FormatSynthesisTask
+	}
+
+	return &FanOut{
+		timeout:        timeout,
+		maxConcurrency: maxConcurrency,
+		synthesizer:    cfg.Synthesizer,
+		formatter:      formatter,
+	}
+}
+
+// Run runs the tasks concurrently in sub-agent contexts derived from
+// parentCtx, then the synthesizer if configured. goal describes what the
+// tasks are for and is passed to the synthesizer.
+//
+// A sub-agent failing or timing out doesn't stop the others; its result
+// records the error. Run returns an error only if it is called without a
+// parent context or tasks.
+func (f *FanOut) Run(ctx context.Context, parentCtx *core.Context, goal string, tasks []Task) (*FanOutResult, error) {
+	if parentCtx == nil {
+		return nil, errors.New("fan-out requires a parent context")
+	}
+	if len(tasks) == 0 {
+		return nil, errors.New("fan-out requires at least one task")
+	}
+	for i, task := range tasks {
+		if task.Agent == nil {
+			return nil, fmt.Errorf("fan-out task %d has no agent", i)
+		}
+	}
+
+	out := &FanOutResult{Results: make([]*SubAgentResult, len(tasks))}
+	requestIDs := subRequestIDs(parentCtx.RequestID, tasks)
+
+	limit := f.maxConcurrency
+	if limit > len(tasks) {
+		limit = len(tasks)
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		// Start tasks in order as slots free up
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, task Task) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			timeout := task.Timeout
+			if timeout == 0 {
+				timeout = f.timeout
+			}
+			out.Results[i] = f.run(ctx, parentCtx, requestIDs[i], task.Agent, task.Task, timeout)
+		}(i, task)
+	}
+	wg.Wait()
+
+	succeeded := false
+	for _, result := range out.Results {
+		out.TokensUsed.Add(result.TokensUsed)
+		succeeded = succeeded || result.Success
+	}
+
+	if f.synthesizer != nil && succeeded {
+		requestID := fmt.Sprintf("%s-%s", parentCtx.RequestID, f.synthesizer.Name())
+		out.Synthesis = f.run(ctx, parentCtx, requestID, f.synthesizer, f.formatter(goal, out.Results), f.timeout)
+		out.TokensUsed.Add(out.Synthesis.TokensUsed)
+	}
+
+	return out, nil
+}
+
+// run runs one sub-agent with a timeout and converts its output to a result.
+func (f *FanOut) run(ctx context.Context, parentCtx *core.Context, requestID string, agent *SubAgent, task string, timeout time.Duration) *SubAgentResult {
+	subCtx := parentCtx.ForSubAgent(requestID)
+	subCtx.Limits.Timeout = parentCtx.CapTimeout(timeout)
+	ctx, cancel := context.WithTimeout(ctx, subCtx.Limits.Timeout)
+	defer cancel()
+
+	output, err := agent.Run(ctx, &core.Input{
+		UserMessage: task,
+		Context:     subCtx,
+		History:     []core.Message{},
+	})
+	if err != nil {
+		result := &SubAgentResult{AgentName: agent.Name(), Error: fmt.Sprintf("sub-agent error: %v", err)}
+		if output != nil {
+			result.TokensUsed = output.TokensUsed
+		}
+		return result
+	}
+	return ToResult(agent.Name(), output)
+}
+
+// subRequestIDs names each task's request after its agent, as RunWithTask
+// does, numbering agents that appear more than once.
+func subRequestIDs(parentID string, tasks []Task) []string {
+	counts := make(map[string]int)
+	for _, task := range tasks {
+		counts[task.Agent.Name()]++
+	}
+	seen := make(map[string]int)
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		name := task.Agent.Name()
+		ids[i] = fmt.Sprintf("%s-%s", parentID, name)
+		if counts[name] > 1 {
+			seen[name]++
+			ids[i] = fmt.Sprintf("%s-%d", ids[i], seen[name])
+		}
+	}
+	return ids
+}
+
+// Response returns the synthesis if one ran, or else the sub-agents'
+// responses and errors under their names.
+func (r *FanOutResult) Response() string {
+	if r.Synthesis != nil && r.Synthesis.Success {
+		return r.Synthesis.Response
+	}
+	return formatResults(r.Results)
+}
+
+// FormatSynthesisTask is the default synthesizer task: the goal followed by
+// each sub-agent's response or error.
+func FormatSynthesisTask(goal string, results []*SubAgentResult) string {
+	var b strings.Builder
+	b.WriteString("Combine these specialist results into a single answer")
+	if goal != "" {
+		b.WriteString(" to: ")
+		b.WriteString(goal)
+	}
+	b.WriteString("\nSome specialists may have failed; work with what succeeded and say what is missing.\n\n")
+	b.WriteString(formatResults(results))
+	return b.String()
+}
+
+func formatResults(results []*SubAgentResult) string {
+	parts := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.Success {
+			parts = append(parts, fmt.Sprintf("## %s\n%s", result.AgentName, result.Response))
+		} else {
+			parts = append(parts, fmt.Sprintf("## %s (failed)\n%s", result.AgentName, result.Error))
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,184 @@
+package subagent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/becomeliminal/nim-go-sdk/anthropictest"
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/engine"
+)
+
+// newTestEngine returns an engine backed by a fake server, with a "slow"
+// tool that blocks until its context is done.
+func newTestEngine(t *testing.T, responses ...anthropictest.Response) (*engine.Engine, *engine.ToolRegistry, *anthropictest.Server) {
+	t.Helper()
+	fake := anthropictest.NewServer(responses...)
+	t.Cleanup(fake.Close)
+	client := anthropic.NewClient(option.WithAPIKey("test-key"), option.WithBaseURL(fake.URL))
+
+	registry := engine.NewToolRegistry()
+	registry.Register(core.NewBaseTool(core.ToolDefinition{ToolName: "slow"}, func(ctx context.Context, p *core.ToolParams) (*core.ToolResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}))
+	return engine.NewEngine(&client, registry), registry, fake
+}
+
+func TestFanOut(t *testing.T) {
+	eng, _, fake := newTestEngine(t,
+		anthropictest.Text("Spent 120.00 on food.").WithUsage(anthropictest.Usage{InputTokens: 10, OutputTokens: 5}),
+		anthropictest.ToolUse("slow", map[string]interface{}{}).WithUsage(anthropictest.Usage{InputTokens: 7, OutputTokens: 3}),
+		anthropictest.Text("Food is fine; yield is unknown.").WithUsage(anthropictest.Usage{InputTokens: 20, OutputTokens: 10}),
+	)
+	analyst := NewSubAgent(eng, SubAgentConfig{Name: "analyst"})
+	optimizer := NewSubAgent(eng, SubAgentConfig{Name: "optimizer", AvailableTools: []string{"slow"}})
+	fanOut := NewFanOut(FanOutConfig{
+		MaxConcurrency: 1, // the fake server answers in order
+		Synthesizer:    NewSubAgent(eng, SubAgentConfig{Name: "synthesizer"}),
+	})
+
+	parent := core.NewContext("user_1", "session_1", "conv_1", "req_1")
+	out, err := fanOut.Run(context.Background(), parent, "How are my finances?", []Task{
+		{Agent: analyst, Task: "Review spending"},
+		{Agent: optimizer, Task: "Review yield", Timeout: 50 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if len(out.Results) != 2 || !out.Results[0].Success || out.Results[0].Response != "Spent 120.00 on food." {
+		t.Fatalf("results = %+v", out.Results)
+	}
+	if failed := out.Results[1]; failed.Success || failed.AgentName != "optimizer" || !strings.Contains(failed.Error, "timed out") {
+		t.Errorf("expected the optimizer to time out, got %+v", failed)
+	}
+	if out.Synthesis == nil || out.Response() != "Food is fine; yield is unknown." {
+		t.Errorf("synthesis = %+v", out.Synthesis)
+	}
+	if out.TokensUsed != (core.TokenUsage{InputTokens: 37, OutputTokens: 18}) {
+		t.Errorf("tokens = %+v", out.TokensUsed)
+	}
+
+	requests := fake.Requests()
+	synthesis := string(requests[len(requests)-1].Body)
+	for _, want := range []string{"How are my finances?", "## analyst", "Spent 120.00 on food.", "## optimizer (failed)"} {
+		if !strings.Contains(synthesis, want) {
+			t.Errorf("synthesizer task is missing %q: %s", want, synthesis)
+		}
+	}
+
+	if _, err := fanOut.Run(context.Background(), parent, "", nil); err == nil {
+		t.Error("expected an error without tasks")
+	}
+}
+
+func TestFanOutFailedTokens(t *testing.T) {
+	eng, registry, _ := newTestEngine(t,
+		anthropictest.ToolUse("quick", map[string]interface{}{}).WithUsage(anthropictest.Usage{InputTokens: 7, OutputTokens: 3}),
+		anthropictest.Error(http.StatusBadRequest, "invalid_request_error", "prompt is too long"),
+	)
+	registry.Register(core.NewBaseTool(core.ToolDefinition{ToolName: "quick"}, func(ctx context.Context, p *core.ToolParams) (*core.ToolResult, error) {
+		return &core.ToolResult{Success: true}, nil
+	}))
+	agent := NewSubAgent(eng, SubAgentConfig{Name: "analyst", AvailableTools: []string{"quick"}})
+
+	out, err := NewFanOut(FanOutConfig{}).Run(context.Background(), core.NewContext("user_1", "", "", "req_1"), "", []Task{{Agent: agent, Task: "a"}})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := core.TokenUsage{InputTokens: 7, OutputTokens: 3}
+	if failed := out.Results[0]; failed.Success || !strings.Contains(failed.Error, "sub-agent error") || failed.TokensUsed != want {
+		t.Errorf("expected the failed sub-agent's tokens, got %+v", failed)
+	}
+	if out.TokensUsed != want {
+		t.Errorf("tokens = %+v", out.TokensUsed)
+	}
+}
+
+func TestFanOutConcurrent(t *testing.T) {
+	eng, _, _ := newTestEngine(t, anthropictest.Text("one"), anthropictest.Text("two"), anthropictest.Text("three"))
+	fanOut := NewFanOut(FanOutConfig{})
+	if fanOut.maxConcurrency != DefaultFanOutConcurrency {
+		t.Errorf("max concurrency = %d", fanOut.maxConcurrency)
+	}
+	agent := NewSubAgent(eng, SubAgentConfig{Name: "analyst"})
+
+	out, err := fanOut.Run(context.Background(), core.NewContext("user_1", "", "", "req_1"), "", []Task{
+		{Agent: agent, Task: "a"}, {Agent: agent, Task: "b"}, {Agent: agent, Task: "c"},
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	got := map[string]bool{}
+	for _, result := range out.Results {
+		got[result.Response] = result.Success
+	}
+	if !got["one"] || !got["two"] || !got["three"] || out.Synthesis != nil {
+		t.Errorf("results = %v", got)
+	}
+
+	ids := subRequestIDs("req_1", []Task{{Agent: agent}, {Agent: NewSubAgent(eng, SubAgentConfig{Name: "optimizer"})}, {Agent: agent}})
+	if strings.Join(ids, ",") != "req_1-analyst-1,req_1-optimizer,req_1-analyst-2" {
+		t.Errorf("request IDs = %v", ids)
+	}
+}
+
+func TestFanOutTool(t *testing.T) {
+	eng, registry, fake := newTestEngine(t,
+		anthropictest.ToolUse("delegate_in_parallel", map[string]interface{}{
+			"query": "How is my spending?",
+			"tasks": []map[string]string{{"agent": "analyst", "task": "Review spending"}},
+		}).WithUsage(anthropictest.Usage{InputTokens: 100, OutputTokens: 10}),
+		anthropictest.Text("Spending is on track.").WithUsage(anthropictest.Usage{InputTokens: 10, OutputTokens: 5}),
+		anthropictest.Text("You're on track.").WithUsage(anthropictest.Usage{InputTokens: 100, OutputTokens: 10}),
+	)
+	tool := NewFanOutTool(FanOutToolConfig{Agents: []*SubAgent{
+		NewSubAgent(eng, SubAgentConfig{Name: "analyst"}),
+		NewSubAgent(eng, SubAgentConfig{Name: "optimizer"}),
+	}})
+	registry.Register(tool)
+
+	out, err := eng.Run(context.Background(), &engine.Input{
+		UserMessage: "how am I doing?",
+		Context:     core.NewContext("user_1", "session_1", "conv_1", "req_1"),
+		Model:       "claude-test",
+	})
+	if err != nil || out.Type != engine.OutputComplete {
+		t.Fatalf("Run = %+v, %v", out, err)
+	}
+	if out.TokensUsed != (core.TokenUsage{InputTokens: 210, OutputTokens: 25}) {
+		t.Errorf("expected sub-agent tokens in the parent output, got %+v", out.TokensUsed)
+	}
+	requests := fake.Requests()
+	if last := string(requests[len(requests)-1].Body); !strings.Contains(last, `## analyst\\nSpending is on track.`) {
+		t.Errorf("expected the analyst's response in the tool result: %s", last)
+	}
+
+	result, _ := tool.Execute(context.Background(), &core.ToolParams{
+		UserID: "user_1",
+		Input:  json.RawMessage(`{"query":"q","tasks":[{"agent":"lawyer","task":"t"}]}`),
+	})
+	if result.Success || result.Error != `unknown agent "lawyer"; available: analyst, optimizer` {
+		t.Errorf("result = %+v", result)
+	}
+
+	// The model can't start more tasks than there are agents
+	result, _ = tool.Execute(context.Background(), &core.ToolParams{
+		UserID: "user_1",
+		Input:  json.RawMessage(`{"query":"q","tasks":[{"agent":"analyst","task":"a"},{"agent":"analyst","task":"b"},{"agent":"optimizer","task":"c"}]}`),
+	})
+	if result.Success || result.Error != "too many tasks: 3, at most 2" {
+		t.Errorf("result = %+v", result)
+	}
+	tasks := tool.Schema()["properties"].(map[string]interface{})["tasks"].(map[string]interface{})
+	if tasks["maxItems"] != 2 {
+		t.Errorf("tasks schema = %v", tasks)
+	}
+}
@@ -0,0 +1,215 @@
+package subagent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// FanOutTool wraps several sub-agents as a tool that runs them in parallel.
+type FanOutTool struct {
+	agents        map[string]*SubAgent
+	order         []string
+	maxTasks      int
+	fanOut        *FanOut
+	taskFormatter func(agent, query string) string
+	definition    core.ToolDefinition
+}
+
+// Verify FanOutTool implements core.Tool.
+var _ core.Tool = (*FanOutTool)(nil)
+
+// FanOutToolConfig configures a fan-out tool.
+type FanOutToolConfig struct {
+	// Agents are the sub-agents the tool can run.
+	Agents []*SubAgent
+
+	// ToolName overrides the tool name. Defaults to "delegate_in_parallel".
+	ToolName string
+
+	// Description overrides the tool description.
+	Description string
+
+	// TaskFormatter formats the query into each agent's task when the
+	// model doesn't give one. If nil, the query is passed directly.
+	TaskFormatter func(agent, query string) string
+
+	// MaxTasks caps the tasks the model can give in one call. Defaults to
+	// the number of agents.
+	MaxTasks int
+
+	// FanOut configures timeouts, concurrency and synthesis.
+	FanOut FanOutConfig
+}
+
+// NewFanOutTool creates a tool that delegates to several sub-agents at once.
+// The model passes a query, and optionally a task for each agent it wants
+// to run; without tasks, every agent gets the query.
+func NewFanOutTool(cfg FanOutToolConfig) *FanOutTool {
+	toolName := cfg.ToolName
+	if toolName == "" {
+		toolName = "delegate_in_parallel"
+	}
+
+	agents := make(map[string]*SubAgent, len(cfg.Agents))
+	order := make([]string, 0, len(cfg.Agents))
+	for _, agent := range cfg.Agents {
+		if _, ok := agents[agent.Name()]; !ok {
+			order = append(order, agent.Name())
+		}
+		agents[agent.Name()] = agent
+	}
+
+	maxTasks := cfg.MaxTasks
+	if maxTasks <= 0 {
+		maxTasks = len(order)
+	}
+
+	description := cfg.Description
+	if description == "" {
+		description = fmt.Sprintf("Delegate tasks to several specialist agents (%s) at once and combine their answers. "+
+			"Use this when a request needs more than one specialist.", strings.Join(order, ", "))
+	}
+
+	return &FanOutTool{
+		agents:        agents,
+		order:         order,
+		maxTasks:      maxTasks,
+		fanOut:        NewFanOut(cfg.FanOut),
+		taskFormatter: cfg.TaskFormatter,
+		definition: core.ToolDefinition{
+			ToolName:        toolName,
+			ToolDescription: description,
+			InputSchema: tools.ObjectSchema(map[string]interface{}{
+				"query": tools.StringProperty("The overall question or request."),
+				"tasks": tools.ArrayProperty(
+					"Optional sub-task for each specialist to run. If omitted, every specialist gets the query.",
+					tools.ObjectSchema(map[string]interface{}{
+						"agent": tools.StringEnumProperty("The specialist to run.", order...),
+						"task":  tools.StringProperty("The specialist's part of the request."),
+					}, "agent", "task"),
+					tools.MaxItems(maxTasks),
+				),
+			}, "query"),
+		},
+	}
+}
+
+// Name returns the tool's name.
+func (f *FanOutTool) Name() string {
+	return f.definition.ToolName
+}
+
+// Description returns the tool's description.
+func (f *FanOutTool) Description() string {
+	return f.definition.ToolDescription
+}
+
+// Schema returns the tool's input schema.
+func (f *FanOutTool) Schema() map[string]interface{} {
+	return f.definition.InputSchema
+}
+
+// RequiresConfirmation returns false - delegation doesn't require confirmation.
+func (f *FanOutTool) RequiresConfirmation() bool {
+	return false
+}
+
+// Execute runs the sub-agents and returns their combined response. It
+// succeeds if at least one sub-agent did.
+func (f *FanOutTool) Execute(ctx context.Context, params *core.ToolParams) (*core.ToolResult, error) {
+	// Parse input
+	var input struct {
+		Query string `json:"query"`
+		Tasks []struct {
+			Agent string `json:"agent"`
+			Task  string `json:"task"`
+		} `json:"tasks"`
+	}
+	if err := json.Unmarshal(params.Input, &input); err != nil {
+		return &core.ToolResult{
+			Success: false,
+			Error:   fmt.Sprintf("invalid input: %v", err),
+		}, nil
+	}
+
+	if input.Query == "" {
+		return &core.ToolResult{
+			Success: false,
+			Error:   "query is required",
+		}, nil
+	}
+
+	if len(input.Tasks) > f.maxTasks {
+		return &core.ToolResult{
+			Success: false,
+			Error:   fmt.Sprintf("too many tasks: %d, at most %d", len(input.Tasks), f.maxTasks),
+		}, nil
+	}
+
+	// Build tasks
+	var tasks []Task
+	for _, t := range input.Tasks {
+		agent, ok := f.agents[t.Agent]
+		if !ok {
+			return &core.ToolResult{
+				Success: false,
+				Error:   fmt.Sprintf("unknown agent %q; available: %s", t.Agent, strings.Join(f.order, ", ")),
+			}, nil
+		}
+		tasks = append(tasks, Task{Agent: agent, Task: t.Task})
+	}
+	if len(tasks) == 0 {
+		for _, name := range f.order {
+			task := input.Query
+			if f.taskFormatter != nil {
+				task = f.taskFormatter(name, input.Query)
+			}
+			tasks = append(tasks, Task{Agent: f.agents[name], Task: task})
+		}
+	}
+
+	// Run sub-agents in contexts derived from the parent's
+	out, err := f.fanOut.Run(ctx, parentContext(ctx, params), input.Query, tasks)
+	if err != nil {
+		return &core.ToolResult{
+			Success: false,
+			Error:   fmt.Sprintf("sub-agent error: %v", err),
+		}, nil
+	}
+
+	var failed []string
+	for _, result := range out.Results {
+		if !result.Success {
+			failed = append(failed, fmt.Sprintf("%s: %s", result.AgentName, result.Error))
+		}
+	}
+	if len(failed) == len(out.Results) {
+		return &core.ToolResult{
+			Success:    false,
+			Error:      "all sub-agents failed: " + strings.Join(failed, "; "),
+			TokensUsed: &out.TokensUsed,
+		}, nil
+	}
+
+	return &core.ToolResult{
+		Success: true,
+		Data:    out.Response(),
+		Metadata: map[string]interface{}{
+			"agents":      len(out.Results),
+			"failed":      len(failed),
+			"synthesized": out.Synthesis != nil && out.Synthesis.Success,
+			"tokens_used": out.TokensUsed.TotalTokens(),
+		},
+		TokensUsed: &out.TokensUsed,
+	}, nil
+}
+
+// GetSummary returns a summary of the delegation.
+func (f *FanOutTool) GetSummary(input json.RawMessage) string {
+	return fmt.Sprintf("Delegate to %s specialists", strings.Join(f.order, ", "))
+}
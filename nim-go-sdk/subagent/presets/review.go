@@ -0,0 +1,45 @@
+package presets
+
+import (
+	"github.com/becomeliminal/nim-go-sdk/engine"
+	"github.com/becomeliminal/nim-go-sdk/subagent"
+)
+
+// SynthesizerSystemPrompt is the system prompt for the sub-agent that
+// combines specialist results.
+const SynthesizerSystemPrompt = `You combine the findings of financial specialist agents into one answer.
+
+Guidelines:
+- Lead with what matters most to the user
+- Reconcile overlapping findings instead of repeating them
+- If a specialist failed, say which part of the answer is missing
+- Don't add facts the specialists didn't report; use tools only to check a figure
+
+Available tools: get_transactions, get_savings_balance`
+
+// NewSynthesizer creates a sub-agent that combines specialist results.
+func NewSynthesizer(eng *engine.Engine) *subagent.SubAgent {
+	return subagent.NewSubAgent(eng, subagent.SubAgentConfig{
+		Name:         "synthesizer",
+		SystemPrompt: SynthesizerSystemPrompt,
+		AvailableTools: []string{
+			"get_transactions",
+			"get_savings_balance",
+		},
+		MaxTurns:  3,
+		MaxTokens: 1024,
+	})
+}
+
+// NewFinancialReviewTool creates a tool that runs the analyst and the
+// optimizer in parallel and combines their findings.
+func NewFinancialReviewTool(eng *engine.Engine) *subagent.FanOutTool {
+	return subagent.NewFanOutTool(subagent.FanOutToolConfig{
+		Agents:      []*subagent.SubAgent{NewAnalyst(eng), NewOptimizer(eng)},
+		ToolName:    "review_finances",
+		Description: "Review the user's finances with the analyst and optimizer specialists at once. Use this for broad questions that cover both spending and savings yield.",
+		FanOut: subagent.FanOutConfig{
+			Synthesizer: NewSynthesizer(eng),
+		},
+	})
+}
@@ -0,0 +1,830 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/becomeliminal/nim-go-sdk/core/money"
+)
+
+// Limits that keep calculator evaluation small and bounded.
+const (
+	calcMaxLength   = 4096 // characters in an expression
+	calcMaxDepth    = 64   // nesting of parentheses, unary operators and calls
+	calcMaxScale    = 40   // decimal places kept for inexact results
+	calcMaxExponent = 400  // decimal exponent of the largest magnitude
+	calcExactPower  = 64   // integer powers up to this are computed exactly
+	calcFloatPrec   = 256  // bits used for fractional powers and roots
+)
+
+var (
+	calcMaxMagnitude = new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(calcMaxExponent), nil))
+	calcMaxLog       = 921.0 // just under ln(10^400)
+)
+
+// roundingModes lists the rounding modes by name, for the tool schema.
+var roundingModes = func() []string {
+	names := make([]string, len(money.RoundingModes))
+	for i, mode := range money.RoundingModes {
+		names[i] = string(mode)
+	}
+	return names
+}()
+
+// roundRat rounds x to places decimal places.
+func roundRat(x *big.Rat, places int, mode money.RoundingMode) *big.Rat {
+	return money.DecimalFromRat(x, int32(places), mode).Rat()
+}
+
+// formatRat formats x with exactly places decimal places, or, if places is
+// negative, with as few as needed up to 20.
+func formatRat(x *big.Rat, places int, mode money.RoundingMode) string {
+	if places >= 0 {
+		return money.DecimalFromRat(x, int32(places), mode).String()
+	}
+	s := money.DecimalFromRat(x, 20, mode).String()
+	if strings.Contains(s, ".") {
+		s = strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// calcError is an evaluation error at a 1-based character position.
+type calcError struct {
+	pos int
+	msg string
+}
+
+func (e *calcError) Error() string {
+	if e.pos > 0 {
+		return fmt.Sprintf("%s at position %d", e.msg, e.pos)
+	}
+	return e.msg
+}
+
+type calcTokenKind int
+
+const (
+	tokEOF calcTokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+)
+
+type calcToken struct {
+	kind calcTokenKind
+	text string
+	pos  int
+}
+
+func (t calcToken) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// tokenize splits an expression into numbers, identifiers and operators.
+// Newlines separate statements like ";".
+func tokenize(src string) ([]calcToken, error) {
+	var tokens []calcToken
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		pos := i + 1
+		switch {
+		case c == '\n':
+			tokens = append(tokens, calcToken{tokOp, ";", pos})
+			i++
+		case unicode.IsSpace(c):
+			i++
+		case c >= '0' && c <= '9' || c == '.':
+			start := i
+			for i < len(runes) && (runes[i] >= '0' && runes[i] <= '9' || runes[i] == '.' || runes[i] == '_') {
+				i++
+			}
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				j := i + 1
+				if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+					j++
+				}
+				if j < len(runes) && runes[j] >= '0' && runes[j] <= '9' {
+					for j < len(runes) && runes[j] >= '0' && runes[j] <= '9' {
+						j++
+					}
+					i = j
+				}
+			}
+			tokens = append(tokens, calcToken{tokNumber, string(runes[start:i]), pos})
+		case c == '_' || unicode.IsLetter(c):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, calcToken{tokIdent, string(runes[start:i]), pos})
+		case c == '*' && i+1 < len(runes) && runes[i+1] == '*':
+			tokens = append(tokens, calcToken{tokOp, "^", pos})
+			i += 2
+		case strings.ContainsRune("+-*/^%(),=;", c):
+			tokens = append(tokens, calcToken{tokOp, string(c), pos})
+			i++
+		default:
+			return nil, &calcError{pos, fmt.Sprintf("unexpected character %q", c)}
+		}
+	}
+	return append(tokens, calcToken{tokEOF, "", len(runes) + 1}), nil
+}
+
+// parseNumber parses a decimal literal such as "1_000.50" or "2.5e3".
+func parseNumber(text string) (*big.Rat, error) {
+	clean := strings.ReplaceAll(text, "_", "")
+	mantissa, exponent, hasExp := strings.Cut(strings.ToLower(clean), "e")
+	if mantissa == "" || mantissa == "." || strings.Count(mantissa, ".") > 1 || strings.HasPrefix(clean, "_") {
+		return nil, fmt.Errorf("invalid number %q", text)
+	}
+	if hasExp {
+		exp := strings.TrimLeft(exponent, "+-0")
+		if len(exp) > 3 || len(exp) == 3 && exp > "400" {
+			return nil, fmt.Errorf("number %q is too large", text)
+		}
+	}
+	x, ok := new(big.Rat).SetString(clean)
+	if !ok {
+		return nil, fmt.Errorf("invalid number %q", text)
+	}
+	return x, nil
+}
+
+// calculator evaluates expressions with exact rational arithmetic.
+type calculator struct {
+	tokens []calcToken
+	pos    int
+	depth  int
+	vars   map[string]*big.Rat
+	mode   money.RoundingMode
+}
+
+// evaluate runs a program of ";"-separated statements. Statements are
+// expressions or assignments ("rate = 4.5%"); the value of the last one is
+// the result.
+func (c *calculator) evaluate(src string) (*big.Rat, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, &calcError{0, "expression is empty"}
+	}
+	if len(src) > calcMaxLength {
+		return nil, &calcError{0, fmt.Sprintf("expression is longer than %d characters", calcMaxLength)}
+	}
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	c.tokens, c.pos = tokens, 0
+
+	var result *big.Rat
+	for c.peek().kind != tokEOF {
+		if c.accept(";") {
+			continue
+		}
+		value, err := c.statement()
+		if err != nil {
+			return nil, err
+		}
+		result = value
+		if tok := c.peek(); tok.kind != tokEOF && !c.accept(";") {
+			return nil, &calcError{tok.pos, fmt.Sprintf("unexpected %s", tok)}
+		}
+	}
+	if result == nil {
+		return nil, &calcError{0, "expression is empty"}
+	}
+	return result, nil
+}
+
+func (c *calculator) peek() calcToken { return c.tokens[c.pos] }
+
+func (c *calculator) next() calcToken {
+	tok := c.tokens[c.pos]
+	if tok.kind != tokEOF {
+		c.pos++
+	}
+	return tok
+}
+
+// accept consumes the operator op if it comes next.
+func (c *calculator) accept(op string) bool {
+	if tok := c.peek(); tok.kind == tokOp && tok.text == op {
+		c.pos++
+		return true
+	}
+	return false
+}
+
+func (c *calculator) statement() (*big.Rat, error) {
+	tok := c.peek()
+	if tok.kind == tokIdent && c.tokens[c.pos+1].kind == tokOp && c.tokens[c.pos+1].text == "=" {
+		if _, ok := calcFunctions[tok.text]; ok {
+			return nil, &calcError{tok.pos, fmt.Sprintf("cannot assign to function %s", tok.text)}
+		}
+		c.pos += 2
+		value, err := c.expr()
+		if err != nil {
+			return nil, err
+		}
+		c.vars[tok.text] = value
+		return value, nil
+	}
+	return c.expr()
+}
+
+// expr parses sums: term (("+" | "-") term)*.
+func (c *calculator) expr() (*big.Rat, error) {
+	left, err := c.term()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := c.peek()
+		switch {
+		case c.accept("+"):
+			right, err := c.term()
+			if err != nil {
+				return nil, err
+			}
+			if left, err = checked(new(big.Rat).Add(left, right), tok.pos); err != nil {
+				return nil, err
+			}
+		case c.accept("-"):
+			right, err := c.term()
+			if err != nil {
+				return nil, err
+			}
+			if left, err = checked(new(big.Rat).Sub(left, right), tok.pos); err != nil {
+				return nil, err
+			}
+		default:
+			return left, nil
+		}
+	}
+}
+
+// term parses products: unary (("*" | "/") unary)*.
+func (c *calculator) term() (*big.Rat, error) {
+	left, err := c.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := c.peek()
+		switch {
+		case c.accept("*"):
+			right, err := c.unary()
+			if err != nil {
+				return nil, err
+			}
+			if left, err = checked(new(big.Rat).Mul(left, right), tok.pos); err != nil {
+				return nil, err
+			}
+		case c.accept("/"):
+			right, err := c.unary()
+			if err != nil {
+				return nil, err
+			}
+			if right.Sign() == 0 {
+				return nil, &calcError{tok.pos, "division by zero"}
+			}
+			if left, err = checked(new(big.Rat).Quo(left, right), tok.pos); err != nil {
+				return nil, err
+			}
+		default:
+			return left, nil
+		}
+	}
+}
+
+// unary parses signs: ("-" | "+") unary | power.
+func (c *calculator) unary() (*big.Rat, error) {
+	if c.depth++; c.depth > calcMaxDepth {
+		return nil, &calcError{c.peek().pos, "expression is nested too deeply"}
+	}
+	defer func() { c.depth-- }()
+
+	if c.accept("-") {
+		x, err := c.unary()
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Rat).Neg(x), nil
+	}
+	if c.accept("+") {
+		return c.unary()
+	}
+	return c.power()
+}
+
+// power parses right-associative exponents: postfix ("^" unary)?.
+func (c *calculator) power() (*big.Rat, error) {
+	base, err := c.postfix()
+	if err != nil {
+		return nil, err
+	}
+	tok := c.peek()
+	if !c.accept("^") {
+		return base, nil
+	}
+	exp, err := c.unary()
+	if err != nil {
+		return nil, err
+	}
+	result, err := pow(base, exp)
+	if err != nil {
+		return nil, &calcError{tok.pos, err.Error()}
+	}
+	return checked(result, tok.pos)
+}
+
+// postfix parses percentages: primary "%"*. "5%" is 0.05.
+func (c *calculator) postfix() (*big.Rat, error) {
+	x, err := c.primary()
+	if err != nil {
+		return nil, err
+	}
+	for c.accept("%") {
+		x = new(big.Rat).Quo(x, big.NewRat(100, 1))
+	}
+	return x, nil
+}
+
+// primary parses numbers, variables, calls and parentheses.
+func (c *calculator) primary() (*big.Rat, error) {
+	tok := c.next()
+	switch {
+	case tok.kind == tokNumber:
+		x, err := parseNumber(tok.text)
+		if err != nil {
+			return nil, &calcError{tok.pos, err.Error()}
+		}
+		return checked(x, tok.pos)
+
+	case tok.kind == tokIdent:
+		if fn, ok := calcFunctions[tok.text]; ok {
+			return c.call(tok, fn)
+		}
+		x, ok := c.vars[tok.text]
+		if !ok {
+			return nil, &calcError{tok.pos, fmt.Sprintf("unknown variable %s", tok.text)}
+		}
+		return x, nil
+
+	case tok.kind == tokOp && tok.text == "(":
+		x, err := c.expr()
+		if err != nil {
+			return nil, err
+		}
+		if !c.accept(")") {
+			return nil, &calcError{c.peek().pos, fmt.Sprintf("expected \")\" but found %s", c.peek())}
+		}
+		return x, nil
+	}
+	return nil, &calcError{tok.pos, fmt.Sprintf("unexpected %s", tok)}
+}
+
+// call evaluates a function call's arguments and applies the function.
+func (c *calculator) call(name calcToken, fn calcFunction) (*big.Rat, error) {
+	if !c.accept("(") {
+		return nil, &calcError{name.pos, fmt.Sprintf("function %s must be called with parentheses", name.text)}
+	}
+	var args []*big.Rat
+	if !c.accept(")") {
+		for {
+			arg, err := c.expr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if c.accept(")") {
+				break
+			}
+			if !c.accept(",") {
+				return nil, &calcError{c.peek().pos, fmt.Sprintf("expected \",\" or \")\" but found %s", c.peek())}
+			}
+		}
+	}
+
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return nil, &calcError{name.pos, fmt.Sprintf("%s expects %s", name.text, fn.arity())}
+	}
+	result, err := fn.eval(c, args)
+	if err != nil {
+		return nil, &calcError{name.pos, fmt.Sprintf("%s: %v", name.text, err)}
+	}
+	return checked(result, name.pos)
+}
+
+// checked rejects results that are too large and bounds the precision of
+// results with very large denominators.
+func checked(x *big.Rat, pos int) (*big.Rat, error) {
+	if new(big.Rat).Abs(x).Cmp(calcMaxMagnitude) > 0 {
+		return nil, &calcError{pos, "result is too large"}
+	}
+	if x.Denom().BitLen() > 4*calcMaxScale {
+		x = roundRat(x, calcMaxScale, money.RoundHalfEven)
+	}
+	return x, nil
+}
+
+// pow raises base to exp. Small integer powers are exact; others are
+// computed with calcFloatPrec bits and rounded to calcMaxScale places.
+func pow(base, exp *big.Rat) (*big.Rat, error) {
+	if exp.IsInt() && exp.Num().IsInt64() && abs64(exp.Num().Int64()) <= calcExactPower {
+		n := exp.Num().Int64()
+		if base.Sign() == 0 && n < 0 {
+			return nil, errors.New("division by zero")
+		}
+		result := new(big.Rat).SetInt64(1)
+		for i := int64(0); i < abs64(n); i++ {
+			result.Mul(result, base)
+			if new(big.Rat).Abs(result).Cmp(calcMaxMagnitude) > 0 {
+				return nil, errors.New("result is too large")
+			}
+		}
+		if n < 0 {
+			result.Inv(result)
+		}
+		return result, nil
+	}
+
+	switch base.Sign() {
+	case 0:
+		if exp.Sign() < 0 {
+			return nil, errors.New("division by zero")
+		}
+		return new(big.Rat), nil
+	case -1:
+		if !exp.IsInt() {
+			return nil, errors.New("cannot raise a negative number to a fractional power")
+		}
+		result, err := pow(new(big.Rat).Neg(base), exp)
+		if err != nil {
+			return nil, err
+		}
+		if exp.Num().Bit(0) == 1 {
+			result.Neg(result)
+		}
+		return result, nil
+	}
+
+	// base^exp = e^(exp * ln(base))
+	prec := uint(calcFloatPrec)
+	y := new(big.Float).SetPrec(prec).SetRat(exp)
+	y.Mul(y, floatLog(new(big.Float).SetPrec(prec).SetRat(base)))
+	if f, _ := y.Float64(); f > calcMaxLog {
+		return nil, errors.New("result is too large")
+	} else if f < -calcMaxLog {
+		return new(big.Rat), nil
+	}
+	result, _ := floatExp(y).Rat(nil)
+	return roundRat(result, calcMaxScale, money.RoundHalfEven), nil
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// floatExp returns e^x. It halves x until it is small, sums the Taylor
+// series and squares the result back.
+func floatExp(x *big.Float) *big.Float {
+	prec := x.Prec() + 64
+	r := new(big.Float).SetPrec(prec).Set(x)
+	halvings := 0
+	for r.Sign() != 0 && r.MantExp(nil) > -8 {
+		r.SetMantExp(r, -1)
+		halvings++
+	}
+
+	sum := new(big.Float).SetPrec(prec).SetInt64(1)
+	term := new(big.Float).SetPrec(prec).SetInt64(1)
+	for i := int64(1); ; i++ {
+		term.Mul(term, r)
+		term.Quo(term, new(big.Float).SetPrec(prec).SetInt64(i))
+		if term.Sign() == 0 || term.MantExp(nil) < sum.MantExp(nil)-int(prec) {
+			break
+		}
+		sum.Add(sum, term)
+	}
+	for ; halvings > 0; halvings-- {
+		sum.Mul(sum, sum)
+	}
+	return sum.SetPrec(x.Prec())
+}
+
+// floatLog returns ln(x) for x > 0, as ln(m) + e*ln(2) where x = m * 2^e.
+func floatLog(x *big.Float) *big.Float {
+	prec := x.Prec() + 64
+	m := new(big.Float).SetPrec(prec)
+	e := x.MantExp(m)
+
+	result := floatAtanhLog(m, prec)
+	if e != 0 {
+		ln2 := floatAtanhLog(new(big.Float).SetPrec(prec).SetInt64(2), prec)
+		result.Add(result, ln2.Mul(ln2, new(big.Float).SetPrec(prec).SetInt64(int64(e))))
+	}
+	return result.SetPrec(x.Prec())
+}
+
+// floatAtanhLog returns ln(y) = 2 atanh((y-1)/(y+1)), which converges
+// quickly for y near 1.
+func floatAtanhLog(y *big.Float, prec uint) *big.Float {
+	one := new(big.Float).SetPrec(prec).SetInt64(1)
+	z := new(big.Float).SetPrec(prec).Sub(y, one)
+	z.Quo(z, new(big.Float).SetPrec(prec).Add(y, one))
+	z2 := new(big.Float).SetPrec(prec).Mul(z, z)
+
+	sum := new(big.Float).SetPrec(prec).Set(z)
+	power := new(big.Float).SetPrec(prec).Set(z)
+	for k := int64(3); sum.Sign() != 0; k += 2 {
+		power.Mul(power, z2)
+		term := new(big.Float).SetPrec(prec).Quo(power, new(big.Float).SetPrec(prec).SetInt64(k))
+		if term.Sign() == 0 || term.MantExp(nil) < sum.MantExp(nil)-int(prec) {
+			break
+		}
+		sum.Add(sum, term)
+	}
+	return sum.Mul(sum, new(big.Float).SetPrec(prec).SetInt64(2))
+}
+
+// calcFunction is a function callable from expressions.
+type calcFunction struct {
+	minArgs, maxArgs int // maxArgs is -1 for any number
+	params           string
+	description      string
+	eval             func(c *calculator, args []*big.Rat) (*big.Rat, error)
+}
+
+func (f calcFunction) arity() string {
+	switch {
+	case f.maxArgs < 0:
+		return fmt.Sprintf("at least %d arguments", f.minArgs)
+	case f.minArgs == f.maxArgs && f.minArgs == 1:
+		return "1 argument"
+	case f.minArgs == f.maxArgs:
+		return fmt.Sprintf("%d arguments", f.minArgs)
+	default:
+		return fmt.Sprintf("%d to %d arguments", f.minArgs, f.maxArgs)
+	}
+}
+
+// calcFunctions are the functions expressions can call.
+var calcFunctions = map[string]calcFunction{
+	"abs": {1, 1, "x", "absolute value", func(c *calculator, a []*big.Rat) (*big.Rat, error) {
+		return new(big.Rat).Abs(a[0]), nil
+	}},
+	"min": {1, -1, "a, b, ...", "smallest argument", func(c *calculator, a []*big.Rat) (*big.Rat, error) {
+		return extreme(a, -1), nil
+	}},
+	"max": {1, -1, "a, b, ...", "largest argument", func(c *calculator, a []*big.Rat) (*big.Rat, error) {
+		return extreme(a, 1), nil
+	}},
+	"round": {1, 2, "x, places=0", "round using the rounding mode", func(c *calculator, a []*big.Rat) (*big.Rat, error) {
+		places := 0
+		if len(a) == 2 {
+			var err error
+			if places, err = intArg(a[1], "places", 0, calcMaxScale); err != nil {
+				return nil, err
+			}
+		}
+		return roundRat(a[0], places, c.mode), nil
+	}},
+	"floor": {1, 1, "x", "round down to an integer", func(c *calculator, a []*big.Rat) (*big.Rat, error) {
+		return roundRat(a[0], 0, money.RoundFloor), nil
+	}},
+	"ceil": {1, 1, "x", "round up to an integer", func(c *calculator, a []*big.Rat) (*big.Rat, error) {
+		return roundRat(a[0], 0, money.RoundCeiling), nil
+	}},
+	"sqrt": {1, 1, "x", "square root", func(c *calculator, a []*big.Rat) (*big.Rat, error) {
+		if a[0].Sign() < 0 {
+			return nil, errors.New("cannot take the square root of a negative number")
+		}
+		return pow(a[0], big.NewRat(1, 2))
+	}},
+	"pow": {2, 2, "x, y", "x raised to y", func(c *calculator, a []*big.Rat) (*big.Rat, error) {
+		return pow(a[0], a[1])
+	}},
+	"mod": {2, 2, "x, y", "remainder of x / y, with the sign of y", func(c *calculator, a []*big.Rat) (*big.Rat, error) {
+		if a[1].Sign() == 0 {
+			return nil, errors.New("division by zero")
+		}
+		q := roundRat(new(big.Rat).Quo(a[0], a[1]), 0, money.RoundFloor)
+		return new(big.Rat).Sub(a[0], q.Mul(q, a[1])), nil
+	}},
+	"compound_interest": {3, 4, "principal, rate, years, periods_per_year=12", "interest earned at a nominal annual rate", compoundInterest},
+	"apr_to_apy":        {1, 2, "apr, periods_per_year=12", "effective annual yield of a nominal rate", aprToAPY},
+	"apy_to_apr":        {1, 2, "apy, periods_per_year=12", "nominal annual rate of an effective yield", apyToAPR},
+	"loan_payment":      {3, 3, "principal, apr, months", "monthly payment of an amortising loan", loanPayment},
+	"loan_interest":     {3, 3, "principal, apr, months", "total interest paid over a loan", loanInterest},
+	"loan_balance":      {4, 4, "principal, apr, months, payments_made", "balance left after some payments", loanBalance},
+	"future_value":      {3, 4, "principal, apy, years, monthly_deposit=0", "savings balance at a vault APY", futureValue},
+}
+
+// calcFunctionHelp lists the functions for the tool description.
+func calcFunctionHelp() string {
+	names := make([]string, 0, len(calcFunctions))
+	for name := range calcFunctions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		fn := calcFunctions[name]
+		fmt.Fprintf(&b, "- %s(%s): %s\n", name, fn.params, fn.description)
+	}
+	return b.String()
+}
+
+func extreme(args []*big.Rat, sign int) *big.Rat {
+	result := args[0]
+	for _, arg := range args[1:] {
+		if arg.Cmp(result) == sign {
+			result = arg
+		}
+	}
+	return result
+}
+
+// intArg converts an argument to an int in [min, max].
+func intArg(x *big.Rat, name string, min, max int) (int, error) {
+	if !x.IsInt() || !x.Num().IsInt64() || x.Num().Int64() < int64(min) || x.Num().Int64() > int64(max) {
+		return 0, fmt.Errorf("%s must be a whole number from %d to %d", name, min, max)
+	}
+	return int(x.Num().Int64()), nil
+}
+
+// periodsArg returns the optional periods-per-year argument at i.
+func periodsArg(args []*big.Rat, i int) (*big.Rat, error) {
+	if len(args) <= i {
+		return big.NewRat(12, 1), nil
+	}
+	n, err := intArg(args[i], "periods_per_year", 1, 366)
+	if err != nil {
+		return nil, err
+	}
+	return big.NewRat(int64(n), 1), nil
+}
+
+// growth returns (1 + rate)^periods.
+func growth(rate, periods *big.Rat) (*big.Rat, error) {
+	base := new(big.Rat).Add(big.NewRat(1, 1), rate)
+	if base.Sign() <= 0 {
+		return nil, errors.New("rate must be greater than -100%")
+	}
+	return pow(base, periods)
+}
+
+// compoundInterest returns principal * ((1 + rate/n)^(n*years) - 1).
+func compoundInterest(c *calculator, a []*big.Rat) (*big.Rat, error) {
+	if a[2].Sign() < 0 {
+		return nil, errors.New("years must not be negative")
+	}
+	n, err := periodsArg(a, 3)
+	if err != nil {
+		return nil, err
+	}
+	g, err := growth(new(big.Rat).Quo(a[1], n), new(big.Rat).Mul(n, a[2]))
+	if err != nil {
+		return nil, err
+	}
+	return g.Sub(g, big.NewRat(1, 1)).Mul(g, a[0]), nil
+}
+
+// aprToAPY returns (1 + apr/n)^n - 1.
+func aprToAPY(c *calculator, a []*big.Rat) (*big.Rat, error) {
+	n, err := periodsArg(a, 1)
+	if err != nil {
+		return nil, err
+	}
+	g, err := growth(new(big.Rat).Quo(a[0], n), n)
+	if err != nil {
+		return nil, err
+	}
+	return g.Sub(g, big.NewRat(1, 1)), nil
+}
+
+// apyToAPR returns n * ((1 + apy)^(1/n) - 1).
+func apyToAPR(c *calculator, a []*big.Rat) (*big.Rat, error) {
+	n, err := periodsArg(a, 1)
+	if err != nil {
+		return nil, err
+	}
+	g, err := growth(a[0], new(big.Rat).Inv(n))
+	if err != nil {
+		return nil, err
+	}
+	return g.Sub(g, big.NewRat(1, 1)).Mul(g, n), nil
+}
+
+// loanTerms validates a loan and returns its monthly rate and term.
+func loanTerms(a []*big.Rat) (rate, months *big.Rat, err error) {
+	if a[1].Sign() < 0 {
+		return nil, nil, errors.New("apr must not be negative")
+	}
+	m, err := intArg(a[2], "months", 1, 1200)
+	if err != nil {
+		return nil, nil, err
+	}
+	return new(big.Rat).Quo(a[1], big.NewRat(12, 1)), big.NewRat(int64(m), 1), nil
+}
+
+// loanPayment returns principal * r / (1 - (1 + r)^-months), or
+// principal / months at a zero rate.
+func loanPayment(c *calculator, a []*big.Rat) (*big.Rat, error) {
+	r, months, err := loanTerms(a)
+	if err != nil {
+		return nil, err
+	}
+	if r.Sign() == 0 {
+		return new(big.Rat).Quo(a[0], months), nil
+	}
+	g, err := growth(r, new(big.Rat).Neg(months))
+	if err != nil {
+		return nil, err
+	}
+	denom := new(big.Rat).Sub(big.NewRat(1, 1), g)
+	return new(big.Rat).Quo(new(big.Rat).Mul(a[0], r), denom), nil
+}
+
+// loanInterest returns payment * months - principal.
+func loanInterest(c *calculator, a []*big.Rat) (*big.Rat, error) {
+	payment, err := loanPayment(c, a)
+	if err != nil {
+		return nil, err
+	}
+	_, months, _ := loanTerms(a)
+	return payment.Mul(payment, months).Sub(payment, a[0]), nil
+}
+
+// loanBalance returns principal * (1+r)^k - payment * ((1+r)^k - 1) / r
+// after k payments.
+func loanBalance(c *calculator, a []*big.Rat) (*big.Rat, error) {
+	payment, err := loanPayment(c, a[:3])
+	if err != nil {
+		return nil, err
+	}
+	r, months, _ := loanTerms(a)
+	k, err := intArg(a[3], "payments_made", 0, int(months.Num().Int64()))
+	if err != nil {
+		return nil, err
+	}
+	if r.Sign() == 0 {
+		paid := new(big.Rat).Mul(payment, big.NewRat(int64(k), 1))
+		return paid.Sub(a[0], paid), nil
+	}
+	g, err := growth(r, big.NewRat(int64(k), 1))
+	if err != nil {
+		return nil, err
+	}
+	owed := new(big.Rat).Mul(a[0], g)
+	paid := new(big.Rat).Sub(g, big.NewRat(1, 1))
+	paid.Mul(paid, payment).Quo(paid, r)
+	return owed.Sub(owed, paid), nil
+}
+
+// futureValue returns the balance after years at an effective annual yield,
+// with optional deposits at the end of each month:
+// principal * (1+apy)^years + deposit * ((1+m)^(12*years) - 1) / m, where
+// m = (1+apy)^(1/12) - 1 is the equivalent monthly rate.
+func futureValue(c *calculator, a []*big.Rat) (*big.Rat, error) {
+	if a[2].Sign() < 0 {
+		return nil, errors.New("years must not be negative")
+	}
+	total, err := growth(a[1], a[2])
+	if err != nil {
+		return nil, err
+	}
+	total.Mul(total, a[0])
+	if len(a) < 4 || a[3].Sign() == 0 {
+		return total, nil
+	}
+
+	months := new(big.Rat).Mul(a[2], big.NewRat(12, 1))
+	m, err := growth(a[1], big.NewRat(1, 12))
+	if err != nil {
+		return nil, err
+	}
+	m.Sub(m, big.NewRat(1, 1))
+	if m.Sign() == 0 {
+		return total.Add(total, new(big.Rat).Mul(a[3], months)), nil
+	}
+	deposits, err := growth(m, months)
+	if err != nil {
+		return nil, err
+	}
+	deposits.Sub(deposits, big.NewRat(1, 1)).Quo(deposits, m).Mul(deposits, a[3])
+	return total.Add(total, deposits), nil
+}
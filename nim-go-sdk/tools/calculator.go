@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/core/money"
+)
+
+// CalculatorToolName is the name of the calculator tool.
+const CalculatorToolName = "calculate"
+
+// CalculatorTool evaluates arithmetic and financial expressions exactly,
+// so the agent doesn't do money math in its head. Expressions are parsed by
+// a small grammar and never evaluated as code.
+type CalculatorTool struct{}
+
+// NewCalculatorTool creates a new calculator tool.
+func NewCalculatorTool() *CalculatorTool {
+	return &CalculatorTool{}
+}
+
+// Name returns the tool's name.
+func (t *CalculatorTool) Name() string {
+	return CalculatorToolName
+}
+
+// Description returns the tool's description.
+func (t *CalculatorTool) Description() string {
+	return `Evaluate arithmetic and financial calculations exactly. Use this for any math on money instead of calculating yourself.
+Supports + - * / ^ and parentheses, percentages (5% is 0.05), variables, and several statements separated by ";" where the last one is the result, e.g. "rate = 4.5%; 1000 * (1 + rate)".
+Rates are decimals or percentages: pass 4.5% for a 4.5% APY. Functions:
+` + calcFunctionHelp()
+}
+
+// Schema returns the tool's input schema.
+func (t *CalculatorTool) Schema() map[string]interface{} {
+	return ObjectSchema(map[string]interface{}{
+		"expression": StringProperty("Expression to evaluate, e.g. 'loan_payment(20000, 6.5%, 60)'"),
+		"variables": map[string]interface{}{
+			"type":                 "object",
+			"description":          "Named values the expression can use, as numbers or decimal strings, e.g. {\"balance\": \"1520.75\"}",
+			"additionalProperties": map[string]interface{}{"type": []string{"number", "string"}},
+		},
+		"decimal_places": IntegerProperty("Round the result to this many decimal places, e.g. 2 for money", Minimum(0), Maximum(calcMaxScale)),
+		"rounding":       StringEnumProperty("Rounding mode for round() and decimal_places (default half_even)", roundingModes...),
+	}, "expression")
+}
+
+// RequiresConfirmation returns false - calculations have no side effects.
+func (t *CalculatorTool) RequiresConfirmation() bool {
+	return false
+}
+
+// variablePattern matches valid variable names.
+var variablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Execute evaluates the expression.
+func (t *CalculatorTool) Execute(ctx context.Context, params *core.ToolParams) (*core.ToolResult, error) {
+	var input struct {
+		Expression    string                 `json:"expression"`
+		Variables     map[string]interface{} `json:"variables"`
+		DecimalPlaces *int                   `json:"decimal_places"`
+		Rounding      string                 `json:"rounding"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(params.Input))
+	dec.UseNumber()
+	if err := dec.Decode(&input); err != nil {
+		return &core.ToolResult{Success: false, Error: "invalid input: expression is required"}, nil
+	}
+
+	c := &calculator{vars: make(map[string]*big.Rat), mode: money.RoundHalfEven}
+	if input.Rounding != "" {
+		c.mode = money.RoundingMode(input.Rounding)
+		if !c.mode.Valid() {
+			return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: unknown rounding mode %q", input.Rounding)}, nil
+		}
+	}
+	places := -1
+	if input.DecimalPlaces != nil {
+		places = *input.DecimalPlaces
+		if places < 0 || places > calcMaxScale {
+			return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: decimal_places must be from 0 to %d", calcMaxScale)}, nil
+		}
+	}
+	// Check variables in name order so errors are deterministic
+	names := make([]string, 0, len(input.Variables))
+	for name := range input.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		x, err := variableValue(name, input.Variables[name])
+		if err != nil {
+			return &core.ToolResult{Success: false, Error: "invalid input: " + err.Error()}, nil
+		}
+		c.vars[name] = x
+	}
+
+	result, err := c.evaluate(input.Expression)
+	if err != nil {
+		return &core.ToolResult{Success: false, Error: err.Error()}, nil
+	}
+	return &core.ToolResult{
+		Success: true,
+		Data: map[string]interface{}{
+			"expression": input.Expression,
+			"result":     formatRat(result, places, c.mode),
+		},
+	}, nil
+}
+
+// GetSummary returns a summary (not used, as calculations need no confirmation).
+func (t *CalculatorTool) GetSummary(input json.RawMessage) string {
+	return "Calculate"
+}
+
+// variableValue validates an input variable.
+func variableValue(name string, value interface{}) (*big.Rat, error) {
+	if !variablePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid variable name %q", name)
+	}
+	if _, ok := calcFunctions[name]; ok {
+		return nil, fmt.Errorf("variable %s has the name of a function", name)
+	}
+	var text string
+	switch v := value.(type) {
+	case json.Number:
+		text = string(v)
+	case string:
+		if !decimalPattern.MatchString(v) {
+			return nil, fmt.Errorf("variable %s must be a decimal number, got %q", name, v)
+		}
+		text = v
+	default:
+		return nil, fmt.Errorf("variable %s must be a number", name)
+	}
+	x, err := parseNumber(text)
+	if err == nil {
+		x, err = checked(x, 0)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("variable %s: %v", name, err)
+	}
+	return x, nil
+}
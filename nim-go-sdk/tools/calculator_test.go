@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+func calculate(t *testing.T, input string) *core.ToolResult {
+	t.Helper()
+	result, err := NewCalculatorTool().Execute(context.Background(), &core.ToolParams{Input: json.RawMessage(input)})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	return result
+}
+
+func TestCalculator(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`{"expression": "0.1 + 0.2"}`, "0.3"},
+		{`{"expression": "100 + 50 * 0.05"}`, "102.5"},
+		{`{"expression": "-2 ^ 2 + 2 ** 3 ** 2"}`, "508"},
+		{`{"expression": "1 / 3"}`, "0.33333333333333333333"},
+		{`{"expression": "1 / 3", "decimal_places": 2}`, "0.33"},
+		{`{"expression": "15% * 80"}`, "12"},
+		{`{"expression": "1_000.50 * 2e3"}`, "2001000"},
+		{`{"expression": "rate = 4.5%; monthly = rate / 12\n balance * monthly", "variables": {"balance": "1200.00"}}`, "4.5"},
+		{`{"expression": "round(2.5) + round(3.5) + round(2.345, 2)"}`, "8.34"},
+		{`{"expression": "round(2.5)", "rounding": "half_up"}`, "3"},
+		{`{"expression": "x", "variables": {"x": 2.675}, "decimal_places": 2, "rounding": "down"}`, "2.67"},
+		{`{"expression": "-1.5", "decimal_places": 0, "rounding": "ceiling"}`, "-1"},
+		{`{"expression": "-1.5", "decimal_places": 0, "rounding": "up"}`, "-2"},
+		{`{"expression": "floor(-1.5) + ceil(1.2) + abs(-3) + min(4, 2, 9) + max(1, 5)"}`, "10"},
+		{`{"expression": "mod(-7, 3) + sqrt(2) ^ 2", "decimal_places": 10}`, "4.0000000000"},
+		{`{"expression": "pow(16, 0.25)", "decimal_places": 6}`, "2.000000"},
+		{`{"expression": "compound_interest(1000, 5%, 10)", "decimal_places": 2}`, "647.01"},
+		{`{"expression": "compound_interest(1000, 5%, 1, 1)"}`, "50"},
+		{`{"expression": "apr_to_apy(12%)", "decimal_places": 6}`, "0.126825"},
+		{`{"expression": "apy_to_apr(apr_to_apy(6.5%, 365), 365)", "decimal_places": 10}`, "0.0650000000"},
+		{`{"expression": "loan_payment(20000, 6%, 60)", "decimal_places": 2}`, "386.66"},
+		{`{"expression": "loan_payment(1200, 0, 12)"}`, "100"},
+		{`{"expression": "loan_interest(20000, 6%, 60)", "decimal_places": 2}`, "3199.36"},
+		{`{"expression": "loan_balance(20000, 6%, 60, 60)", "decimal_places": 2}`, "0.00"},
+		{`{"expression": "future_value(500, 4.5%, 1)"}`, "522.5"},
+		{`{"expression": "future_value(0, 0, 2, 100)"}`, "2400"},
+		{`{"expression": "future_value(1000, 4.5%, 10, 100)", "decimal_places": 2}`, "16600.54"},
+	}
+	for _, tt := range tests {
+		result := calculate(t, tt.input)
+		if !result.Success {
+			t.Errorf("%s: %s", tt.input, result.Error)
+			continue
+		}
+		if got := result.Data.(map[string]interface{})["result"]; got != tt.want {
+			t.Errorf("%s = %v, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestCalculatorErrors(t *testing.T) {
+	tests := map[string]string{
+		`{"expression": ""}`:                              "expression is empty",
+		`{"expression": "1 / (2 - 2)"}`:                   "division by zero at position 3",
+		`{"expression": "2 * (3 + 4"}`:                    "expected \")\" but found end of expression at position 11",
+		`{"expression": "os.exit(1)"}`:                    "unknown variable os at position 1",
+		`{"expression": "__import__('os')"}`:              "unexpected character '\\'' at position 12",
+		`{"expression": "1 +"}`:                           "unexpected end of expression at position 4",
+		`{"expression": "2 3"}`:                           "unexpected \"3\" at position 3",
+		`{"expression": "round = 2"}`:                     "cannot assign to function round at position 1",
+		`{"expression": "round(1, 2, 3)"}`:                "round expects 1 to 2 arguments at position 1",
+		`{"expression": "round(1, 0.5)"}`:                 "round: places must be a whole number from 0 to 40 at position 1",
+		`{"expression": "(-8) ^ 0.5"}`:                    "cannot raise a negative number to a fractional power at position 6",
+		`{"expression": "10 ^ 1000"}`:                     "result is too large at position 4",
+		`{"expression": "1e500"}`:                         "number \"1e500\" is too large at position 1",
+		`{"expression": "loan_payment(100, -1%, 12)"}`:    "loan_payment: apr must not be negative at position 1",
+		`{"expression": "x", "variables": {"x": "1/3"}}`:  "invalid input: variable x must be a decimal number, got \"1/3\"",
+		`{"expression": "1", "rounding": "bankers"}`:      "invalid input: unknown rounding mode \"bankers\"",
+		`{"expression": "sqrt", "variables": {"a b": 1}}`: "invalid input: invalid variable name \"a b\"",
+		`{"expression": "((((((((((((((((((((((((((((((((((((((((((((((((((((((((((((((((((1))))))))))))))))))))))))))))))))))))))))))))))))))))))))))))))))))"}`: "expression is nested too deeply at position 65",
+	}
+	for input, want := range tests {
+		result := calculate(t, input)
+		if result.Success || result.Error != want {
+			t.Errorf("%s = %+v, want error %q", input, result, want)
+		}
+	}
+}
@@ -0,0 +1,512 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+const (
+	// defaultHTTPToolTimeout bounds each attempt when Timeout is empty.
+	defaultHTTPToolTimeout = 10 * time.Second
+
+	// defaultHTTPToolBackoff is the delay before the first retry.
+	defaultHTTPToolBackoff = 200 * time.Millisecond
+
+	// maxHTTPToolResponse bounds the response body read by an HTTP tool.
+	maxHTTPToolResponse = 4 << 20
+)
+
+// HTTPToolConfig declares a tool that calls a REST endpoint. It is usually
+// loaded from a YAML or JSON file with LoadHTTPTools:
+//
+//	tools:
+//	  - name: get_fx_rate
+//	    description: Get the exchange rate between two currencies
+//	    method: GET
+//	    url: https://fx.internal/v1/rates/{{path .base}}
+//	    headers:
+//	      Authorization: Bearer ${FX_API_TOKEN}
+//	    query:
+//	      symbols: "{{.quote}}"
+//	    input_schema:
+//	      type: object
+//	      properties:
+//	        base: {type: string, description: ISO currency code}
+//	        quote: {type: string, description: ISO currency code}
+//	      required: [base, quote]
+//	    extract:
+//	      rate: $.rates[0].rate
+//	      as_of: $.timestamp
+//	    timeout: 5s
+//	    retries: 2
+//
+// URL, Headers, Query, Body and Summary are Go text/templates executed with
+// the tool input; missing input fields render as empty strings. Templates
+// can use {{json .field}} to embed a value as JSON and {{path .field}} to
+// escape a URL path segment; query values are escaped automatically.
+// ${VAR} in URL, Headers, Query and Body is replaced with the environment
+// variable when the tool is created, so secrets stay out of the config file.
+type HTTPToolConfig struct {
+	// Name and Description identify the tool to Claude.
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description" json:"description"`
+
+	// Method is the HTTP method. Defaults to GET.
+	Method string `yaml:"method" json:"method"`
+
+	// URL is the endpoint template.
+	URL string `yaml:"url" json:"url"`
+
+	// Headers are header templates.
+	Headers map[string]string `yaml:"headers" json:"headers"`
+
+	// Query holds query parameter templates. Parameters that render empty
+	// are omitted.
+	Query map[string]string `yaml:"query" json:"query"`
+
+	// Body is a request body template that must render valid JSON, e.g.
+	// {"amount": {{json .amount}}}. If empty, no body is sent.
+	Body string `yaml:"body" json:"body"`
+
+	// InputSchema is the JSON Schema for the tool's input. If nil, the tool
+	// takes no input.
+	InputSchema map[string]interface{} `yaml:"input_schema" json:"input_schema"`
+
+	// Extract maps result fields to JSON paths into the response, such as
+	// "$.data.items[*].id". If empty, the whole response is returned.
+	Extract map[string]string `yaml:"extract" json:"extract"`
+
+	// Timeout bounds each attempt, e.g. "5s". Defaults to 10s.
+	Timeout string `yaml:"timeout" json:"timeout"`
+
+	// Retries is how many times a request is retried after network errors,
+	// 429s and 5xx responses. Only idempotent methods (GET, HEAD, PUT,
+	// DELETE, OPTIONS) are retried.
+	Retries int `yaml:"retries" json:"retries"`
+
+	// RequiresConfirmation marks a write that needs user approval.
+	RequiresConfirmation bool `yaml:"requires_confirmation" json:"requires_confirmation"`
+
+	// Summary describes a pending action for confirmation, e.g.
+	// "Open a {{.priority}} ticket: {{.title}}". If empty, Description is used.
+	Summary string `yaml:"summary" json:"summary"`
+
+	// Client sends requests. If nil, http.DefaultClient is used.
+	Client *http.Client `yaml:"-" json:"-"`
+}
+
+// LoadHTTPTools reads HTTP tool declarations from a YAML or JSON file with
+// a top-level "tools" list.
+func LoadHTTPTools(path string) ([]core.Tool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tools, err := ParseHTTPTools(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return tools, nil
+}
+
+// ParseHTTPTools parses HTTP tool declarations from YAML or JSON.
+func ParseHTTPTools(data []byte) ([]core.Tool, error) {
+	var file struct {
+		Tools []HTTPToolConfig `yaml:"tools"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid HTTP tool config: %w", err)
+	}
+
+	tools := make([]core.Tool, 0, len(file.Tools))
+	names := make(map[string]bool)
+	for i, cfg := range file.Tools {
+		tool, err := NewHTTPTool(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("tool %d: %w", i, err)
+		}
+		if names[cfg.Name] {
+			return nil, fmt.Errorf("tool %d: duplicate tool name %s", i, cfg.Name)
+		}
+		names[cfg.Name] = true
+		tools = append(tools, tool)
+	}
+	return tools, nil
+}
+
+// NewHTTPTool creates a tool from a declaration. Templates, JSON paths and
+// referenced environment variables are checked up front.
+func NewHTTPTool(cfg HTTPToolConfig) (core.Tool, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("%s: url is required", cfg.Name)
+	}
+	t := &httpTool{config: cfg, method: strings.ToUpper(cfg.Method)}
+	if t.method == "" {
+		t.method = http.MethodGet
+	}
+	if t.config.Client == nil {
+		t.config.Client = http.DefaultClient
+	}
+
+	t.timeout = defaultHTTPToolTimeout
+	if cfg.Timeout != "" {
+		d, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%s: invalid timeout %q", cfg.Name, cfg.Timeout)
+		}
+		t.timeout = d
+	}
+	if cfg.Retries < 0 {
+		return nil, fmt.Errorf("%s: retries must not be negative", cfg.Name)
+	}
+
+	t.schema = normalizeYAML(cfg.InputSchema).(map[string]interface{})
+	if t.schema == nil {
+		t.schema = ObjectSchema(map[string]interface{}{})
+	}
+
+	var err error
+	parse := func(field, text string, expand bool) *template.Template {
+		if err != nil {
+			return nil
+		}
+		if expand {
+			if text, err = expandEnv(text); err != nil {
+				err = fmt.Errorf("%s: %s: %w", cfg.Name, field, err)
+				return nil
+			}
+		}
+		tmpl, perr := template.New(field).Funcs(httpToolFuncs).Parse(text)
+		if perr != nil {
+			err = fmt.Errorf("%s: %w", cfg.Name, perr)
+		}
+		return tmpl
+	}
+
+	t.url = parse("url", cfg.URL, true)
+	t.headers = make(map[string]*template.Template, len(cfg.Headers))
+	for k, v := range cfg.Headers {
+		t.headers[k] = parse("headers."+k, v, true)
+	}
+	t.query = make(map[string]*template.Template, len(cfg.Query))
+	for k, v := range cfg.Query {
+		t.query[k] = parse("query."+k, v, true)
+	}
+	if cfg.Body != "" {
+		t.body = parse("body", cfg.Body, true)
+	}
+	if cfg.Summary != "" {
+		t.summary = parse("summary", cfg.Summary, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	t.extract = make(map[string]*jsonPath, len(cfg.Extract))
+	for field, path := range cfg.Extract {
+		compiled, err := compileJSONPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: extract %s: %w", cfg.Name, field, err)
+		}
+		t.extract[field] = compiled
+	}
+	return t, nil
+}
+
+// httpToolFuncs are the functions available to HTTP tool templates.
+var httpToolFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"path": func(v interface{}) string {
+		return url.PathEscape(fmt.Sprint(v))
+	},
+}
+
+// envPattern matches ${VAR} references.
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv replaces ${VAR} references, failing if a variable is unset.
+func expandEnv(s string) (string, error) {
+	var missing []string
+	out := envPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := envPattern.FindStringSubmatch(ref)[1]
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return v
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+// httpTool is a tool declared by an HTTPToolConfig.
+type httpTool struct {
+	config  HTTPToolConfig
+	method  string
+	schema  map[string]interface{}
+	timeout time.Duration
+
+	url     *template.Template
+	headers map[string]*template.Template
+	query   map[string]*template.Template
+	body    *template.Template
+	summary *template.Template
+	extract map[string]*jsonPath
+}
+
+// Verify httpTool implements core.Tool.
+var _ core.Tool = (*httpTool)(nil)
+
+func (t *httpTool) Name() string                   { return t.config.Name }
+func (t *httpTool) Description() string            { return t.config.Description }
+func (t *httpTool) Schema() map[string]interface{} { return t.schema }
+func (t *httpTool) RequiresConfirmation() bool     { return t.config.RequiresConfirmation }
+
+func (t *httpTool) GetSummary(input json.RawMessage) string {
+	if t.summary == nil {
+		return t.config.Description
+	}
+	data, err := t.templateData(input)
+	if err != nil {
+		return t.config.Description
+	}
+	summary, err := render(t.summary, data)
+	if err != nil {
+		return t.config.Description
+	}
+	return summary
+}
+
+// Execute renders the request, sends it with retries and extracts the
+// configured fields from the response.
+func (t *httpTool) Execute(ctx context.Context, params *core.ToolParams) (*core.ToolResult, error) {
+	data, err := t.templateData(params.Input)
+	if err != nil {
+		return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+	}
+
+	body, err := t.send(ctx, data, params.ConfirmationID)
+	if err != nil {
+		return &core.ToolResult{Success: false, Error: err.Error()}, nil
+	}
+
+	var decoded interface{}
+	if len(bytes.TrimSpace(body)) == 0 {
+		decoded = map[string]interface{}{}
+	} else if err := json.Unmarshal(body, &decoded); err != nil {
+		if len(t.extract) > 0 {
+			return &core.ToolResult{Success: false, Error: "response is not JSON"}, nil
+		}
+		return &core.ToolResult{Success: true, Data: string(body)}, nil
+	}
+	if len(t.extract) == 0 {
+		return &core.ToolResult{Success: true, Data: decoded}, nil
+	}
+
+	result := make(map[string]interface{}, len(t.extract))
+	for field, path := range t.extract {
+		v, err := path.get(decoded)
+		if err != nil {
+			return &core.ToolResult{Success: false, Error: err.Error()}, nil
+		}
+		result[field] = v
+	}
+	return &core.ToolResult{Success: true, Data: result}, nil
+}
+
+// templateData decodes input for templates. Schema properties missing
+// from the input are set to "" so they render empty.
+func (t *httpTool) templateData(input json.RawMessage) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	if len(input) > 0 && string(input) != "null" {
+		if err := json.Unmarshal(input, &data); err != nil {
+			return nil, err
+		}
+	}
+	props, _ := t.schema["properties"].(map[string]interface{})
+	for name := range props {
+		if _, ok := data[name]; !ok {
+			data[name] = ""
+		}
+	}
+	return data, nil
+}
+
+// send performs the request, retrying idempotent methods on transient
+// failures.
+func (t *httpTool) send(ctx context.Context, data map[string]interface{}, confirmationID string) ([]byte, error) {
+	retries := t.config.Retries
+	switch t.method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+	default:
+		retries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		body, retryable, err := t.attempt(ctx, data, confirmationID)
+		if err == nil || !retryable || attempt >= retries || ctx.Err() != nil {
+			return body, err
+		}
+
+		delay := defaultHTTPToolBackoff << attempt
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends one request. retryable reports whether a failure was a
+// network error, 429 or 5xx.
+func (t *httpTool) attempt(ctx context.Context, data map[string]interface{}, confirmationID string) (body []byte, retryable bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	req, err := t.newRequest(ctx, data)
+	if err != nil {
+		return nil, false, err
+	}
+	if confirmationID != "" {
+		req.Header.Set("Idempotency-Key", confirmationID)
+	}
+
+	resp, err := t.config.Client.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, true, fmt.Errorf("request timed out after %s", t.timeout)
+		}
+		return nil, true, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err = io.ReadAll(io.LimitReader(resp.Body, maxHTTPToolResponse))
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		retryable = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return nil, retryable, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, false, nil
+}
+
+func (t *httpTool) newRequest(ctx context.Context, data map[string]interface{}) (*http.Request, error) {
+	rawURL, err := render(t.url, data)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+
+	if len(t.query) > 0 {
+		q := u.Query()
+		names := make([]string, 0, len(t.query))
+		for name := range t.query {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			v, err := render(t.query[name], data)
+			if err != nil {
+				return nil, err
+			}
+			if v != "" {
+				q.Set(name, v)
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+
+	var body io.Reader
+	if t.body != nil {
+		rendered, err := render(t.body, data)
+		if err != nil {
+			return nil, err
+		}
+		if !json.Valid([]byte(rendered)) {
+			return nil, fmt.Errorf("body template rendered invalid JSON: %s", rendered)
+		}
+		body = strings.NewReader(rendered)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, t.method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, tmpl := range t.headers {
+		v, err := render(tmpl, data)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(name, v)
+	}
+	return req, nil
+}
+
+func render(tmpl *template.Template, data map[string]interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// normalizeYAML converts YAML maps with non-string keys into
+// map[string]interface{} so schemas marshal as JSON.
+func normalizeYAML(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			val[k] = normalizeYAML(child)
+		}
+		return val
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, child := range val {
+			out[fmt.Sprint(k)] = normalizeYAML(child)
+		}
+		return out
+	case []interface{}:
+		for i, child := range val {
+			val[i] = normalizeYAML(child)
+		}
+		return val
+	default:
+		return v
+	}
+}
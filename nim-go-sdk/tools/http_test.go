@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+func TestHTTPTools(t *testing.T) {
+	var rateCalls atomic.Int32
+	var lastTicket string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/rates/"):
+			if r.Header.Get("Authorization") != "Bearer fx-secret" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			// Fail the first attempt to exercise retries
+			if rateCalls.Add(1) == 1 {
+				http.Error(w, "try again", http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprintf(w, `{"base":%q,"rates":[{"symbol":%q,"rate":0.92},{"symbol":"GBP","rate":0.79}],"timestamp":"2026-10-18T09:00:00Z","query":%q}`,
+				strings.TrimPrefix(r.URL.Path, "/rates/"), r.URL.Query().Get("symbols"), r.URL.RawQuery)
+		case r.URL.Path == "/tickets":
+			body, _ := io.ReadAll(r.Body)
+			lastTicket = string(body)
+			w.Write([]byte(`{"ticket":{"id":"T-1"}}`))
+		case r.URL.Path == "/slow":
+			time.Sleep(200 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("FX_TOKEN", "fx-secret")
+	config := fmt.Sprintf(`
+tools:
+  - name: get_fx_rate
+    description: Get an exchange rate
+    url: %[1]s/rates/{{path .base}}
+    headers:
+      Authorization: Bearer ${FX_TOKEN}
+    query:
+      symbols: "{{.quote}}"
+      date: "{{.date}}"
+    input_schema:
+      type: object
+      properties:
+        base: {type: string}
+        quote: {type: string}
+        date: {type: string}
+      required: [base, quote]
+    extract:
+      rate: $.rates[0].rate
+      symbols: $.rates[*].symbol
+      as_of: $.timestamp
+      query: $.query
+    retries: 1
+  - name: open_ticket
+    description: Open a support ticket
+    method: post
+    url: %[1]s/tickets
+    body: '{"title": {{json .title}}, "priority": {{json .priority}}}'
+    input_schema:
+      type: object
+      properties:
+        title: {type: string}
+        priority: {type: integer}
+    extract:
+      id: $.ticket.id
+    requires_confirmation: true
+    summary: 'Open a P{{.priority}} ticket: {{.title}}'
+  - name: slow
+    description: Too slow
+    url: %[1]s/slow
+    timeout: 50ms
+`, server.URL)
+
+	path := filepath.Join(t.TempDir(), "tools.yaml")
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tools, err := LoadHTTPTools(path)
+	if err != nil {
+		t.Fatalf("LoadHTTPTools: %v", err)
+	}
+	if len(tools) != 3 {
+		t.Fatalf("loaded %d tools, want 3", len(tools))
+	}
+	ctx := context.Background()
+
+	fx := tools[0]
+	if fx.RequiresConfirmation() || fmt.Sprint(fx.Schema()["required"]) != "[base quote]" {
+		t.Errorf("get_fx_rate schema = %v", fx.Schema())
+	}
+	result, _ := fx.Execute(ctx, &core.ToolParams{Input: json.RawMessage(`{"base":"US D","quote":"EUR"}`)})
+	if !result.Success {
+		t.Fatalf("get_fx_rate = %+v", result)
+	}
+	data := result.Data.(map[string]interface{})
+	if data["rate"] != 0.92 || fmt.Sprint(data["symbols"]) != "[EUR GBP]" || data["as_of"] != "2026-10-18T09:00:00Z" {
+		t.Errorf("extracted = %v", data)
+	}
+	if data["query"] != "symbols=EUR" {
+		t.Errorf("query = %v, want empty date omitted", data["query"])
+	}
+	if rateCalls.Load() != 2 {
+		t.Errorf("rate calls = %d, want 2 (one retry)", rateCalls.Load())
+	}
+
+	ticket := tools[1]
+	input := json.RawMessage(`{"title":"Card \"declined\"","priority":1}`)
+	if !ticket.RequiresConfirmation() || ticket.GetSummary(input) != `Open a P1 ticket: Card "declined"` {
+		t.Errorf("open_ticket summary = %q", ticket.GetSummary(input))
+	}
+	result, _ = ticket.Execute(ctx, &core.ToolParams{Input: input})
+	if !result.Success || result.Data.(map[string]interface{})["id"] != "T-1" {
+		t.Errorf("open_ticket = %+v", result)
+	}
+	if lastTicket != `{"title": "Card \"declined\"", "priority": 1}` {
+		t.Errorf("ticket body = %s", lastTicket)
+	}
+
+	result, _ = tools[2].Execute(ctx, &core.ToolParams{})
+	if result.Success || result.Error != "request timed out after 50ms" {
+		t.Errorf("slow = %+v", result)
+	}
+}
+
+func TestHTTPToolConfigErrors(t *testing.T) {
+	tests := map[string]string{
+		"missing env":  `{"tools":[{"name":"a","url":"http://x/${NIM_TEST_UNSET_VAR}"}]}`,
+		"bad template": `{"tools":[{"name":"a","url":"http://x/{{.a"}]}`,
+		"bad path":     `{"tools":[{"name":"a","url":"http://x","extract":{"v":"data.v"}}]}`,
+		"bad timeout":  `{"tools":[{"name":"a","url":"http://x","timeout":"soon"}]}`,
+		"duplicate":    `{"tools":[{"name":"a","url":"http://x"},{"name":"a","url":"http://y"}]}`,
+	}
+	for name, config := range tests {
+		if _, err := ParseHTTPTools([]byte(config)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	var doc interface{}
+	json.Unmarshal([]byte(`{"a":{"b c":[1,2,3]},"items":[{"id":"x"},{"id":"y"}]}`), &doc)
+
+	tests := map[string]string{
+		"$":              "map[a:map[b c:[1 2 3]] items:[map[id:x] map[id:y]]]",
+		"$.a['b c'][-1]": "3",
+		"$.items[*].id":  "[x y]",
+		"$.items[1].id":  "y",
+		"$.missing[*]":   "[]",
+	}
+	for path, want := range tests {
+		p, err := compileJSONPath(path)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		got, err := p.get(doc)
+		if err != nil || fmt.Sprint(got) != want {
+			t.Errorf("%s = %v, %v; want %s", path, got, err, want)
+		}
+	}
+
+	p, _ := compileJSONPath("$.a.missing")
+	if _, err := p.get(doc); err == nil {
+		t.Error("expected an error for a missing value")
+	}
+}
@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a compiled JSONPath expression. The supported subset is the
+// root "$", child names (".name" or "['name']"), array indexes ("[0]", with
+// negative indexes counting from the end) and wildcards ("[*]" or ".*").
+type jsonPath struct {
+	source string
+	steps  []pathStep
+}
+
+type pathStep struct {
+	name     string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// compileJSONPath parses a path such as "$.rates[0].value".
+func compileJSONPath(path string) (*jsonPath, error) {
+	p := &jsonPath{source: path}
+	rest := strings.TrimSpace(path)
+	if !strings.HasPrefix(rest, "$") {
+		return nil, fmt.Errorf("JSON path %q must start with $", path)
+	}
+	rest = rest[1:]
+
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, ".*"):
+			p.steps = append(p.steps, pathStep{wildcard: true})
+			rest = rest[2:]
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			name := rest[1 : end+1]
+			if name == "" {
+				return nil, fmt.Errorf("JSON path %q has an empty name", path)
+			}
+			p.steps = append(p.steps, pathStep{name: name})
+			rest = rest[end+1:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("JSON path %q has an unclosed [", path)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			switch {
+			case inner == "*":
+				p.steps = append(p.steps, pathStep{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				p.steps = append(p.steps, pathStep{name: inner[1 : len(inner)-1]})
+			default:
+				i, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("JSON path %q has an invalid index [%s]", path, inner)
+				}
+				p.steps = append(p.steps, pathStep{index: i, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("JSON path %q is invalid at %q", path, rest)
+		}
+	}
+	return p, nil
+}
+
+// get evaluates the path against decoded JSON. Paths with a wildcard
+// return a list of matches; others return the single value, or an error
+// if it doesn't exist.
+func (p *jsonPath) get(v interface{}) (interface{}, error) {
+	matches := []interface{}{v}
+	wildcard := false
+	for _, step := range p.steps {
+		wildcard = wildcard || step.wildcard
+		var next []interface{}
+		for _, m := range matches {
+			switch {
+			case step.wildcard:
+				switch val := m.(type) {
+				case []interface{}:
+					next = append(next, val...)
+				case map[string]interface{}:
+					for _, child := range val {
+						next = append(next, child)
+					}
+				}
+			case step.isIndex:
+				list, ok := m.([]interface{})
+				if !ok {
+					continue
+				}
+				i := step.index
+				if i < 0 {
+					i += len(list)
+				}
+				if i >= 0 && i < len(list) {
+					next = append(next, list[i])
+				}
+			default:
+				if obj, ok := m.(map[string]interface{}); ok {
+					if child, ok := obj[step.name]; ok {
+						next = append(next, child)
+					}
+				}
+			}
+		}
+		matches = next
+	}
+
+	if wildcard {
+		if matches == nil {
+			matches = []interface{}{}
+		}
+		return matches, nil
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%s not found in response", p.source)
+	}
+	return matches[0], nil
+}
@@ -30,10 +30,15 @@ func LiminalToolDefinitions() []core.ToolDefinition {
 		},
 		{
 			ToolName:        "get_transactions",
-			ToolDescription: "Get the user's recent transaction history.",
+			ToolDescription: "Get the user's transaction history, newest first. Results are paginated: pass nextCursor from the previous result as cursor to fetch older transactions.",
 			InputSchema: ObjectSchema(map[string]interface{}{
-				"limit": IntegerProperty("Number of transactions to return (default: 10)"),
-				"type":  StringEnumProperty("Filter by transaction type", "send", "receive", "deposit", "withdraw"),
+				"limit":        IntegerProperty("Number of transactions to return (default: 10)", Minimum(1)),
+				"type":         StringEnumProperty("Filter by transaction type", "send", "receive", "deposit", "withdraw"),
+				"cursor":       StringProperty("Optional: nextCursor from a previous page, to continue from there"),
+				"start_date":   StringProperty("Optional: earliest date to include (YYYY-MM-DD or RFC 3339)"),
+				"end_date":     StringProperty("Optional: latest date to include (YYYY-MM-DD or RFC 3339)"),
+				"counterparty": StringProperty("Optional: filter by counterparty display tag or name (e.g., @alice)"),
+				"min_amount":   DecimalProperty("Optional: minimum transaction amount (e.g., '25.00')"),
 			}),
 		},
 		{
@@ -57,7 +62,7 @@ func LiminalToolDefinitions() []core.ToolDefinition {
 			SummaryTemplate:          "Send {{.amount}} {{.currency}} to {{.recipient}}",
 			InputSchema: ObjectSchema(map[string]interface{}{
 				"recipient": StringProperty("Recipient's display tag (e.g., @alice) or user ID"),
-				"amount":    StringProperty("Amount to send (e.g., '50.00')"),
+				"amount":    MoneyProperty("Amount to send (e.g., '50.00')", 2),
 				"currency":  StringProperty("Currency to send (e.g., 'USD', 'EUR', 'LIL')"),
 				"note":      StringProperty("Optional payment note"),
 			}, "recipient", "amount", "currency"),
@@ -68,7 +73,7 @@ func LiminalToolDefinitions() []core.ToolDefinition {
 			RequiresUserConfirmation: true,
 			SummaryTemplate:          "Deposit {{.amount}} {{.currency}} into savings",
 			InputSchema: ObjectSchema(map[string]interface{}{
-				"amount":   StringProperty("Amount to deposit"),
+				"amount":   MoneyProperty("Amount to deposit", 2),
 				"currency": StringProperty("Currency to deposit (e.g., 'USD', 'EUR', 'LIL')"),
 			}, "amount", "currency"),
 		},
@@ -78,7 +83,7 @@ func LiminalToolDefinitions() []core.ToolDefinition {
 			RequiresUserConfirmation: true,
 			SummaryTemplate:          "Withdraw {{.amount}} {{.currency}} from savings",
 			InputSchema: ObjectSchema(map[string]interface{}{
-				"amount":   StringProperty("Amount to withdraw"),
+				"amount":   MoneyProperty("Amount to withdraw", 2),
 				"currency": StringProperty("Currency to withdraw (e.g., 'USD', 'EUR', 'LIL')"),
 			}, "amount", "currency"),
 		},
@@ -94,3 +99,17 @@ func LiminalTools(executor core.ToolExecutor) []core.Tool {
 	}
 	return tools
 }
+
+// ReadOnlyLiminalTools returns the names of the Liminal tools that don't
+// require confirmation. Sandboxed tools, such as scripts and WebAssembly
+// plugins, may only call these: their calls go through
+// ToolExecutor.Execute and never through the confirmation flow.
+func ReadOnlyLiminalTools() []string {
+	var names []string
+	for _, def := range LiminalToolDefinitions() {
+		if !def.RequiresUserConfirmation {
+			names = append(names, def.ToolName)
+		}
+	}
+	return names
+}
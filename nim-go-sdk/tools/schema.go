@@ -1,5 +1,9 @@
 package tools
 
+import (
+	"fmt"
+)
+
 // Schema helpers for building JSON Schema definitions.
 
 // ObjectSchema creates an object schema with the given properties.
@@ -15,11 +19,11 @@ func ObjectSchema(properties map[string]interface{}, required ...string) map[str
 }
 
 // StringProperty creates a string property with optional description.
-func StringProperty(description string) map[string]interface{} {
-	return map[string]interface{}{
+func StringProperty(description string, opts ...PropertyOption) map[string]interface{} {
+	return With(map[string]interface{}{
 		"type":        "string",
 		"description": description,
-	}
+	}, opts...)
 }
 
 // StringEnumProperty creates a string property with allowed values.
@@ -32,34 +36,161 @@ func StringEnumProperty(description string, values ...string) map[string]interfa
 }
 
 // NumberProperty creates a number property with optional description.
-func NumberProperty(description string) map[string]interface{} {
-	return map[string]interface{}{
+func NumberProperty(description string, opts ...PropertyOption) map[string]interface{} {
+	return With(map[string]interface{}{
 		"type":        "number",
 		"description": description,
-	}
+	}, opts...)
 }
 
 // IntegerProperty creates an integer property with optional description.
-func IntegerProperty(description string) map[string]interface{} {
-	return map[string]interface{}{
+func IntegerProperty(description string, opts ...PropertyOption) map[string]interface{} {
+	return With(map[string]interface{}{
 		"type":        "integer",
 		"description": description,
-	}
+	}, opts...)
 }
 
 // BooleanProperty creates a boolean property with optional description.
-func BooleanProperty(description string) map[string]interface{} {
-	return map[string]interface{}{
+func BooleanProperty(description string, opts ...PropertyOption) map[string]interface{} {
+	return With(map[string]interface{}{
 		"type":        "boolean",
 		"description": description,
-	}
+	}, opts...)
 }
 
 // ArrayProperty creates an array property with the given item type.
-func ArrayProperty(description string, itemType map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
+func ArrayProperty(description string, itemType map[string]interface{}, opts ...PropertyOption) map[string]interface{} {
+	return With(map[string]interface{}{
 		"type":        "array",
 		"description": description,
 		"items":       itemType,
+	}, opts...)
+}
+
+// ObjectProperty creates a nested object property.
+func ObjectProperty(description string, properties map[string]interface{}, required []string, opts ...PropertyOption) map[string]interface{} {
+	schema := ObjectSchema(properties, required...)
+	schema["description"] = description
+	return With(schema, opts...)
+}
+
+// OneOfProperty creates a property that must match exactly one of the
+// given schemas.
+func OneOfProperty(description string, variants ...map[string]interface{}) map[string]interface{} {
+	oneOf := make([]interface{}, len(variants))
+	for i, v := range variants {
+		oneOf[i] = v
 	}
+	return map[string]interface{}{
+		"description": description,
+		"oneOf":       oneOf,
+	}
+}
+
+// DateProperty creates a string property holding a date (YYYY-MM-DD).
+func DateProperty(description string, opts ...PropertyOption) map[string]interface{} {
+	return StringProperty(description, append([]PropertyOption{Format("date")}, opts...)...)
+}
+
+// DateTimeProperty creates a string property holding an RFC 3339 timestamp.
+func DateTimeProperty(description string, opts ...PropertyOption) map[string]interface{} {
+	return StringProperty(description, append([]PropertyOption{Format("date-time")}, opts...)...)
+}
+
+// EmailProperty creates a string property holding an email address.
+func EmailProperty(description string, opts ...PropertyOption) map[string]interface{} {
+	return StringProperty(description, append([]PropertyOption{Format("email")}, opts...)...)
+}
+
+// DecimalProperty creates a string property holding a decimal number,
+// for values that must not lose precision as JSON numbers.
+func DecimalProperty(description string, opts ...PropertyOption) map[string]interface{} {
+	return StringProperty(description, append([]PropertyOption{Format("decimal"), Pattern(decimalPattern.String())}, opts...)...)
+}
+
+// MoneyProperty creates a decimal string property for a positive amount
+// with at most decimals fractional digits, e.g. 2 for USD:
+//
+//	"amount": tools.MoneyProperty("Amount to send (e.g., '50.00')", 2)
+func MoneyProperty(description string, decimals int, opts ...PropertyOption) map[string]interface{} {
+	return StringProperty(description, append([]PropertyOption{Format("decimal"), Pattern(MoneyPattern(decimals))}, opts...)...)
+}
+
+// MoneyPattern returns a pattern matching positive decimal amounts with at
+// most decimals fractional digits.
+func MoneyPattern(decimals int) string {
+	if decimals <= 0 {
+		return `^\d+$`
+	}
+	return fmt.Sprintf(`^\d+(\.\d{1,%d})?$`, decimals)
+}
+
+// PropertyOption adds a keyword to a schema.
+type PropertyOption func(schema map[string]interface{})
+
+// With applies options to a schema and returns it, e.g. to close a
+// top-level object:
+//
+//	tools.With(tools.ObjectSchema(props, "amount"), tools.NoAdditionalProperties())
+func With(schema map[string]interface{}, opts ...PropertyOption) map[string]interface{} {
+	for _, opt := range opts {
+		opt(schema)
+	}
+	return schema
+}
+
+// Minimum sets the inclusive lower bound of a number.
+func Minimum(n float64) PropertyOption {
+	return func(schema map[string]interface{}) { schema["minimum"] = n }
+}
+
+// Maximum sets the inclusive upper bound of a number.
+func Maximum(n float64) PropertyOption {
+	return func(schema map[string]interface{}) { schema["maximum"] = n }
+}
+
+// MinLength sets the minimum length of a string.
+func MinLength(n int) PropertyOption {
+	return func(schema map[string]interface{}) { schema["minLength"] = n }
+}
+
+// MaxLength sets the maximum length of a string.
+func MaxLength(n int) PropertyOption {
+	return func(schema map[string]interface{}) { schema["maxLength"] = n }
+}
+
+// MinItems sets the minimum length of an array.
+func MinItems(n int) PropertyOption {
+	return func(schema map[string]interface{}) { schema["minItems"] = n }
+}
+
+// MaxItems sets the maximum length of an array.
+func MaxItems(n int) PropertyOption {
+	return func(schema map[string]interface{}) { schema["maxItems"] = n }
+}
+
+// Pattern sets a regular expression a string must match.
+func Pattern(pattern string) PropertyOption {
+	return func(schema map[string]interface{}) { schema["pattern"] = pattern }
+}
+
+// Format sets a string format such as "date", "email" or "decimal".
+func Format(format string) PropertyOption {
+	return func(schema map[string]interface{}) { schema["format"] = format }
+}
+
+// Default sets the value assumed when the property is absent.
+func Default(value interface{}) PropertyOption {
+	return func(schema map[string]interface{}) { schema["default"] = value }
+}
+
+// Enum restricts a property to the given values.
+func Enum(values ...interface{}) PropertyOption {
+	return func(schema map[string]interface{}) { schema["enum"] = values }
+}
+
+// NoAdditionalProperties rejects object keys not listed in properties.
+func NoAdditionalProperties() PropertyOption {
+	return func(schema map[string]interface{}) { schema["additionalProperties"] = false }
 }
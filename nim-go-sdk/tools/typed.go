@@ -0,0 +1,773 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+// Typed creates a tool builder from a typed handler. The input schema is
+// derived from In, which must be a struct, and each call's input is decoded
+// and validated into In before fn runs:
+//
+//	type SendInput struct {
+//		Recipient string  `json:"recipient" description:"Display tag, e.g. @alice"`
+//		Amount    float64 `json:"amount" description:"Amount to send" min:"0.01"`
+//		Currency  string  `json:"currency" enum:"USD,EUR,LIL"`
+//		Note      *string `json:"note" description:"Optional payment note"`
+//	}
+//
+//	tool := tools.Typed("split_bill", func(ctx context.Context, in SendInput) (Receipt, error) {
+//		...
+//	}).Description("Split a bill").Build()
+//
+// Fields are named by their json tag and are required unless they are
+// pointers, slices or maps, are tagged omitempty, or have a default. These
+// struct tags refine the schema and validation:
+//
+//	description:"..."  property description
+//	enum:"a,b,c"       allowed values (for slices, allowed item values)
+//	required:"true"    required even if it would otherwise be optional; "false" for the reverse
+//	min:"1" max:"10"   bounds for numbers, lengths for strings and slices
+//	format:"date"      string format: date, date-time, email, decimal or uuid
+//	pattern:"^[A-Z]+$" regular expression strings must match
+//	default:"10"       value used when the field is absent
+//
+// Nested structs, slices, maps with string keys and time.Time (an RFC 3339
+// string, or a date with format:"date") are supported. Types implementing
+// json.Unmarshaler are decoded with it; give them a JSONSchema method to
+// describe their schema.
+//
+// A non-nil error from fn becomes a failed ToolResult; if Out is
+// *core.ToolResult it is returned as is. The handler's context carries the
+// call's core.ToolParams, available through ParamsFromContext.
+//
+// Typed panics if In can't be described by a JSON Schema, such as a
+// recursive type, a channel or an invalid tag.
+func Typed[In, Out any](name string, fn func(ctx context.Context, in In) (Out, error)) *Builder {
+	schema, err := schemaOf(reflect.TypeOf((*In)(nil)).Elem())
+	if err != nil {
+		panic(fmt.Sprintf("tools: Typed(%q): %v", name, err))
+	}
+
+	return New(name).Schema(schema).Handler(func(ctx context.Context, params *core.ToolParams) (*core.ToolResult, error) {
+		var in In
+		if err := DecodeInput(params.Input, &in); err != nil {
+			return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+		}
+
+		out, err := fn(context.WithValue(ctx, paramsKey{}, params), in)
+		if err != nil {
+			return &core.ToolResult{Success: false, Error: err.Error()}, nil
+		}
+		if result, ok := any(out).(*core.ToolResult); ok {
+			return result, nil
+		}
+		return &core.ToolResult{Success: true, Data: out}, nil
+	})
+}
+
+type paramsKey struct{}
+
+// ParamsFromContext returns the tool call's parameters inside a Typed handler.
+func ParamsFromContext(ctx context.Context) (*core.ToolParams, bool) {
+	params, ok := ctx.Value(paramsKey{}).(*core.ToolParams)
+	return params, ok
+}
+
+// SchemaOf returns the JSON Schema Typed derives for a struct type.
+func SchemaOf[T any]() (map[string]interface{}, error) {
+	return schemaOf(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// DecodeInput decodes tool input into v, a pointer to a struct, validating
+// it against the rules Typed derives from v's struct tags.
+func DecodeInput(input json.RawMessage, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("DecodeInput requires a non-nil pointer, got %T", v)
+	}
+	if _, err := schemaOf(rv.Type().Elem()); err != nil {
+		return err
+	}
+
+	var raw interface{}
+	if len(bytes.TrimSpace(input)) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(input))
+		dec.UseNumber()
+		if err := dec.Decode(&raw); err != nil {
+			return fmt.Errorf("malformed JSON: %v", err)
+		}
+	}
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+	return decodeValue(raw, rv.Elem(), fieldRules{}, "")
+}
+
+// schemaProvider lets a type describe its own JSON Schema.
+type schemaProvider interface {
+	JSONSchema() map[string]interface{}
+}
+
+var (
+	timeType           = reflect.TypeOf(time.Time{})
+	schemaProviderType = reflect.TypeOf((*schemaProvider)(nil)).Elem()
+	unmarshalerType    = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// Patterns for string formats Typed validates.
+var (
+	emailPattern   = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	decimalPattern = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+	uuidPattern    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// fieldRules are the constraints parsed from a struct field's tags.
+type fieldRules struct {
+	description string
+	enum        []string
+	min, max    *float64
+	format      string
+	pattern     *regexp.Regexp
+	def         *string
+}
+
+// itemRules are the rules a slice field passes on to its items.
+func (r fieldRules) itemRules() fieldRules {
+	return fieldRules{enum: r.enum, format: r.format, pattern: r.pattern}
+}
+
+// structField is a decoded struct field.
+type structField struct {
+	name     string
+	index    []int
+	required bool
+	rules    fieldRules
+}
+
+var fieldCache sync.Map // reflect.Type -> []structField
+
+// structFields lists t's JSON fields, flattening embedded structs.
+func structFields(t reflect.Type) ([]structField, error) {
+	if cached, ok := fieldCache.Load(t); ok {
+		return cached.([]structField), nil
+	}
+
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			embedded, err := structFields(f.Type)
+			if err != nil {
+				return nil, err
+			}
+			for _, ef := range embedded {
+				ef.index = append([]int{i}, ef.index...)
+				fields = append(fields, ef)
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		rules, err := parseRules(f)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name, err)
+		}
+
+		required := rules.def == nil && !strings.Contains(opts, "omitempty")
+		switch f.Type.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+			required = false
+		}
+		switch f.Tag.Get("required") {
+		case "true":
+			required = true
+		case "false":
+			required = false
+		case "":
+		default:
+			return nil, fmt.Errorf("field %s: required must be true or false", f.Name)
+		}
+
+		fields = append(fields, structField{name: name, index: []int{i}, required: required, rules: rules})
+	}
+
+	fieldCache.Store(t, fields)
+	return fields, nil
+}
+
+func parseRules(f reflect.StructField) (fieldRules, error) {
+	r := fieldRules{
+		description: f.Tag.Get("description"),
+		format:      f.Tag.Get("format"),
+	}
+	if enum := f.Tag.Get("enum"); enum != "" {
+		r.enum = strings.Split(enum, ",")
+	}
+	for tag, dst := range map[string]**float64{"min": &r.min, "max": &r.max} {
+		if value := f.Tag.Get(tag); value != "" {
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return r, fmt.Errorf("invalid %s %q", tag, value)
+			}
+			*dst = &n
+		}
+	}
+	if pattern := f.Tag.Get("pattern"); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return r, fmt.Errorf("invalid pattern: %w", err)
+		}
+		r.pattern = re
+	}
+	if def, ok := f.Tag.Lookup("default"); ok {
+		r.def = &def
+	}
+	return r, nil
+}
+
+// schemaOf derives the object schema for struct type t.
+func schemaOf(t reflect.Type) (map[string]interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("input type must be a struct, got %s", t)
+	}
+	return typeSchema(t, fieldRules{}, map[reflect.Type]bool{})
+}
+
+func typeSchema(t reflect.Type, r fieldRules, seen map[reflect.Type]bool) (map[string]interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var schema map[string]interface{}
+	switch {
+	case t == timeType:
+		format := r.format
+		if format == "" {
+			format = "date-time"
+		}
+		schema = map[string]interface{}{"type": "string", "format": format}
+		r.format = ""
+	case t.Implements(schemaProviderType) || reflect.PointerTo(t).Implements(schemaProviderType):
+		schema = reflect.New(t).Interface().(schemaProvider).JSONSchema()
+		copied := make(map[string]interface{}, len(schema))
+		for k, v := range schema {
+			copied[k] = v
+		}
+		schema = copied
+	case reflect.PointerTo(t).Implements(unmarshalerType):
+		schema = map[string]interface{}{}
+	default:
+		var err error
+		if schema, err = kindSchema(t, r, seen); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.description != "" {
+		schema["description"] = r.description
+	}
+	if t.Kind() == reflect.Slice {
+		// Enum, format and pattern were applied to the items
+		r.enum, r.format, r.pattern = nil, "", nil
+	}
+	if r.format != "" {
+		schema["format"] = r.format
+	}
+	if r.pattern != nil {
+		schema["pattern"] = r.pattern.String()
+	}
+	if len(r.enum) > 0 {
+		values := make([]interface{}, len(r.enum))
+		for i, value := range r.enum {
+			v, err := tagValue(t, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid enum value %q: %w", value, err)
+			}
+			values[i] = v
+		}
+		schema["enum"] = values
+	}
+	if r.def != nil {
+		v, err := tagValue(t, *r.def)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default %q: %w", *r.def, err)
+		}
+		schema["default"] = v
+	}
+	return schema, nil
+}
+
+func kindSchema(t reflect.Type, r fieldRules, seen map[reflect.Type]bool) (map[string]interface{}, error) {
+	schema := map[string]interface{}{}
+	bounds := func(minKey, maxKey string) {
+		if r.min != nil {
+			schema[minKey] = boundValue(*r.min, minKey != "minimum")
+		}
+		if r.max != nil {
+			schema[maxKey] = boundValue(*r.max, maxKey != "maximum")
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		schema["type"] = "string"
+		bounds("minLength", "maxLength")
+	case reflect.Bool:
+		schema["type"] = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		schema["type"] = "integer"
+		bounds("minimum", "maximum")
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema["type"] = "integer"
+		schema["minimum"] = 0
+		bounds("minimum", "maximum")
+	case reflect.Float32, reflect.Float64:
+		schema["type"] = "number"
+		bounds("minimum", "maximum")
+	case reflect.Slice:
+		items, err := typeSchema(t.Elem(), r.itemRules(), seen)
+		if err != nil {
+			return nil, err
+		}
+		schema["type"] = "array"
+		schema["items"] = items
+		bounds("minItems", "maxItems")
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map keys must be strings, got %s", t)
+		}
+		values, err := typeSchema(t.Elem(), fieldRules{}, seen)
+		if err != nil {
+			return nil, err
+		}
+		schema["type"] = "object"
+		schema["additionalProperties"] = values
+	case reflect.Interface:
+		// Any JSON value
+	case reflect.Struct:
+		if seen[t] {
+			return nil, fmt.Errorf("recursive type %s", t)
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		fields, err := structFields(t)
+		if err != nil {
+			return nil, err
+		}
+		properties := map[string]interface{}{}
+		var required []string
+		for _, f := range fields {
+			prop, err := typeSchema(t.FieldByIndex(f.index).Type, f.rules, seen)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", f.name, err)
+			}
+			properties[f.name] = prop
+			if f.required {
+				required = append(required, f.name)
+			}
+		}
+		schema["type"] = "object"
+		schema["properties"] = properties
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+
+	// Formats only make sense for strings and string items
+	if r.format != "" && t.Kind() != reflect.String && t.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("format %q on non-string type %s", r.format, t)
+	}
+	return schema, nil
+}
+
+// boundValue renders a tag bound as an integer when it is one.
+func boundValue(n float64, length bool) interface{} {
+	if length || n == float64(int64(n)) {
+		return int64(n)
+	}
+	return n
+}
+
+// tagValue parses an enum or default tag value as a JSON value of type t.
+func tagValue(t reflect.Type, value string) (interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseInt(value, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(value, 64)
+	case reflect.String:
+		return value, nil
+	}
+	if t == timeType {
+		return value, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// decodeValue validates the decoded JSON value v and stores it in rv.
+func decodeValue(v interface{}, rv reflect.Value, r fieldRules, path string) error {
+	if v == nil {
+		switch rv.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+			rv.Set(reflect.Zero(rv.Type()))
+			return nil
+		}
+		return fmt.Errorf("%s must not be null", label(path))
+	}
+
+	if rv.Kind() == reflect.Pointer {
+		elem := reflect.New(rv.Type().Elem())
+		if err := decodeValue(v, elem.Elem(), r, path); err != nil {
+			return err
+		}
+		rv.Set(elem)
+		return nil
+	}
+
+	if rv.Type() == timeType {
+		return decodeTime(v, rv, r, path)
+	}
+	if rv.Kind() != reflect.Interface && rv.Addr().Type().Implements(unmarshalerType) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("%s: %v", label(path), err)
+		}
+		if err := rv.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(data); err != nil {
+			return fmt.Errorf("%s: %v", label(path), err)
+		}
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		s, ok := v.(string)
+		if !ok {
+			return typeError(path, "a string", v)
+		}
+		if err := checkString(s, r, path); err != nil {
+			return err
+		}
+		rv.SetString(s)
+
+	case reflect.Bool:
+		b, ok := v.(bool)
+		if !ok {
+			return typeError(path, "a boolean", v)
+		}
+		rv.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		num, ok := v.(json.Number)
+		if !ok {
+			return typeError(path, "an integer", v)
+		}
+		n, err := strconv.ParseInt(num.String(), 10, 64)
+		if err != nil || rv.OverflowInt(n) {
+			return fmt.Errorf("%s must be an integer in range, got %s", label(path), num)
+		}
+		if err := checkNumber(float64(n), num.String(), r, path); err != nil {
+			return err
+		}
+		rv.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		num, ok := v.(json.Number)
+		if !ok {
+			return typeError(path, "an integer", v)
+		}
+		n, err := strconv.ParseUint(num.String(), 10, 64)
+		if err != nil || rv.OverflowUint(n) {
+			return fmt.Errorf("%s must be a non-negative integer in range, got %s", label(path), num)
+		}
+		if err := checkNumber(float64(n), num.String(), r, path); err != nil {
+			return err
+		}
+		rv.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		num, ok := v.(json.Number)
+		if !ok {
+			return typeError(path, "a number", v)
+		}
+		n, err := num.Float64()
+		if err != nil || rv.OverflowFloat(n) {
+			return fmt.Errorf("%s must be a number in range, got %s", label(path), num)
+		}
+		if err := checkNumber(n, num.String(), r, path); err != nil {
+			return err
+		}
+		rv.SetFloat(n)
+
+	case reflect.Slice:
+		items, ok := v.([]interface{})
+		if !ok {
+			return typeError(path, "an array", v)
+		}
+		if r.min != nil && float64(len(items)) < *r.min {
+			return fmt.Errorf("%s must have at least %v items", label(path), *r.min)
+		}
+		if r.max != nil && float64(len(items)) > *r.max {
+			return fmt.Errorf("%s must have at most %v items", label(path), *r.max)
+		}
+		slice := reflect.MakeSlice(rv.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(item, slice.Index(i), r.itemRules(), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+
+	case reflect.Map:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return typeError(path, "an object", v)
+		}
+		m := reflect.MakeMapWithSize(rv.Type(), len(obj))
+		for _, key := range sortedKeys(obj) {
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			if err := decodeValue(obj[key], elem, fieldRules{}, joinPath(path, key)); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()), elem)
+		}
+		rv.Set(m)
+
+	case reflect.Interface:
+		rv.Set(reflect.ValueOf(plainValue(v)))
+
+	case reflect.Struct:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return typeError(path, "an object", v)
+		}
+		fields, err := structFields(rv.Type())
+		if err != nil {
+			return err
+		}
+		for _, f := range fields {
+			fieldPath := joinPath(path, f.name)
+			value, present := obj[f.name]
+			if !present {
+				if f.rules.def != nil {
+					value = defaultValue(*f.rules.def)
+				} else if f.required {
+					return fmt.Errorf("%s is required", fieldPath)
+				} else {
+					continue
+				}
+			}
+			if err := decodeValue(value, fieldByIndex(rv, f.index), f.rules, fieldPath); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fmt.Errorf("%s has unsupported type %s", label(path), rv.Type())
+	}
+	return nil
+}
+
+func decodeTime(v interface{}, rv reflect.Value, r fieldRules, path string) error {
+	s, ok := v.(string)
+	if !ok {
+		return typeError(path, "a timestamp string", v)
+	}
+	layouts := []string{time.RFC3339, "2006-01-02"}
+	if r.format == "date" {
+		layouts = []string{"2006-01-02"}
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			rv.Set(reflect.ValueOf(t))
+			return nil
+		}
+	}
+	if r.format == "date" {
+		return fmt.Errorf("%s must be a date (YYYY-MM-DD), got %q", label(path), s)
+	}
+	return fmt.Errorf("%s must be an RFC 3339 timestamp, got %q", label(path), s)
+}
+
+func checkString(s string, r fieldRules, path string) error {
+	if len(r.enum) > 0 && !contains(r.enum, s) {
+		return fmt.Errorf("%s must be one of %s, got %q", label(path), strings.Join(r.enum, ", "), s)
+	}
+	n := float64(utf8.RuneCountInString(s))
+	if r.min != nil && n < *r.min {
+		return fmt.Errorf("%s must be at least %v characters", label(path), *r.min)
+	}
+	if r.max != nil && n > *r.max {
+		return fmt.Errorf("%s must be at most %v characters", label(path), *r.max)
+	}
+	if r.pattern != nil && !r.pattern.MatchString(s) {
+		return fmt.Errorf("%s must match %s", label(path), r.pattern)
+	}
+
+	var valid bool
+	switch r.format {
+	case "":
+		return nil
+	case "date":
+		_, err := time.Parse("2006-01-02", s)
+		valid = err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		valid = err == nil
+	case "email":
+		valid = emailPattern.MatchString(s)
+	case "decimal":
+		valid = decimalPattern.MatchString(s)
+	case "uuid":
+		valid = uuidPattern.MatchString(s)
+	default:
+		// Unknown formats are descriptive only
+		valid = true
+	}
+	if !valid {
+		return fmt.Errorf("%s must be a valid %s, got %q", label(path), r.format, s)
+	}
+	return nil
+}
+
+func checkNumber(n float64, text string, r fieldRules, path string) error {
+	if len(r.enum) > 0 && !contains(r.enum, text) {
+		return fmt.Errorf("%s must be one of %s, got %s", label(path), strings.Join(r.enum, ", "), text)
+	}
+	if r.min != nil && n < *r.min {
+		return fmt.Errorf("%s must be at least %v", label(path), *r.min)
+	}
+	if r.max != nil && n > *r.max {
+		return fmt.Errorf("%s must be at most %v", label(path), *r.max)
+	}
+	return nil
+}
+
+// defaultValue parses a default tag as JSON, falling back to a string.
+func defaultValue(def string) interface{} {
+	dec := json.NewDecoder(strings.NewReader(def))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return def
+	}
+	return v
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex for addressable structs.
+func fieldByIndex(rv reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		rv = rv.Field(i)
+	}
+	return rv
+}
+
+// plainValue converts json.Numbers to float64, as encoding/json would.
+func plainValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		for i := range v {
+			v[i] = plainValue(v[i])
+		}
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = plainValue(v[k])
+		}
+	}
+	return v
+}
+
+func typeError(path, want string, got interface{}) error {
+	return fmt.Errorf("%s must be %s, got %s", label(path), want, jsonType(got))
+}
+
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return "null"
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func label(path string) string {
+	if path == "" {
+		return "input"
+	}
+	return path
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+type splitInput struct {
+	Title    string     `json:"title" description:"What the bill was for" min:"1" max:"40"`
+	Total    float64    `json:"total" min:"0.01"`
+	Currency string     `json:"currency" enum:"USD,EUR,LIL" default:"USD"`
+	Date     time.Time  `json:"date" format:"date"`
+	Due      *time.Time `json:"due"`
+	People   []person   `json:"people" min:"1"`
+	Tags     []string   `json:"tags" enum:"food,travel"`
+	Rounds   int        `json:"rounds,omitempty" max:"3"`
+}
+
+type person struct {
+	Tag   string `json:"tag" pattern:"^@[a-z]+$"`
+	Email string `json:"email,omitempty" format:"email"`
+}
+
+func TestTypedSchema(t *testing.T) {
+	schema, err := SchemaOf[splitInput]()
+	if err != nil {
+		t.Fatalf("SchemaOf failed: %v", err)
+	}
+	got, _ := json.Marshal(schema)
+	want := `{"properties":{` +
+		`"currency":{"default":"USD","enum":["USD","EUR","LIL"],"type":"string"},` +
+		`"date":{"format":"date","type":"string"},` +
+		`"due":{"format":"date-time","type":"string"},` +
+		`"people":{"items":{"properties":{"email":{"format":"email","type":"string"},"tag":{"pattern":"^@[a-z]+$","type":"string"}},"required":["tag"],"type":"object"},"minItems":1,"type":"array"},` +
+		`"rounds":{"maximum":3,"type":"integer"},` +
+		`"tags":{"items":{"enum":["food","travel"],"type":"string"},"type":"array"},` +
+		`"title":{"description":"What the bill was for","maxLength":40,"minLength":1,"type":"string"},` +
+		`"total":{"minimum":0.01,"type":"number"}},` +
+		`"required":["title","total","date"],"type":"object"}`
+	if string(got) != want {
+		t.Errorf("unexpected schema:\n got %s\nwant %s", got, want)
+	}
+
+	type recursive struct {
+		Next *recursive `json:"next"`
+	}
+	if _, err := SchemaOf[recursive](); err == nil {
+		t.Error("expected error for recursive type")
+	}
+}
+
+func TestTypedExecute(t *testing.T) {
+	tool := Typed("split_bill", func(ctx context.Context, in splitInput) (map[string]interface{}, error) {
+		params, _ := ParamsFromContext(ctx)
+		if in.Total > 1000 {
+			return nil, errors.New("total too large")
+		}
+		return map[string]interface{}{
+			"user":     params.UserID,
+			"share":    in.Total / float64(len(in.People)),
+			"currency": in.Currency,
+			"date":     in.Date.Format("Jan 2"),
+			"due":      in.Due != nil,
+		}, nil
+	}).Description("Split a bill").Build()
+
+	run := func(input string) *core.ToolResult {
+		t.Helper()
+		result, err := tool.Execute(context.Background(), &core.ToolParams{UserID: "user_1", Input: json.RawMessage(input)})
+		if err != nil {
+			t.Fatalf("Execute returned error: %v", err)
+		}
+		return result
+	}
+
+	result := run(`{"title":"Dinner","total":90,"date":"2026-03-14","people":[{"tag":"@alice"},{"tag":"@bob"}]}`)
+	want := map[string]interface{}{"user": "user_1", "share": 45.0, "currency": "USD", "date": "Mar 14", "due": false}
+	if !result.Success || !reflect.DeepEqual(result.Data, want) {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	for input, wantErr := range map[string]string{
+		`{"total":90,"date":"2026-03-14","people":[{"tag":"@a"}]}`:                              "title is required",
+		`{"title":"x","total":"90","date":"2026-03-14","people":[{"tag":"@a"}]}`:                "total must be a number, got string",
+		`{"title":"x","total":90,"date":"14/03/2026","people":[{"tag":"@a"}]}`:                  "date must be a date",
+		`{"title":"x","total":90,"date":"2026-03-14","people":[]}`:                              "people must have at least 1 items",
+		`{"title":"x","total":90,"date":"2026-03-14","people":[{"tag":"alice"}]}`:               "people[0].tag must match",
+		`{"title":"x","total":90,"date":"2026-03-14","people":[{"tag":"@a","email":"nope"}]}`:   "people[0].email must be a valid email",
+		`{"title":"x","total":90,"currency":"GBP","date":"2026-03-14","people":[{"tag":"@a"}]}`: "currency must be one of USD, EUR, LIL",
+		`{"title":"x","total":90,"date":"2026-03-14","people":[{"tag":"@a"}],"tags":["rent"]}`:  "tags[0] must be one of food, travel",
+		`{"title":"x","total":90,"date":"2026-03-14","people":[{"tag":"@a"}],"rounds":1.5}`:     "rounds must be an integer",
+		`{"title":"x","total":2000,"date":"2026-03-14","people":[{"tag":"@a"}]}`:                "total too large",
+		`{"title":"x","total":90,"date":"2026-03-14","people":[{"tag":"@a"}],"due":"tomorrow"}`: "due must be an RFC 3339 timestamp",
+		`[1, 2]`: "input must be an object",
+	} {
+		result := run(input)
+		if result.Success || !strings.Contains(result.Error, wantErr) {
+			t.Errorf("input %s: expected error containing %q, got %+v", input, wantErr, result)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Package wasm loads tools from sandboxed WebAssembly modules, so teams can
+// ship tools without linking Go code into the agent binary.
+//
+// Modules run on wazero, a pure-Go engine, unless Config.Runtime plugs in
+// another:
+//
+//	tools, err := wasm.LoadDir(ctx, "plugins", registry, wasm.Config{
+//		Executor: liminalExecutor,
+//	})
+//
+// # ABI
+//
+// Strings and JSON cross the boundary as bytes in the module's linear
+// memory. Functions that return bytes return an i64 packing the pointer in
+// the high 32 bits and the length in the low 32 bits.
+//
+// A plugin module exports:
+//
+//	memory                                   linear memory
+//	nim_alloc(size i32) -> i32               allocates size bytes for the host
+//	nim_name() -> i64                        tool name
+//	nim_description() -> i64                 tool description
+//	nim_schema() -> i64                      JSON Schema of the input
+//	nim_requires_confirmation() -> i32       1 if calls need user approval
+//	nim_summary() -> i64                     optional confirmation summary,
+//	                                         with {field} placeholders
+//	nim_handle(ptr i32, len i32) -> i64      runs the tool on JSON input
+//
+// nim_handle returns a JSON object with either "data" or a non-empty
+// "error". The module may import these functions from the "nim" module:
+//
+//	log(ptr i32, len i32)                                    writes a log line
+//	call_tool(name i32, name_len i32, in i32, in_len i32) -> i64
+//
+// call_tool runs one of Config.AllowedTools through the executor and
+// returns a JSON object in the same shape as nim_handle's result, in memory
+// allocated with nim_alloc.
+package wasm
+
+import "context"
+
+// PageSize is the size of a WebAssembly memory page.
+const PageSize = 64 << 10
+
+// HostModule is the import module name of the host functions.
+const HostModule = "nim"
+
+// Runtime compiles WebAssembly modules.
+type Runtime interface {
+	// Compile validates and compiles a module's binary.
+	Compile(ctx context.Context, wasm []byte) (Module, error)
+}
+
+// Module is a compiled WebAssembly module.
+type Module interface {
+	// Instantiate creates an isolated instance linked to the host
+	// functions. The instance's memory must not grow beyond maxPages, and
+	// calls must stop when their context is done.
+	Instantiate(ctx context.Context, host []HostFunction, maxPages uint32) (Instance, error)
+
+	// Close releases the compiled module.
+	Close(ctx context.Context) error
+}
+
+// Instance is an instantiated module.
+type Instance interface {
+	// Call calls an exported function. It returns an error if the export
+	// doesn't exist, the module traps or ctx is done.
+	Call(ctx context.Context, name string, params ...uint64) ([]uint64, error)
+
+	// Memory returns the instance's exported memory.
+	Memory() Memory
+
+	// Close releases the instance.
+	Close(ctx context.Context) error
+}
+
+// Memory is an instance's linear memory.
+type Memory interface {
+	// Read returns length bytes at offset, or false if out of range.
+	Read(offset, length uint32) ([]byte, bool)
+
+	// Write copies data to offset, returning false if out of range.
+	Write(offset uint32, data []byte) bool
+
+	// Size returns the size of the memory in bytes.
+	Size() uint32
+}
+
+// ValueType is a WebAssembly value type of a host function.
+type ValueType byte
+
+// Value types used by the host functions.
+const (
+	I32 ValueType = 0x7f
+	I64 ValueType = 0x7e
+)
+
+// HostFunction is a function the host exports to modules.
+type HostFunction struct {
+	Module  string
+	Name    string
+	Params  []ValueType
+	Results []ValueType
+
+	// Func is called with the calling instance and the raw parameters.
+	Func func(ctx context.Context, instance Instance, params []uint64) []uint64
+}
+
+// pack packs a pointer and length into an i64 ABI value.
+func pack(ptr, length uint32) uint64 {
+	return uint64(ptr)<<32 | uint64(length)
+}
+
+// unpack splits an i64 ABI value into a pointer and length.
+func unpack(v uint64) (ptr, length uint32) {
+	return uint32(v >> 32), uint32(v)
+}
@@ -0,0 +1,419 @@
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/engine"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+const (
+	// DefaultTimeout bounds the wall time of one call.
+	DefaultTimeout = 2 * time.Second
+
+	// DefaultMaxMemoryBytes bounds a module's linear memory.
+	DefaultMaxMemoryBytes = 16 << 20
+)
+
+// DefaultAllowedTools are the read-only Liminal tools plugins may call.
+var DefaultAllowedTools = tools.ReadOnlyLiminalTools()
+
+// Config configures the runtime, host API and limits of plugin tools.
+type Config struct {
+	// Runtime compiles and runs modules. Defaults to a shared
+	// NewWazeroRuntime.
+	Runtime Runtime
+
+	// Executor runs call_tool requests. If nil, call_tool fails.
+	Executor core.ToolExecutor
+
+	// AllowedTools lists the executor tools plugins may call. Defaults to
+	// DefaultAllowedTools. See tools.ReadOnlyLiminalTools for why these
+	// must be read-only.
+	AllowedTools []string
+
+	// Timeout bounds the wall time of a call, including host function
+	// calls. Defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// MaxMemoryBytes bounds a module's linear memory, rounded down to
+	// whole pages. Defaults to DefaultMaxMemoryBytes.
+	MaxMemoryBytes uint32
+
+	// Logger receives the module's log lines. If nil, the standard logger
+	// is used.
+	Logger *log.Logger
+}
+
+// defaultRuntime is shared so compiled code is cached across tools.
+var defaultRuntime = sync.OnceValue(NewWazeroRuntime)
+
+func (c Config) withDefaults() Config {
+	if c.Runtime == nil {
+		c.Runtime = defaultRuntime()
+	}
+	if c.AllowedTools == nil {
+		c.AllowedTools = DefaultAllowedTools
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.MaxMemoryBytes == 0 {
+		c.MaxMemoryBytes = DefaultMaxMemoryBytes
+	}
+	if c.Logger == nil {
+		c.Logger = log.Default()
+	}
+	return c
+}
+
+// Tool is a tool implemented by a WebAssembly module. Every call runs in a
+// fresh instance, so calls share no memory.
+type Tool struct {
+	name                 string
+	description          string
+	schema               map[string]interface{}
+	requiresConfirmation bool
+	summary              string
+
+	filename string
+	module   Module
+	config   Config
+}
+
+// Verify Tool implements core.Tool.
+var _ core.Tool = (*Tool)(nil)
+
+// LoadFile compiles a module file.
+func LoadFile(ctx context.Context, path string, cfg Config) (*Tool, error) {
+	wasm, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(ctx, path, wasm, cfg)
+}
+
+// LoadDir compiles every *.wasm file in dir and registers the tools in
+// registry, which may be nil. It fails without registering anything if two
+// modules define the same tool, or a module would replace a tool already in
+// registry.
+func LoadDir(ctx context.Context, dir string, registry *engine.ToolRegistry, cfg Config) ([]*Tool, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.wasm"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	loaded := make([]*Tool, 0, len(paths))
+	fail := func(err error) ([]*Tool, error) {
+		for _, t := range loaded {
+			t.Close(ctx)
+		}
+		return nil, err
+	}
+	seen := make(map[string]string)
+	for _, path := range paths {
+		tool, err := LoadFile(ctx, path, cfg)
+		if err != nil {
+			return fail(err)
+		}
+		loaded = append(loaded, tool)
+		if other, ok := seen[tool.name]; ok {
+			return fail(fmt.Errorf("%s: tool %s is already defined by %s", path, tool.name, other))
+		}
+		if registry != nil {
+			if _, ok := registry.Get(tool.name); ok {
+				return fail(fmt.Errorf("%s: tool %s is already registered", path, tool.name))
+			}
+		}
+		seen[tool.name] = path
+	}
+	if registry != nil {
+		for _, tool := range loaded {
+			registry.Register(tool)
+		}
+	}
+	return loaded, nil
+}
+
+// New compiles a module and reads the tool's metadata from its exports.
+// filename is used in errors and log lines.
+func New(ctx context.Context, filename string, wasm []byte, cfg Config) (*Tool, error) {
+	cfg = cfg.withDefaults()
+	module, err := cfg.Runtime.Compile(ctx, wasm)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	t := &Tool{filename: filename, module: module, config: cfg}
+
+	err = t.run(ctx, "", func(ctx context.Context, inst Instance) error {
+		var err error
+		if t.name, err = readString(ctx, inst, "nim_name"); err != nil {
+			return err
+		}
+		if t.description, err = readString(ctx, inst, "nim_description"); err != nil {
+			return err
+		}
+		schema, err := readString(ctx, inst, "nim_schema")
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(schema), &t.schema); err != nil {
+			return fmt.Errorf("nim_schema: %w", err)
+		}
+		results, err := inst.Call(ctx, "nim_requires_confirmation")
+		if err != nil {
+			return err
+		}
+		t.requiresConfirmation = len(results) == 1 && uint32(results[0]) != 0
+		// The summary is optional
+		t.summary, _ = readString(ctx, inst, "nim_summary")
+		return nil
+	})
+	if err == nil && (t.name == "" || t.description == "") {
+		err = errors.New("nim_name and nim_description must not be empty")
+	}
+	if err != nil {
+		module.Close(ctx)
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return t, nil
+}
+
+// Close releases the compiled module.
+func (t *Tool) Close(ctx context.Context) error {
+	return t.module.Close(ctx)
+}
+
+func (t *Tool) Name() string                   { return t.name }
+func (t *Tool) Description() string            { return t.description }
+func (t *Tool) Schema() map[string]interface{} { return t.schema }
+func (t *Tool) RequiresConfirmation() bool     { return t.requiresConfirmation }
+
+// GetSummary returns the module's summary, with {field} placeholders
+// replaced by input values.
+func (t *Tool) GetSummary(input json.RawMessage) string {
+	if t.summary == "" {
+		return t.description
+	}
+	var fields map[string]interface{}
+	json.Unmarshal(input, &fields)
+	summary := t.summary
+	for k, v := range fields {
+		summary = strings.ReplaceAll(summary, "{"+k+"}", fmt.Sprint(v))
+	}
+	return summary
+}
+
+// handleResult is the JSON result of nim_handle and call_tool.
+type handleResult struct {
+	Data  json.RawMessage `json:"data,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+// Execute passes the JSON input to nim_handle in a fresh instance.
+// Errors, traps and limit violations become failed results.
+func (t *Tool) Execute(ctx context.Context, params *core.ToolParams) (*core.ToolResult, error) {
+	input := params.Input
+	if len(input) == 0 {
+		input = json.RawMessage("{}")
+	}
+
+	var result handleResult
+	err := t.run(ctx, params.UserID, func(ctx context.Context, inst Instance) error {
+		ptr, err := write(ctx, inst, input)
+		if err != nil {
+			return err
+		}
+		out, err := inst.Call(ctx, "nim_handle", uint64(ptr), uint64(len(input)))
+		if err != nil {
+			return err
+		}
+		if len(out) != 1 {
+			return errors.New("nim_handle must return an i64")
+		}
+		data, err := read(inst, out[0])
+		if err != nil {
+			return fmt.Errorf("nim_handle: %w", err)
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return fmt.Errorf("nim_handle returned invalid JSON: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return &core.ToolResult{Success: false, Error: err.Error()}, nil
+	}
+	if result.Error != "" {
+		return &core.ToolResult{Success: false, Error: result.Error}, nil
+	}
+
+	var data interface{}
+	if len(result.Data) > 0 {
+		json.Unmarshal(result.Data, &data)
+	}
+	return &core.ToolResult{Success: true, Data: data}, nil
+}
+
+// callUser carries the calling user to host functions.
+type callUser struct{}
+
+// run executes fn on a fresh instance under the configured limits.
+func (t *Tool) run(ctx context.Context, userID string, fn func(ctx context.Context, inst Instance) error) error {
+	ctx, cancel := context.WithTimeout(ctx, t.config.Timeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, callUser{}, userID)
+
+	maxPages := t.config.MaxMemoryBytes / PageSize
+	inst, err := t.module.Instantiate(ctx, t.hostFunctions(), maxPages)
+	if err != nil {
+		return err
+	}
+	defer inst.Close(context.Background())
+
+	// Don't rely on the runtime alone to stop at the deadline
+	done := make(chan error, 1)
+	go func() { done <- fn(ctx, inst) }()
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("plugin exceeded the %s time limit", t.config.Timeout)
+	}
+	if err != nil {
+		return err
+	}
+	if size := inst.Memory().Size(); size > maxPages*PageSize {
+		return fmt.Errorf("plugin exceeded the %d byte memory limit", maxPages*PageSize)
+	}
+	return nil
+}
+
+// hostFunctions returns the host API linked into each instance.
+func (t *Tool) hostFunctions() []HostFunction {
+	return []HostFunction{
+		{
+			Module: HostModule,
+			Name:   "log",
+			Params: []ValueType{I32, I32},
+			Func: func(ctx context.Context, inst Instance, params []uint64) []uint64 {
+				if msg, ok := inst.Memory().Read(uint32(params[0]), uint32(params[1])); ok {
+					t.config.Logger.Printf("plugin %s: %s", t.filename, msg)
+				}
+				return nil
+			},
+		},
+		{
+			Module:  HostModule,
+			Name:    "call_tool",
+			Params:  []ValueType{I32, I32, I32, I32},
+			Results: []ValueType{I64},
+			Func: func(ctx context.Context, inst Instance, params []uint64) []uint64 {
+				result := t.callTool(ctx, inst, params)
+				data, _ := json.Marshal(result)
+				ptr, err := write(ctx, inst, data)
+				if err != nil {
+					return []uint64{0}
+				}
+				return []uint64{pack(ptr, uint32(len(data)))}
+			},
+		},
+	}
+}
+
+// callTool runs an allowed executor tool for the calling user.
+func (t *Tool) callTool(ctx context.Context, inst Instance, params []uint64) handleResult {
+	mem := inst.Memory()
+	name, ok := mem.Read(uint32(params[0]), uint32(params[1]))
+	if !ok {
+		return handleResult{Error: "call_tool: name out of range"}
+	}
+	input, ok := mem.Read(uint32(params[2]), uint32(params[3]))
+	if !ok {
+		return handleResult{Error: "call_tool: input out of range"}
+	}
+
+	allowed := false
+	for _, tool := range t.config.AllowedTools {
+		allowed = allowed || tool == string(name)
+	}
+	if !allowed {
+		return handleResult{Error: fmt.Sprintf("call_tool: %s is not allowed", name)}
+	}
+	userID, _ := ctx.Value(callUser{}).(string)
+	if t.config.Executor == nil || userID == "" {
+		return handleResult{Error: "call_tool: not available"}
+	}
+	if !json.Valid(input) {
+		return handleResult{Error: "call_tool: input is not valid JSON"}
+	}
+
+	resp, err := t.config.Executor.Execute(ctx, &core.ExecuteRequest{
+		UserID: userID,
+		Tool:   string(name),
+		Input:  append(json.RawMessage(nil), input...),
+	})
+	if err != nil {
+		return handleResult{Error: fmt.Sprintf("call_tool %s: %v", name, err)}
+	}
+	if !resp.Success {
+		return handleResult{Error: fmt.Sprintf("call_tool %s: %s", name, resp.Error)}
+	}
+	return handleResult{Data: resp.Data}
+}
+
+// readString calls an export returning packed bytes and reads them.
+func readString(ctx context.Context, inst Instance, export string) (string, error) {
+	results, err := inst.Call(ctx, export)
+	if err != nil {
+		return "", err
+	}
+	if len(results) != 1 {
+		return "", fmt.Errorf("%s must return an i64", export)
+	}
+	data, err := read(inst, results[0])
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", export, err)
+	}
+	return string(data), nil
+}
+
+// read copies the bytes described by a packed pointer and length.
+func read(inst Instance, packed uint64) ([]byte, error) {
+	ptr, length := unpack(packed)
+	data, ok := inst.Memory().Read(ptr, length)
+	if !ok {
+		return nil, fmt.Errorf("%d bytes at %d are out of range", length, ptr)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// write copies data into memory allocated with nim_alloc.
+func write(ctx context.Context, inst Instance, data []byte) (uint32, error) {
+	results, err := inst.Call(ctx, "nim_alloc", uint64(len(data)))
+	if err != nil {
+		return 0, err
+	}
+	if len(results) != 1 {
+		return 0, errors.New("nim_alloc must return an i32")
+	}
+	ptr := uint32(results[0])
+	if !inst.Memory().Write(ptr, data) {
+		return 0, fmt.Errorf("nim_alloc returned %d, which is out of range", ptr)
+	}
+	return ptr, nil
+}
@@ -0,0 +1,246 @@
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/engine"
+)
+
+// fakeRuntime runs guests written in Go against the plugin ABI. The module
+// binary names the guest to run.
+type fakeRuntime map[string]guest
+
+// guest implements a plugin's nim_handle in Go.
+type guest func(ctx context.Context, g *fakeInstance, input []byte) string
+
+func (r fakeRuntime) Compile(ctx context.Context, wasm []byte) (Module, error) {
+	handle, ok := r[string(wasm)]
+	if !ok {
+		return nil, fmt.Errorf("invalid module")
+	}
+	return &fakeModule{name: string(wasm), handle: handle}, nil
+}
+
+type fakeModule struct {
+	name   string
+	handle guest
+}
+
+func (m *fakeModule) Instantiate(ctx context.Context, host []HostFunction, maxPages uint32) (Instance, error) {
+	inst := &fakeInstance{module: m, host: make(map[string]HostFunction), memory: &fakeMemory{data: make([]byte, PageSize)}}
+	for _, fn := range host {
+		inst.host[fn.Module+"."+fn.Name] = fn
+	}
+	return inst, nil
+}
+
+func (m *fakeModule) Close(ctx context.Context) error { return nil }
+
+type fakeInstance struct {
+	module *fakeModule
+	host   map[string]HostFunction
+	memory *fakeMemory
+	next   uint32
+}
+
+func (g *fakeInstance) Call(ctx context.Context, name string, params ...uint64) ([]uint64, error) {
+	switch name {
+	case "nim_alloc":
+		ptr := g.next
+		g.next += uint32(params[0])
+		for g.next > g.memory.Size() {
+			g.memory.data = append(g.memory.data, make([]byte, PageSize)...)
+		}
+		return []uint64{uint64(ptr)}, nil
+	case "nim_name":
+		return g.guestString(ctx, g.module.name)
+	case "nim_description":
+		return g.guestString(ctx, "Plugin "+g.module.name)
+	case "nim_schema":
+		return g.guestString(ctx, `{"type":"object","properties":{"amount":{"type":"number"}}}`)
+	case "nim_requires_confirmation":
+		return []uint64{1}, nil
+	case "nim_summary":
+		return g.guestString(ctx, "Run with {amount}")
+	case "nim_handle":
+		input, _ := g.memory.Read(uint32(params[0]), uint32(params[1]))
+		return g.guestString(ctx, g.module.handle(ctx, g, input))
+	}
+	return nil, fmt.Errorf("export %s not found", name)
+}
+
+func (g *fakeInstance) guestString(ctx context.Context, s string) ([]uint64, error) {
+	ptr, err := write(ctx, g, []byte(s))
+	if err != nil {
+		return nil, err
+	}
+	return []uint64{pack(ptr, uint32(len(s)))}, nil
+}
+
+// importFn calls a host function with strings written to guest memory.
+func (g *fakeInstance) importFn(ctx context.Context, name string, args ...string) string {
+	var params []uint64
+	for _, arg := range args {
+		ptr, _ := write(ctx, g, []byte(arg))
+		params = append(params, uint64(ptr), uint64(len(arg)))
+	}
+	results := g.host[HostModule+"."+name].Func(ctx, g, params)
+	if len(results) == 0 {
+		return ""
+	}
+	data, _ := read(g, results[0])
+	return string(data)
+}
+
+func (g *fakeInstance) Memory() Memory                  { return g.memory }
+func (g *fakeInstance) Close(ctx context.Context) error { return nil }
+
+type fakeMemory struct{ data []byte }
+
+func (m *fakeMemory) Read(offset, length uint32) ([]byte, bool) {
+	if uint64(offset)+uint64(length) > uint64(len(m.data)) {
+		return nil, false
+	}
+	return m.data[offset : offset+length], true
+}
+
+func (m *fakeMemory) Write(offset uint32, data []byte) bool {
+	if uint64(offset)+uint64(len(data)) > uint64(len(m.data)) {
+		return false
+	}
+	copy(m.data[offset:], data)
+	return true
+}
+
+func (m *fakeMemory) Size() uint32 { return uint32(len(m.data)) }
+
+type fakeExecutor struct {
+	core.ToolExecutor
+	requests []*core.ExecuteRequest
+}
+
+func (f *fakeExecutor) Execute(ctx context.Context, req *core.ExecuteRequest) (*core.ExecuteResponse, error) {
+	f.requests = append(f.requests, req)
+	return &core.ExecuteResponse{Success: true, Data: json.RawMessage(`{"amount":"120.50"}`)}, nil
+}
+
+func TestTool(t *testing.T) {
+	var logs bytes.Buffer
+	executor := &fakeExecutor{}
+	runtime := fakeRuntime{
+		"fee": func(ctx context.Context, g *fakeInstance, input []byte) string {
+			g.importFn(ctx, "log", "input "+string(input))
+			balance := g.importFn(ctx, "call_tool", "get_balance", `{"currency":"USD"}`)
+			denied := g.importFn(ctx, "call_tool", "send_money", `{}`)
+			return fmt.Sprintf(`{"data":{"balance":%s,"denied":%s}}`, balance, denied)
+		},
+		"broken": func(ctx context.Context, g *fakeInstance, input []byte) string {
+			return `{"error":"amount is required"}`
+		},
+	}
+	cfg := Config{Runtime: runtime, Executor: executor, Logger: log.New(&logs, "", 0)}
+
+	tool, err := New(context.Background(), "fee.wasm", []byte("fee"), cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if tool.Name() != "fee" || tool.Description() != "Plugin fee" || !tool.RequiresConfirmation() || tool.Schema()["type"] != "object" {
+		t.Errorf("metadata = %s %q %v %v", tool.Name(), tool.Description(), tool.RequiresConfirmation(), tool.Schema())
+	}
+	if got := tool.GetSummary(json.RawMessage(`{"amount":5}`)); got != "Run with 5" {
+		t.Errorf("summary = %q", got)
+	}
+
+	result, _ := tool.Execute(context.Background(), &core.ToolParams{UserID: "user-1", Input: json.RawMessage(`{"amount":5}`)})
+	data, _ := json.Marshal(result.Data)
+	want := `{"balance":{"data":{"amount":"120.50"}},"denied":{"error":"call_tool: send_money is not allowed"}}`
+	if !result.Success || string(data) != want {
+		t.Errorf("result = %+v, data = %s", result, data)
+	}
+	if len(executor.requests) != 1 || executor.requests[0].UserID != "user-1" || executor.requests[0].Tool != "get_balance" {
+		t.Errorf("requests = %+v", executor.requests)
+	}
+	if !strings.Contains(logs.String(), `plugin fee.wasm: input {"amount":5}`) {
+		t.Errorf("logs = %q", logs.String())
+	}
+
+	broken, _ := New(context.Background(), "broken.wasm", []byte("broken"), cfg)
+	result, _ = broken.Execute(context.Background(), &core.ToolParams{UserID: "user-1"})
+	if result.Success || result.Error != "amount is required" {
+		t.Errorf("result = %+v", result)
+	}
+
+	if _, err := New(context.Background(), "bad.wasm", []byte("bad"), cfg); err == nil {
+		t.Error("expected a compile error")
+	}
+}
+
+func TestLimits(t *testing.T) {
+	runtime := fakeRuntime{
+		"spin": func(ctx context.Context, g *fakeInstance, input []byte) string {
+			<-ctx.Done()
+			return `{}`
+		},
+		"hog": func(ctx context.Context, g *fakeInstance, input []byte) string {
+			write(ctx, g, make([]byte, 2*PageSize))
+			return `{}`
+		},
+	}
+	cfg := Config{Runtime: runtime, Timeout: 20 * time.Millisecond, MaxMemoryBytes: 2 * PageSize}
+
+	for name, want := range map[string]string{
+		"spin": "plugin exceeded the 20ms time limit",
+		"hog":  "plugin exceeded the 131072 byte memory limit",
+	} {
+		tool, err := New(context.Background(), name+".wasm", []byte(name), cfg)
+		if err != nil {
+			t.Fatalf("%s: New: %v", name, err)
+		}
+		result, _ := tool.Execute(context.Background(), &core.ToolParams{UserID: "user-1"})
+		if result.Success || result.Error != want {
+			t.Errorf("%s: result = %+v, want %q", name, result, want)
+		}
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a", "b"} {
+		os.WriteFile(filepath.Join(dir, name+".wasm"), []byte(name), 0o600)
+	}
+	echo := func(ctx context.Context, g *fakeInstance, input []byte) string {
+		return fmt.Sprintf(`{"data":%s}`, input)
+	}
+	registry := engine.NewToolRegistry()
+	tools, err := LoadDir(context.Background(), dir, registry, Config{Runtime: fakeRuntime{"a": echo, "b": echo}})
+	if err != nil {
+		t.Fatalf("LoadDir: %v", err)
+	}
+	names := registry.List()
+	sort.Strings(names)
+	if len(tools) != 2 || fmt.Sprint(names) != "[a b]" {
+		t.Errorf("registry = %v", names)
+	}
+
+	// A plugin can't replace a tool registered elsewhere
+	registry = engine.NewToolRegistry()
+	builtin := core.NewBaseTool(core.ToolDefinition{ToolName: "b"}, nil)
+	registry.Register(builtin)
+	if _, err := LoadDir(context.Background(), dir, registry, Config{Runtime: fakeRuntime{"a": echo, "b": echo}}); err == nil || !strings.Contains(err.Error(), "tool b is already registered") {
+		t.Errorf("expected a shadowing error, got %v", err)
+	}
+	if tool, _ := registry.Get("b"); tool != builtin || len(registry.List()) != 1 {
+		t.Errorf("registry = %v", registry.List())
+	}
+}
@@ -0,0 +1,148 @@
+package wasm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// wazeroRuntime is a Runtime backed by wazero, a pure-Go WebAssembly
+// engine.
+type wazeroRuntime struct {
+	cache wazero.CompilationCache
+}
+
+// NewWazeroRuntime returns a Runtime backed by wazero, a pure-Go
+// WebAssembly engine that needs no cgo. It is the default Runtime.
+//
+// Each instance runs in its own wazero runtime, which enforces the memory
+// limit and stops calls when their context is done. Compiled code is
+// shared through a compilation cache, so instantiating is cheap.
+func NewWazeroRuntime() Runtime {
+	return &wazeroRuntime{cache: wazero.NewCompilationCache()}
+}
+
+func (r *wazeroRuntime) config(maxPages uint32) wazero.RuntimeConfig {
+	return wazero.NewRuntimeConfig().
+		WithCompilationCache(r.cache).
+		WithCloseOnContextDone(true).
+		WithMemoryLimitPages(maxPages)
+}
+
+// Compile validates the module and warms the compilation cache.
+func (r *wazeroRuntime) Compile(ctx context.Context, wasm []byte) (Module, error) {
+	rt := wazero.NewRuntimeWithConfig(ctx, r.config(maxWasmPages))
+	defer rt.Close(ctx)
+	compiled, err := rt.CompileModule(ctx, wasm)
+	if err != nil {
+		return nil, err
+	}
+	compiled.Close(ctx)
+	return &wazeroModule{runtime: r, wasm: wasm}, nil
+}
+
+// maxWasmPages is the most pages a 32-bit memory can have.
+const maxWasmPages = 65536
+
+type wazeroModule struct {
+	runtime *wazeroRuntime
+	wasm    []byte
+}
+
+// Instantiate links the host functions and instantiates the module. The
+// module's "_initialize" export, if any, runs first, as for a WASI reactor.
+func (m *wazeroModule) Instantiate(ctx context.Context, host []HostFunction, maxPages uint32) (Instance, error) {
+	if maxPages == 0 {
+		return nil, errors.New("memory limit is below one page")
+	}
+	rt := wazero.NewRuntimeWithConfig(ctx, m.runtime.config(maxPages))
+
+	builders := make(map[string]wazero.HostModuleBuilder)
+	for _, fn := range host {
+		builder, ok := builders[fn.Module]
+		if !ok {
+			builder = rt.NewHostModuleBuilder(fn.Module)
+			builders[fn.Module] = builder
+		}
+		fn := fn
+		builder.NewFunctionBuilder().
+			WithGoModuleFunction(api.GoModuleFunc(func(ctx context.Context, mod api.Module, stack []uint64) {
+				copy(stack, fn.Func(ctx, newWazeroInstance(nil, mod), stack[:len(fn.Params)]))
+			}), valueTypes(fn.Params), valueTypes(fn.Results)).
+			Export(fn.Name)
+	}
+	for name, builder := range builders {
+		if _, err := builder.Instantiate(ctx); err != nil {
+			rt.Close(ctx)
+			return nil, fmt.Errorf("host module %s: %w", name, err)
+		}
+	}
+
+	compiled, err := rt.CompileModule(ctx, m.wasm)
+	if err != nil {
+		rt.Close(ctx)
+		return nil, err
+	}
+	mod, err := rt.InstantiateModule(ctx, compiled, wazero.NewModuleConfig().WithStartFunctions("_initialize"))
+	if err != nil {
+		rt.Close(ctx)
+		return nil, err
+	}
+	return newWazeroInstance(rt, mod), nil
+}
+
+// Close is a no-op: compiled code lives in the runtime's cache.
+func (m *wazeroModule) Close(ctx context.Context) error {
+	return nil
+}
+
+type wazeroInstance struct {
+	runtime wazero.Runtime // nil for the view host functions get
+	mod     api.Module
+	memory  Memory
+}
+
+func newWazeroInstance(rt wazero.Runtime, mod api.Module) *wazeroInstance {
+	var memory Memory = noMemory{}
+	if mem := mod.ExportedMemory("memory"); mem != nil {
+		memory = mem
+	}
+	return &wazeroInstance{runtime: rt, mod: mod, memory: memory}
+}
+
+func (i *wazeroInstance) Call(ctx context.Context, name string, params ...uint64) ([]uint64, error) {
+	fn := i.mod.ExportedFunction(name)
+	if fn == nil {
+		return nil, fmt.Errorf("export %s not found", name)
+	}
+	return fn.Call(ctx, params...)
+}
+
+func (i *wazeroInstance) Memory() Memory {
+	return i.memory
+}
+
+func (i *wazeroInstance) Close(ctx context.Context) error {
+	if i.runtime == nil {
+		return nil
+	}
+	return i.runtime.Close(ctx)
+}
+
+// noMemory stands in for a module that exports no memory.
+type noMemory struct{}
+
+func (noMemory) Read(offset, length uint32) ([]byte, bool) { return nil, false }
+func (noMemory) Write(offset uint32, data []byte) bool     { return false }
+func (noMemory) Size() uint32                              { return 0 }
+
+func valueTypes(types []ValueType) []api.ValueType {
+	out := make([]api.ValueType, len(types))
+	for i, t := range types {
+		out[i] = api.ValueType(t)
+	}
+	return out
+}
@@ -0,0 +1,185 @@
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+// Strings in the test module's data segment, by offset.
+var pluginData = []struct {
+	offset uint32
+	value  string
+}{
+	{16, "wasm_fee"},
+	{32, "Works out the fee on a transfer"},
+	{96, `{"type":"object"}`},
+	{128, "get_balance"},
+	{144, "{}"},
+}
+
+// pluginModule assembles a plugin that logs its input and returns the
+// result of call_tool("get_balance", "{}"), or loops forever if spin is set.
+// Its memory starts at minPages.
+func pluginModule(minPages uint64, spin bool) []byte {
+	const (
+		i32, i64                       = 0x7f, 0x7e
+		localGet, globalGet, globalSet = 0x20, 0x23, 0x24
+		i32Const, i64Const, i32Add     = 0x41, 0x42, 0x6a
+		call, loop, br, unreachable    = 0x10, 0x03, 0x0c, 0x00
+		end                            = 0x0b
+	)
+	funcType := func(params, results []byte) []byte {
+		return cat([]byte{0x60}, vec(params), vec(results))
+	}
+	packed := func(i int) []byte {
+		d := pluginData[i]
+		return cat([]byte{i64Const}, sleb(int64(pack(d.offset, uint32(len(d.value))))), []byte{end})
+	}
+	export := func(name string, kind byte, index uint64) []byte {
+		return cat(str(name), []byte{kind}, uleb(index))
+	}
+
+	handle := cat(
+		[]byte{localGet, 0, localGet, 1, call, 0},
+		[]byte{i32Const}, sleb(128), []byte{i32Const}, sleb(11),
+		[]byte{i32Const}, sleb(144), []byte{i32Const}, sleb(2),
+		[]byte{call, 1, end},
+	)
+	if spin {
+		handle = []byte{loop, 0x40, br, 0, end, unreachable, end}
+	}
+
+	var data [][]byte
+	for _, d := range pluginData {
+		data = append(data, cat([]byte{0, i32Const}, sleb(int64(d.offset)), []byte{end}, str(d.value)))
+	}
+
+	return cat(
+		[]byte("\x00asm\x01\x00\x00\x00"),
+		section(1, // types
+			funcType([]byte{i32, i32}, nil),
+			funcType([]byte{i32, i32, i32, i32}, []byte{i64}),
+			funcType([]byte{i32}, []byte{i32}),
+			funcType(nil, []byte{i64}),
+			funcType(nil, []byte{i32}),
+			funcType([]byte{i32, i32}, []byte{i64}),
+		),
+		section(2, // imports
+			cat(str(HostModule), str("log"), []byte{0, 0}),
+			cat(str(HostModule), str("call_tool"), []byte{0, 1}),
+		),
+		section(3, []byte{2}, []byte{3}, []byte{3}, []byte{3}, []byte{4}, []byte{5}), // functions
+		section(5, cat([]byte{0}, uleb(minPages))),                                   // memory
+		section(6, cat([]byte{i32, 1, i32Const}, sleb(1024), []byte{end})),           // bump allocator
+		section(7, // exports
+			export("memory", 2, 0),
+			export("nim_alloc", 0, 2),
+			export("nim_name", 0, 3),
+			export("nim_description", 0, 4),
+			export("nim_schema", 0, 5),
+			export("nim_requires_confirmation", 0, 6),
+			export("nim_handle", 0, 7),
+		),
+		section(10, // code
+			body(globalGet, 0, globalGet, 0, localGet, 0, i32Add, globalSet, 0, end),
+			body(packed(0)...),
+			body(packed(1)...),
+			body(packed(2)...),
+			body(i32Const, 0, end),
+			body(handle...),
+		),
+		section(11, data...),
+	)
+}
+
+func cat(parts ...[]byte) []byte { return bytes.Join(parts, nil) }
+
+func str(s string) []byte { return cat(uleb(uint64(len(s))), []byte(s)) }
+
+func vec(items []byte) []byte { return cat(uleb(uint64(len(items))), items) }
+
+func body(code ...byte) []byte {
+	// No locals
+	return cat(uleb(uint64(len(code)+1)), []byte{0}, code)
+}
+
+func section(id byte, items ...[]byte) []byte {
+	content := cat(uleb(uint64(len(items))), cat(items...))
+	return cat([]byte{id}, uleb(uint64(len(content))), content)
+}
+
+func uleb(v uint64) []byte {
+	var out []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func sleb(v int64) []byte {
+	var out []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && b&0x40 == 0) || (v == -1 && b&0x40 != 0) {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func TestWazeroRuntime(t *testing.T) {
+	ctx := context.Background()
+	var logs bytes.Buffer
+	executor := &fakeExecutor{}
+	cfg := Config{Executor: executor, Logger: log.New(&logs, "", 0)}
+
+	tool, err := New(ctx, "fee.wasm", pluginModule(1, false), cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer tool.Close(ctx)
+	if tool.Name() != "wasm_fee" || tool.Description() != "Works out the fee on a transfer" || tool.RequiresConfirmation() || tool.Schema()["type"] != "object" {
+		t.Errorf("metadata = %s %q %v %v", tool.Name(), tool.Description(), tool.RequiresConfirmation(), tool.Schema())
+	}
+
+	result, _ := tool.Execute(ctx, &core.ToolParams{UserID: "user-1", Input: json.RawMessage(`{"amount":5}`)})
+	data, _ := json.Marshal(result.Data)
+	if !result.Success || string(data) != `{"amount":"120.50"}` {
+		t.Errorf("result = %+v, data = %s", result, data)
+	}
+	if len(executor.requests) != 1 || executor.requests[0].Tool != "get_balance" || string(executor.requests[0].Input) != "{}" {
+		t.Errorf("requests = %+v", executor.requests)
+	}
+	if !strings.Contains(logs.String(), `plugin fee.wasm: {"amount":5}`) {
+		t.Errorf("logs = %q", logs.String())
+	}
+
+	spin, err := New(ctx, "spin.wasm", pluginModule(1, true), Config{Timeout: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer spin.Close(ctx)
+	result, _ = spin.Execute(ctx, &core.ToolParams{UserID: "user-1"})
+	if result.Success || result.Error != "plugin exceeded the 20ms time limit" {
+		t.Errorf("result = %+v", result)
+	}
+
+	if _, err := New(ctx, "big.wasm", pluginModule(4, false), Config{MaxMemoryBytes: 2 * PageSize}); err == nil {
+		t.Error("expected an error for a module whose memory starts above the limit")
+	}
+	if _, err := New(ctx, "bad.wasm", []byte("bad"), Config{}); err == nil {
+		t.Error("expected a compile error")
+	}
+}